@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/interop"
+	"FedeAbella/mtgdb/internal/sqlc"
+	"FedeAbella/mtgdb/internal/valuation"
+)
+
+const collectionUsage = `usage:
+  mtgdb collection create NAME
+  mtgdb collection list [NAME]
+  mtgdb collection owner NAME [USER]
+  mtgdb collection add [flags] NAME SCRYFALL_ID
+  mtgdb collection remove [-quantity N] ITEM_ID
+  mtgdb collection move [-quantity N] ITEM_ID NAME
+  mtgdb collection export [-to FORMAT] NAME
+  mtgdb collection import -from VENDOR [-dry-run] NAME FILE
+  mtgdb collection value [-top N] [-since YYYY-MM-DD] [-output OUTPUT] NAME
+  mtgdb collection completion [-variants] SET [NAME]`
+
+var errStoreWithoutCollections = errors.New("the db backend doesn't support collections")
+
+func openCollections(ctx context.Context) (*db.Collections, func(), error) {
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		return nil, nil, err
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	collectionStore, ok := store.(db.CollectionStore)
+	if !ok {
+		closeStore()
+		return nil, nil, errStoreWithoutCollections
+	}
+
+	return &db.Collections{Store: collectionStore}, closeStore, nil
+}
+
+// parseCents reads a decimal amount like 1.50 as cents.
+func parseCents(value string) (pgtype.Int8, error) {
+	if value == "" {
+		return pgtype.Int8{}, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return pgtype.Int8{}, fmt.Errorf("invalid price %q", value)
+	}
+
+	return pgtype.Int8{Int64: int64(math.Round(amount * 100)), Valid: true}, nil
+}
+
+func parseDate(value string) (pgtype.Date, error) {
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+
+	return pgtype.Date{Time: date, Valid: true}, nil
+}
+
+func parseItemID(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid item id %q", value)
+	}
+
+	return id, nil
+}
+
+func formatCents(cents pgtype.Int8, currency pgtype.Text) string {
+	if !cents.Valid {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d.%02d %s", cents.Int64/100, cents.Int64%100, currency.String)
+}
+
+func runCollection(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, collectionUsage)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("collection "+args[0], flag.ExitOnError)
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	quantity := flags.Int("quantity", 0, "number of copies, all of them if 0 on remove and move, 1 on add")
+	finish := flags.String("finish", "nonfoil", "finish of the copies added: "+strings.Join(db.Finishes, ", "))
+	condition := flags.String("condition", "NM", "condition of the copies added: "+strings.Join(db.Conditions, ", "))
+	language := flags.String("lang", "", "language of the copies added, the printing's if empty")
+	price := flags.String("price", "", "price paid per copy, as in 1.50")
+	currency := flags.String("currency", "USD", "currency of the price paid: "+strings.Join(db.Currencies, ", "))
+	acquired := flags.String("acquired", "", "date the copies were acquired, as YYYY-MM-DD")
+	to := flags.String("to", "csv", "format of the export: "+strings.Join(interop.ExportFormats(), ", "))
+	from := flags.String("from", "", "vendor of the csv imported: "+strings.Join(interop.Vendors(), ", "))
+	dryRun := flags.Bool("dry-run", false, "report how the rows match without importing them")
+	top := flags.Int("top", 10, "number of most valuable cards reported")
+	since := flags.String("since", "", "date to report the change in value since, as YYYY-MM-DD")
+	output := flags.String("output", "text", "output of the report: "+strings.Join(valuation.Outputs(), ", "))
+	variants := flags.Bool("variants", false, "count variations and boosterfun printings towards completion")
+	_ = flags.Parse(args[1:])
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	collections, closeStore, err := openCollections(ctx)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	switch {
+	case args[0] == "create" && flags.NArg() == 1:
+		_, err = collections.Create(ctx, flags.Arg(0))
+	case args[0] == "list" && flags.NArg() == 0:
+		err = listCollections(ctx, collections)
+	case args[0] == "list" && flags.NArg() == 1:
+		err = listCollectionItems(ctx, collections, flags.Arg(0))
+	case args[0] == "owner" && (flags.NArg() == 1 || flags.NArg() == 2):
+		err = collections.SetOwner(ctx, flags.Arg(0), flags.Arg(1))
+	case args[0] == "add" && flags.NArg() == 2:
+		item := db.NewCollectionItem{
+			Quantity:     int32(max(*quantity, 1)),
+			Finish:       *finish,
+			Condition:    strings.ToUpper(*condition),
+			LanguageCode: *language,
+		}
+		err = addCollectionItem(ctx, collections, flags.Arg(0), flags.Arg(1), item, *price, *currency, *acquired)
+	case args[0] == "remove" && flags.NArg() == 1:
+		var itemID int64
+		if itemID, err = parseItemID(flags.Arg(0)); err == nil {
+			err = collections.Remove(ctx, itemID, int32(*quantity))
+		}
+	case args[0] == "move" && flags.NArg() == 2:
+		var itemID int64
+		if itemID, err = parseItemID(flags.Arg(0)); err == nil {
+			_, err = collections.Move(ctx, itemID, flags.Arg(1), int32(*quantity))
+		}
+	case args[0] == "export" && flags.NArg() == 1:
+		var items []sqlc.GetCollectionItemsWithCardsRow
+		if items, err = collections.List(ctx, flags.Arg(0)); err == nil {
+			err = interop.Export(os.Stdout, *to, interop.CollectionEntries(items))
+		}
+	case args[0] == "import" && flags.NArg() == 2:
+		return importCollection(ctx, collections, flags.Arg(0), flags.Arg(1), *from, *dryRun)
+	case args[0] == "value" && flags.NArg() == 1:
+		var sinceDate pgtype.Date
+		if sinceDate, err = parseDate(*since); err == nil {
+			var report valuation.Report
+			if report, err = collections.Value(ctx, flags.Arg(0), max(*top, 0), sinceDate); err == nil {
+				err = valuation.Write(os.Stdout, *output, report)
+			}
+		}
+	case args[0] == "completion" && (flags.NArg() == 1 || flags.NArg() == 2):
+		err = printCompletion(ctx, collections, strings.ToLower(flags.Arg(0)), flags.Arg(1), *variants)
+	default:
+		fmt.Fprintln(os.Stderr, collectionUsage)
+		return exitUsage
+	}
+
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func addCollectionItem(
+	ctx context.Context,
+	collections *db.Collections,
+	name string,
+	scryfallID string,
+	item db.NewCollectionItem,
+	price string,
+	currency string,
+	acquired string,
+) error {
+	id, err := uuid.Parse(scryfallID)
+	if err != nil {
+		return fmt.Errorf("invalid scryfall id %q", scryfallID)
+	}
+	item.ScryfallID = pgtype.UUID{Bytes: id, Valid: true}
+
+	if item.AcquiredPriceCents, err = parseCents(price); err != nil {
+		return err
+	}
+	if item.AcquiredPriceCents.Valid {
+		item.AcquiredCurrency = pgtype.Text{String: strings.ToUpper(currency), Valid: true}
+	}
+	if item.AcquiredOn, err = parseDate(acquired); err != nil {
+		return err
+	}
+
+	added, err := collections.Add(ctx, name, item)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("added item %d\n", added.ID)
+	return nil
+}
+
+func listCollections(ctx context.Context, collections *db.Collections) error {
+	all, err := collections.Store.GetAllCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range all {
+		fmt.Println(collection.Name)
+	}
+
+	return nil
+}
+
+func listCollectionItems(ctx context.Context, collections *db.Collections, name string) error {
+	items, err := collections.List(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tQTY\tSET\tNUMBER\tLANG\tFINISH\tCOND\tPAID\tNAME")
+	for _, item := range items {
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			item.CollectionItem.ID,
+			item.CollectionItem.Quantity,
+			item.SetCode,
+			item.Card.CollectorNumber,
+			item.CollectionItem.LanguageCode,
+			item.CollectionItem.Finish,
+			item.CollectionItem.Condition,
+			formatCents(item.CollectionItem.AcquiredPriceCents, item.CollectionItem.AcquiredCurrency),
+			item.Card.Name,
+		)
+	}
+
+	return w.Flush()
+}
+
+// importCollection adds the vendor export at path, or stdin if it is "-", to
+// the collection, creating the collection first if it doesn't exist.
+func importCollection(
+	ctx context.Context,
+	collections *db.Collections,
+	name string,
+	path string,
+	vendor string,
+	dryRun bool,
+) int {
+	file, err := openInput(path)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+	defer file.Close()
+
+	export, err := interop.ReadCollection(file, vendor)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	if !dryRun {
+		if _, err := collections.Create(ctx, name); err != nil && !errors.Is(err, db.ErrCollectionExists) {
+			log.Println(err)
+			return exitCode(err)
+		}
+	}
+
+	matches, err := interop.ImportCollection(ctx, collections, name, export, dryRun)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tQTY\tSET\tNUMBER\tLANG\tFINISH\tCOND\tMATCH\tNAME")
+	copies := 0
+	for _, match := range matches {
+		if !match.Matched() {
+			continue
+		}
+
+		card := match.Resolution.Card
+		language := match.Row.Ref.LanguageCode
+		if language == "" {
+			language = card.Card.LanguageCode
+		}
+		copies += match.Row.Quantity
+
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			match.Row.Number,
+			match.Row.Quantity,
+			card.SetCode,
+			card.Card.CollectorNumber,
+			language,
+			match.Row.Finish,
+			match.Row.Condition,
+			match.Resolution.Method,
+			card.Card.Name,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	unmatched := len(export.Invalid)
+	for _, invalid := range export.Invalid {
+		fmt.Printf("row %d: %s\n", invalid.Number, invalid.Reason)
+	}
+	for _, match := range matches {
+		ref := match.Row.Ref
+		switch {
+		case match.Matched():
+			continue
+		case match.Resolution.Ambiguous():
+			fmt.Printf(
+				"row %d: %q is ambiguous, it could be %s\n",
+				match.Row.Number,
+				ref.Name,
+				strings.Join(match.Resolution.Candidates, ", "),
+			)
+		case match.Resolution.Resolved():
+			set := ref.SetCode
+			if set == "" {
+				set = ref.SetName
+			}
+			fmt.Printf(
+				"row %d: no printing of %s matches set %q number %q\n",
+				match.Row.Number,
+				match.Resolution.Card.Card.Name,
+				set,
+				ref.CollectorNumber,
+			)
+		default:
+			fmt.Printf("row %d: no card matches %q\n", match.Row.Number, ref.Name)
+		}
+		unmatched++
+	}
+
+	verb := "imported"
+	if dryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%s %d copies from %d rows, %d rows unmatched\n", verb, copies, len(matches)+len(export.Invalid)-unmatched, unmatched)
+
+	if unmatched > 0 {
+		return exitUnresolvedCards
+	}
+
+	return exitOK
+}
+
+func printCompletion(ctx context.Context, collections *db.Collections, setCode string, name string, variants bool) error {
+	report, err := collections.Completion(ctx, setCode, name, variants)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"%s %s: %d of %d owned (%.1f%%)\n\n",
+		report.SetCode,
+		report.SetName,
+		report.Cards.Owned,
+		report.Cards.Total,
+		report.Cards.Percent(),
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RARITY\tOWNED\tTOTAL\t%")
+	for _, rarity := range report.ByRarity {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\n", rarity.Rarity, rarity.Owned, rarity.Total, rarity.Percent())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Fprintln(w, "LANG\tOWNED\tTOTAL\t%")
+	for _, language := range report.ByLanguage {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\n", language.LanguageCode, language.Owned, language.Total, language.Percent())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(report.Missing) > 0 {
+		fmt.Printf("\nmissing: %s\n", strings.Join(report.Missing, ", "))
+	}
+
+	return nil
+}
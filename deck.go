@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/interop"
+)
+
+const deckUsage = `usage:
+  mtgdb deck create -format FORMAT NAME
+  mtgdb deck list [NAME]
+  mtgdb deck add [-zone ZONE] [-quantity N] [-oracle] NAME ID
+  mtgdb deck remove [-quantity N] NAME ENTRY_ID
+  mtgdb deck validate NAME
+  mtgdb deck import [-format FORMAT] [-dry-run] NAME FILE
+  mtgdb deck export [-to FORMAT] NAME`
+
+var errStoreWithoutDecks = errors.New("the db backend doesn't support decks")
+
+func openDecks(ctx context.Context) (*db.Decks, func(), error) {
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		return nil, nil, err
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deckStore, ok := store.(db.DeckStore)
+	if !ok {
+		closeStore()
+		return nil, nil, errStoreWithoutDecks
+	}
+
+	return &db.Decks{Store: deckStore}, closeStore, nil
+}
+
+func runDeck(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, deckUsage)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("deck "+args[0], flag.ExitOnError)
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	format := flags.String("format", "", "format of the deck created: "+strings.Join(deck.Formats(), ", "))
+	zone := flags.String("zone", deck.ZoneMain, "zone of the card added: "+strings.Join(deck.Zones, ", "))
+	quantity := flags.Int("quantity", 0, "number of copies, all of them if 0 on remove, 1 on add")
+	oracle := flags.Bool("oracle", false, "add the card by oracle id instead of a specific printing")
+	dryRun := flags.Bool("dry-run", false, "report how the decklist resolves without importing it")
+	to := flags.String("to", "arena", "format of the export: "+strings.Join(interop.ExportFormats(), ", "))
+	_ = flags.Parse(args[1:])
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	decks, closeStore, err := openDecks(ctx)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	switch {
+	case args[0] == "create" && flags.NArg() == 1:
+		_, err = decks.Create(ctx, flags.Arg(0), *format)
+	case args[0] == "list" && flags.NArg() == 0:
+		err = listDecks(ctx, decks)
+	case args[0] == "list" && flags.NArg() == 1:
+		err = listDeckEntries(ctx, decks, flags.Arg(0))
+	case args[0] == "add" && flags.NArg() == 2:
+		err = addDeckCard(ctx, decks, flags.Arg(0), flags.Arg(1), *zone, int32(max(*quantity, 1)), *oracle)
+	case args[0] == "remove" && flags.NArg() == 2:
+		var entryID int64
+		if entryID, err = parseItemID(flags.Arg(1)); err == nil {
+			err = decks.Remove(ctx, flags.Arg(0), entryID, int32(*quantity))
+		}
+	case args[0] == "validate" && flags.NArg() == 1:
+		return validateDeck(ctx, decks, flags.Arg(0))
+	case args[0] == "import" && flags.NArg() == 2:
+		return importDecklist(ctx, decks, flags.Arg(0), flags.Arg(1), *format, *dryRun)
+	case args[0] == "export" && flags.NArg() == 1:
+		var entries []db.DeckEntry
+		if _, entries, err = decks.List(ctx, flags.Arg(0)); err == nil {
+			err = interop.Export(os.Stdout, *to, interop.DeckEntries(entries))
+		}
+	default:
+		fmt.Fprintln(os.Stderr, deckUsage)
+		return exitUsage
+	}
+
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func addDeckCard(
+	ctx context.Context,
+	decks *db.Decks,
+	name string,
+	id string,
+	zone string,
+	quantity int32,
+	oracle bool,
+) error {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid id %q", id)
+	}
+
+	card := db.NewDeckCard{Zone: strings.ToLower(zone), Quantity: quantity}
+	if oracle {
+		card.ScryfallOracleID = pgtype.UUID{Bytes: parsed, Valid: true}
+	} else {
+		card.ScryfallID = pgtype.UUID{Bytes: parsed, Valid: true}
+	}
+
+	added, err := decks.Add(ctx, name, card)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("added entry %d\n", added.ID)
+	return nil
+}
+
+func listDecks(ctx context.Context, decks *db.Decks) error {
+	all, err := decks.Store.GetAllDecks(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFORMAT")
+	for _, stored := range all {
+		fmt.Fprintf(w, "%s\t%s\n", stored.Name, stored.Format)
+	}
+
+	return w.Flush()
+}
+
+func listDeckEntries(ctx context.Context, decks *db.Decks, name string) error {
+	_, entries, err := decks.List(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tZONE\tQTY\tSET\tNUMBER\tNAME")
+	for _, entry := range entries {
+		// Entries naming only the card print no printing
+		setCode, collectorNumber := "-", "-"
+		if entry.DeckCard.ScryfallID.Valid {
+			setCode, collectorNumber = entry.Card.SetCode, entry.Card.Card.CollectorNumber
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%d\t%s\t%s\t%s\n",
+			entry.DeckCard.ID,
+			entry.DeckCard.Zone,
+			entry.DeckCard.Quantity,
+			setCode,
+			collectorNumber,
+			entry.Card.Card.Name,
+		)
+	}
+
+	return w.Flush()
+}
+
+func validateDeck(ctx context.Context, decks *db.Decks, name string) int {
+	violations, err := decks.Validate(ctx, name)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	if len(violations) == 0 {
+		fmt.Printf("%s is legal\n", name)
+		return exitOK
+	}
+
+	for _, violation := range violations {
+		fmt.Println(violation)
+	}
+
+	return exitInvalidDeck
+}
+
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(path)
+}
+
+// importDecklist adds the decklist at path, or stdin if it is "-", to the
+// deck, creating the deck first if a format is given and it doesn't exist.
+func importDecklist(ctx context.Context, decks *db.Decks, name string, path string, format string, dryRun bool) int {
+	file, err := openInput(path)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+	defer file.Close()
+
+	decklist, err := deck.ParseDecklist(file)
+	if err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	if format != "" && !dryRun {
+		if _, err := decks.Create(ctx, name, format); err != nil && !errors.Is(err, db.ErrDeckExists) {
+			log.Println(err)
+			return exitCode(err)
+		}
+	}
+
+	resolutions, err := decks.Import(ctx, name, decklist, dryRun)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tZONE\tQTY\tMATCH\tSET\tNUMBER\tNAME")
+	unresolved := len(decklist.Invalid)
+	for _, resolution := range resolutions {
+		if !resolution.Resolved() {
+			unresolved++
+			continue
+		}
+
+		// Cards matched by name are imported without a printing
+		setCode, collectorNumber := "-", "-"
+		if resolution.Method != db.MatchName {
+			setCode, collectorNumber = resolution.Card.SetCode, resolution.Card.Card.CollectorNumber
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			resolution.Line.Number,
+			resolution.Line.Zone,
+			resolution.Line.Quantity,
+			resolution.Method,
+			setCode,
+			collectorNumber,
+			resolution.Card.Card.Name,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		log.Println(err)
+		return exitError
+	}
+
+	for _, line := range decklist.Invalid {
+		fmt.Printf("line %d: can't parse %q\n", line.Number, line.Text)
+	}
+	for _, resolution := range resolutions {
+		switch {
+		case resolution.Ambiguous():
+			fmt.Printf(
+				"line %d: %q is ambiguous, it could be %s\n",
+				resolution.Line.Number,
+				resolution.Line.Name,
+				strings.Join(resolution.Candidates, ", "),
+			)
+		case !resolution.Resolved():
+			fmt.Printf("line %d: no card matches %q\n", resolution.Line.Number, resolution.Line.Name)
+		}
+	}
+
+	if unresolved > 0 {
+		return exitUnresolvedCards
+	}
+
+	return exitOK
+}
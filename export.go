@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/export"
+)
+
+const exportUsage = `usage:
+  mtgdb export sqlite [-include TABLES] [-raw] FILE
+  mtgdb export jsonl|parquet [-set CODE] [-lang LANG] [-updated-since YYYY-MM-DD] FILE
+
+jsonl and parquet export one row per card, to stdout if FILE is -`
+
+func runExport(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("export "+args[0], flag.ExitOnError)
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	include := flags.String("include", "", "comma separated optional tables to export: "+strings.Join(export.Tables, ", "))
+	raw := flags.Bool("raw", false, "include the raw scryfall json of each card")
+	setCode := flags.String("set", "", "only export the cards of the set with this code")
+	language := flags.String("lang", "", "only export the cards in this language")
+	updatedSince := flags.String("updated-since", "", "only export the cards updated since this date, as YYYY-MM-DD")
+	_ = flags.Parse(args[1:])
+
+	isCards := slices.Contains(export.Formats(), args[0])
+	if (args[0] != "sqlite" && !isCards) || flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		return exitUsage
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{})
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	if isCards {
+		var since pgtype.Date
+		if since, err = parseDate(*updatedSince); err == nil {
+			filter := db.CardFilter{
+				SetCode:      strings.ToLower(*setCode),
+				LanguageCode: strings.ToLower(*language),
+				UpdatedSince: pgtype.Timestamp{Time: since.Time, Valid: since.Valid},
+			}
+			err = exportCards(ctx, store, flags.Arg(0), args[0], filter)
+		}
+	} else {
+		err = exportSQLite(ctx, store, flags.Arg(0), *include, *raw)
+	}
+
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func exportSQLite(ctx context.Context, store db.Store, path string, include string, raw bool) error {
+	options := export.SQLiteOptions{Raw: raw}
+	for _, table := range strings.Split(include, ",") {
+		if table = strings.ToLower(strings.TrimSpace(table)); table != "" {
+			options.Tables = append(options.Tables, table)
+		}
+	}
+
+	summary, err := export.SQLite(ctx, store, path, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %d sets and %d cards to %s\n", summary.Sets, summary.Cards, path)
+	return nil
+}
+
+func exportCards(ctx context.Context, store db.Store, path string, format string, filter db.CardFilter) error {
+	if path == "-" {
+		_, err := export.Cards(ctx, store, os.Stdout, format, filter)
+		return err
+	}
+
+	count, err := export.CardsFile(ctx, store, path, format, filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %d cards to %s\n", count, path)
+	return nil
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"FedeAbella/mtgdb/internal/search"
+)
+
+const (
+	defaultSuggestions = 10
+	maxSuggestions     = 50
+)
+
+type Suggestion struct {
+	Name     string `json:"name"`
+	Language string `json:"lang"`
+	OracleID string `json:"oracle_id"`
+}
+
+type AutocompleteResponse struct {
+	Data []Suggestion `json:"data"`
+}
+
+// RefreshNames rebuilds the autocomplete index from the store. Requests keep
+// using the previous index until the new one is swapped in.
+func (s *Server) RefreshNames(ctx context.Context) error {
+	// Read before the names, so a sync landing in between is picked up by the
+	// next check instead of missed
+	version, err := s.store.GetCardsVersion(ctx)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	rows, err := s.store.GetCardNames(ctx)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	names := make([]search.IndexedName, 0, len(rows))
+	for _, row := range rows {
+		oracleID := uuid.UUID(row.ScryfallOracleID.Bytes)
+		names = append(names, search.IndexedName{Name: row.Name, Language: "en", OracleID: oracleID})
+		if row.SpanishName.Valid {
+			names = append(names, search.IndexedName{Name: row.SpanishName.String, Language: "es", OracleID: oracleID})
+		}
+	}
+
+	s.names.Store(search.NewNameIndex(names))
+	s.namesVersion = version
+
+	return nil
+}
+
+// WatchSyncs refreshes the autocomplete index every interval in which a sync
+// wrote cards, until ctx is done.
+func (s *Server) WatchSyncs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		version, err := s.store.GetCardsVersion(ctx)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if version == s.namesVersion {
+			continue
+		}
+
+		if err = s.RefreshNames(ctx); err != nil {
+			continue
+		}
+		log.Printf("autocomplete index refreshed with %d names", s.names.Load().Len())
+	}
+}
+
+func (s *Server) handleAutocomplete(w http.ResponseWriter, r *http.Request) {
+	names := s.names.Load()
+	if names == nil {
+		writeError(w, r, http.StatusServiceUnavailable, errors.New("autocomplete index not built yet"))
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, errors.New("missing q parameter"))
+		return
+	}
+
+	limit := defaultSuggestions
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxSuggestions {
+			err = fmt.Errorf("invalid limit %q, expected an integer between 1 and %d", value, maxSuggestions)
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	suggestions := []Suggestion{}
+	for _, name := range names.Complete(query, limit) {
+		suggestions = append(suggestions, Suggestion{
+			Name:     name.Name,
+			Language: name.Language,
+			OracleID: name.OracleID.String(),
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, AutocompleteResponse{Data: suggestions})
+}
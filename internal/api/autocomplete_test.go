@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/dbtest"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func autocomplete(t *testing.T, server *Server, path string) (int, []Suggestion) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+
+	var response AutocompleteResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding response failed with error %v", err)
+	}
+
+	return recorder.Code, response.Data
+}
+
+func Test_Autocomplete(t *testing.T) {
+	ctx := context.Background()
+	store := dbtest.NewSqliteStore(t)
+	seedTestCards(t, store)
+	server := NewServer(store)
+
+	if status, _ := autocomplete(t, server, "/autocomplete?q=light"); status != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d before the index is built, got %d", http.StatusServiceUnavailable, status)
+	}
+
+	if err := server.RefreshNames(ctx); err != nil {
+		t.Fatalf("refreshing names failed with error %v", err)
+	}
+
+	status, suggestions := autocomplete(t, server, "/autocomplete?q=LIGHTN")
+	if status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, status)
+	}
+	expected := Suggestion{Name: "Lightning Bolt", Language: "en", OracleID: testOracleID}
+	if len(suggestions) != 1 || suggestions[0] != expected {
+		t.Fatalf("expected [%v], got %v", expected, suggestions)
+	}
+
+	for _, path := range []string{"/autocomplete", "/autocomplete?q=bolt&limit=0", "/autocomplete?q=bolt&limit=51"} {
+		if status, _ := autocomplete(t, server, path); status != http.StatusBadRequest {
+			t.Errorf("expected status %d for %s, got %d", http.StatusBadRequest, path, status)
+		}
+	}
+}
+
+func Test_AutocompleteRefreshedAfterSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := dbtest.NewSqliteStore(t)
+	seedTestCards(t, store)
+	server := NewServer(store)
+	if err := server.RefreshNames(ctx); err != nil {
+		t.Fatalf("refreshing names failed with error %v", err)
+	}
+	go server.WatchSyncs(ctx, 10*time.Millisecond)
+
+	set, err := store.GetSet(ctx, "a25")
+	if err != nil {
+		t.Fatalf("getting set failed with error %v", err)
+	}
+	// Written now, after the seeded cards, so the cards version changes
+	card := dbtest.NewCard("Lightning Helix", "200")
+	card.SetID = set.ScryfallID
+	card.LanguageCode = "es"
+	card.SpanishName = pgtype.Text{String: "Hélice relampagueante", Valid: true}
+	card.TypeLine = "Instant"
+	card.CreatedAt = pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+	card.UpdatedAt = card.CreatedAt
+	_, err = store.InsertCards(ctx, []sqlc.InsertCardsParams{card})
+	if err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, suggestions := autocomplete(t, server, "/autocomplete?q=helice"); len(suggestions) == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("expected the spanish name written after startup to be suggested")
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+type Card struct {
+	ScryfallID      string    `json:"scryfall_id"`
+	OracleID        string    `json:"oracle_id"`
+	Name            string    `json:"name"`
+	SpanishName     string    `json:"spanish_name,omitempty"`
+	Language        string    `json:"lang"`
+	SetCode         string    `json:"set"`
+	SetName         string    `json:"set_name"`
+	CollectorNumber string    `json:"collector_number"`
+	Rarity          string    `json:"rarity,omitempty"`
+	TypeLine        string    `json:"type_line"`
+	Colors          []string  `json:"colors"`
+	ColorIdentity   []string  `json:"color_identity"`
+	ScryfallURI     string    `json:"scryfall_uri"`
+	URI             string    `json:"uri"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Rank            float32   `json:"rank,omitempty"`
+}
+
+type Set struct {
+	ScryfallID string    `json:"scryfall_id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ListResponse[T any] struct {
+	Data     []T  `json:"data"`
+	Page     int  `json:"page"`
+	PageSize int  `json:"page_size"`
+	HasMore  bool `json:"has_more"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func formatUUID(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+
+	return uuid.UUID(id.Bytes).String()
+}
+
+// Colors are stored concatenated, as in "WU".
+func splitColors(colors pgtype.Text) []string {
+	if colors.String == "" {
+		return []string{}
+	}
+
+	return strings.Split(colors.String, "")
+}
+
+func newCard(card sqlc.Card, setCode string, setName string) Card {
+	return Card{
+		ScryfallID:      formatUUID(card.ScryfallID),
+		OracleID:        formatUUID(card.ScryfallOracleID),
+		Name:            card.Name,
+		SpanishName:     card.SpanishName.String,
+		Language:        card.LanguageCode,
+		SetCode:         setCode,
+		SetName:         setName,
+		CollectorNumber: card.CollectorNumber,
+		Rarity:          card.Rarity.String,
+		TypeLine:        card.TypeLine,
+		Colors:          splitColors(card.Colors),
+		ColorIdentity:   splitColors(card.ColorIdentity),
+		ScryfallURI:     card.ScryfallWebUri,
+		URI:             card.ScryfallApiUri,
+		UpdatedAt:       card.UpdatedAt.Time,
+	}
+}
+
+func newCards(cards []db.CardWithSet) []Card {
+	response := make([]Card, 0, len(cards))
+	for _, card := range cards {
+		response = append(response, newCard(card.Card, card.SetCode, card.SetName))
+	}
+
+	return response
+}
+
+func newSearchCards(rows []sqlc.SearchCardsRow) []Card {
+	response := make([]Card, 0, len(rows))
+	for _, row := range rows {
+		card := newCard(sqlc.Card{
+			ScryfallID:       row.ScryfallID,
+			SetID:            row.SetID,
+			Name:             row.Name,
+			CollectorNumber:  row.CollectorNumber,
+			ColorIdentity:    row.ColorIdentity,
+			Colors:           row.Colors,
+			LanguageCode:     row.LanguageCode,
+			SpanishName:      row.SpanishName,
+			Rarity:           row.Rarity,
+			TypeLine:         row.TypeLine,
+			ScryfallApiUri:   row.ScryfallApiUri,
+			ScryfallWebUri:   row.ScryfallWebUri,
+			ScryfallOracleID: row.ScryfallOracleID,
+			UpdatedAt:        row.UpdatedAt,
+		}, row.SetCode, row.SetName)
+		card.Rank = row.Rank
+		response = append(response, card)
+	}
+
+	return response
+}
+
+func newSet(set sqlc.Set) Set {
+	return Set{
+		ScryfallID: formatUUID(set.ScryfallID),
+		Code:       set.Code,
+		Name:       set.Name,
+		UpdatedAt:  set.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/search"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 500
+)
+
+type Server struct {
+	store db.Store
+	mux   *http.ServeMux
+
+	// names is nil until RefreshNames first succeeds. namesVersion is only
+	// touched by RefreshNames and WatchSyncs, which don't run concurrently.
+	names        atomic.Pointer[search.NameIndex]
+	namesVersion sqlc.GetCardsVersionRow
+}
+
+// NewServer serves the read-only endpoints over the store.
+func NewServer(store db.Store) *Server {
+	s := &Server{store: store, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /cards", s.handleQueryCards)
+	s.mux.HandleFunc("GET /cards/{id}", s.handleGetCard)
+	s.mux.HandleFunc("GET /oracle/{id}/printings", s.handleGetOraclePrintings)
+	s.mux.HandleFunc("GET /sets/{code}", s.handleGetSet)
+	s.mux.HandleFunc("GET /sets/{code}/cards", s.handleGetSetCards)
+	s.mux.HandleFunc("GET /search", s.handleSearch)
+	s.mux.HandleFunc("GET /autocomplete", s.handleAutocomplete)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// writeJSON tags the response with a hash of its body, so clients revalidating
+// with If-None-Match get a 304 until a sync changes what they asked for.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, value any) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+
+	w.Header().Set("Content-Type", "application/json")
+	if status == http.StatusOK {
+		w.Header().Set("ETag", etag)
+		if matchesETag(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func matchesETag(ifNoneMatch string, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	writeJSON(w, r, status, ErrorResponse{Error: err.Error()})
+}
+
+// writeStoreError hides store failures behind a 500, as their messages can
+// leak connection details.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	log.Println(err)
+	writeError(w, r, http.StatusInternalServerError, errors.New("internal server error"))
+}
+
+type pagination struct {
+	page     int
+	pageSize int
+}
+
+func parsePagination(r *http.Request) (pagination, error) {
+	p := pagination{page: 1, pageSize: defaultPageSize}
+
+	if value := r.URL.Query().Get("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return p, fmt.Errorf("invalid page %q, expected a positive integer", value)
+		}
+		p.page = page
+	}
+
+	if value := r.URL.Query().Get("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return p, fmt.Errorf("invalid page_size %q, expected an integer between 1 and %d", value, maxPageSize)
+		}
+		p.pageSize = pageSize
+	}
+
+	// Stores take 32 bit offsets
+	if p.page-1 > math.MaxInt32/p.pageSize {
+		return p, fmt.Errorf("invalid page %d, past the last page there can be", p.page)
+	}
+
+	return p, nil
+}
+
+// storePage asks for one row past the page, to tell whether there's a next one.
+func (p pagination) storePage() db.Page {
+	return db.Page{
+		Limit:  int32(p.pageSize + 1),
+		Offset: int32((p.page - 1) * p.pageSize),
+	}
+}
+
+func listResponse[T any](p pagination, data []T) ListResponse[T] {
+	hasMore := len(data) > p.pageSize
+	if hasMore {
+		data = data[:p.pageSize]
+	}
+
+	return ListResponse[T]{Data: data, Page: p.page, PageSize: p.pageSize, HasMore: hasMore}
+}
+
+func parseUUID(value string) (pgtype.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("invalid id %q, expected a uuid", value)
+	}
+
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+func (s *Server) handleGetCard(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	card, err := s.store.GetCard(r.Context(), id)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newCard(card.Card, card.SetCode, card.SetName))
+}
+
+func (s *Server) handleGetOraclePrintings(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	p, err := parsePagination(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	cards, err := s.store.GetOraclePrintings(r.Context(), id, p.storePage())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, listResponse(p, newCards(cards)))
+}
+
+func (s *Server) handleGetSet(w http.ResponseWriter, r *http.Request) {
+	set, err := s.store.GetSet(r.Context(), strings.ToLower(r.PathValue("code")))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, newSet(set))
+}
+
+func (s *Server) handleGetSetCards(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePagination(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	code := strings.ToLower(r.PathValue("code"))
+	if _, err := s.store.GetSet(r.Context(), code); err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	cards, err := s.store.GetSetCards(r.Context(), code, p.storePage())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, listResponse(p, newCards(cards)))
+}
+
+// handleSearch matches names in english or spanish, typos and accents
+// included, best match first.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, errors.New("missing q parameter"))
+		return
+	}
+
+	p, err := parsePagination(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := s.store.SearchCards(r.Context(), query, p.storePage())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, listResponse(p, newSearchCards(rows)))
+}
+
+// handleQueryCards filters printings with the scryfall query syntax, as in
+// /cards?q=t:goblin+c:r.
+func (s *Server) handleQueryCards(w http.ResponseWriter, r *http.Request) {
+	querier, ok := s.store.(db.CardQuerier)
+	if !ok {
+		writeError(w, r, http.StatusNotImplemented, errors.New("card queries are not supported by this store"))
+		return
+	}
+
+	p, err := parsePagination(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	node, err := cardquery.Parse(r.URL.Query().Get("q"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	cards, err := querier.QueryCards(r.Context(), node, p.storePage())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, listResponse(p, newCards(cards)))
+}
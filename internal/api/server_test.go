@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/dbtest"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+const (
+	testCardID   = "00000000-0000-4000-8000-000000000001"
+	testOracleID = "10000000-0000-4000-8000-000000000001"
+)
+
+// seedTestCards stores three printings of the same card in one set, numbered
+// so that sorting them as text would put 10 before 2.
+func seedTestCards(t *testing.T, store db.Store) {
+	t.Helper()
+
+	cards := []sqlc.InsertCardsParams{}
+	for i, collectorNumber := range []string{"10", "2", "141"} {
+		card := dbtest.NewCard("Lightning Bolt", collectorNumber)
+		if i == 0 {
+			card.ScryfallID = pgtype.UUID{Bytes: uuid.MustParse(testCardID), Valid: true}
+		}
+		card.Colors = pgtype.Text{String: "R", Valid: true}
+		card.ColorIdentity = pgtype.Text{String: "R", Valid: true}
+		card.Rarity = pgtype.Text{String: "uncommon", Valid: true}
+		card.TypeLine = "Instant"
+		card.ScryfallOracleID = pgtype.UUID{Bytes: uuid.MustParse(testOracleID), Valid: true}
+
+		cards = append(cards, card)
+	}
+
+	dbtest.Seed(t, store, dbtest.NewSet("a25", "Masters 25"), cards...)
+}
+
+func collectorNumbers(t *testing.T, body []byte) ([]string, bool) {
+	t.Helper()
+
+	var response ListResponse[Card]
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("decoding response failed with error %v", err)
+	}
+
+	numbers := []string{}
+	for _, card := range response.Data {
+		numbers = append(numbers, card.CollectorNumber)
+	}
+
+	return numbers, response.HasMore
+}
+
+func Test_Server(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedNumbers []string
+		expectedHasMore bool
+	}{
+		{name: "card by id", path: "/cards/" + testCardID, expectedStatus: http.StatusOK},
+		{name: "unknown card", path: "/cards/" + testOracleID, expectedStatus: http.StatusNotFound},
+		{name: "malformed card id", path: "/cards/bolt", expectedStatus: http.StatusBadRequest},
+		{name: "set by code", path: "/sets/A25", expectedStatus: http.StatusOK},
+		{name: "unknown set", path: "/sets/xxx", expectedStatus: http.StatusNotFound},
+		{
+			name:            "set cards by collector number",
+			path:            "/sets/a25/cards",
+			expectedStatus:  http.StatusOK,
+			expectedNumbers: []string{"2", "10", "141"},
+		},
+		{
+			name:            "second page",
+			path:            "/sets/a25/cards?page=2&page_size=1",
+			expectedStatus:  http.StatusOK,
+			expectedNumbers: []string{"10"},
+			expectedHasMore: true,
+		},
+		{name: "page size over the maximum", path: "/sets/a25/cards?page_size=501", expectedStatus: http.StatusBadRequest},
+		{name: "page past 32 bit offsets", path: "/sets/a25/cards?page=42949673&page_size=100", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "last page within 32 bit offsets",
+			path:           "/sets/a25/cards?page=21474837&page_size=100",
+			expectedStatus: http.StatusOK,
+		},
+		{name: "cards of an unknown set", path: "/sets/xxx/cards", expectedStatus: http.StatusNotFound},
+		{
+			name:            "oracle printings",
+			path:            "/oracle/" + testOracleID + "/printings",
+			expectedStatus:  http.StatusOK,
+			expectedNumbers: []string{"10", "141", "2"},
+		},
+		{
+			name:            "search by name",
+			path:            "/search?q=lightnin+bolt&page_size=2",
+			expectedStatus:  http.StatusOK,
+			expectedNumbers: []string{"10", "141"},
+			expectedHasMore: true,
+		},
+		{name: "search without query", path: "/search", expectedStatus: http.StatusBadRequest},
+		{
+			name:            "scryfall syntax query",
+			path:            "/cards?q=t:instant+c:r",
+			expectedStatus:  http.StatusOK,
+			expectedNumbers: []string{"10", "141", "2"},
+		},
+		{name: "malformed scryfall syntax query", path: "/cards?q=t:instant+(", expectedStatus: http.StatusBadRequest},
+	}
+
+	store := dbtest.NewSqliteStore(t)
+	seedTestCards(t, store)
+	server := NewServer(store)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body)
+			}
+
+			if test.expectedNumbers == nil {
+				return
+			}
+
+			numbers, hasMore := collectorNumbers(t, recorder.Body.Bytes())
+			if len(numbers) != len(test.expectedNumbers) {
+				t.Fatalf("expected %v, got %v", test.expectedNumbers, numbers)
+			}
+			for i := range numbers {
+				if numbers[i] != test.expectedNumbers[i] {
+					t.Fatalf("expected %v, got %v", test.expectedNumbers, numbers)
+				}
+			}
+
+			if hasMore != test.expectedHasMore {
+				t.Errorf("expected has_more %v, got %v", test.expectedHasMore, hasMore)
+			}
+		})
+	}
+}
+
+func Test_ServerETag(t *testing.T) {
+	store := dbtest.NewSqliteStore(t)
+	seedTestCards(t, store)
+	server := NewServer(store)
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/cards/"+testCardID, nil))
+
+	etag := recorder.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag header")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/cards/"+testCardID, nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, recorder.Code)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("expected an empty body, got %q", recorder.Body)
+	}
+}
+
+func Test_ServerQueryUnsupportedStore(t *testing.T) {
+	server := NewServer(db.NewMemoryStore())
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/cards?q=t:instant", nil))
+
+	if recorder.Code != http.StatusNotImplemented {
+		t.Errorf("expected status %d, got %d", http.StatusNotImplemented, recorder.Code)
+	}
+}
@@ -0,0 +1,95 @@
+package cardquery
+
+type Operator string
+
+const (
+	Colon          Operator = ":"
+	Equal          Operator = "="
+	NotEqual       Operator = "!="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+)
+
+type Field int
+
+const (
+	FieldName Field = iota
+	FieldType
+	FieldOracle
+	FieldSet
+	FieldLanguage
+	FieldManaValue
+	FieldColors
+	FieldIdentity
+	FieldRarity
+)
+
+// Node is a boolean expression over a card printing.
+type Node interface {
+	node()
+}
+
+type And struct {
+	Operands []Node
+}
+
+type Or struct {
+	Operands []Node
+}
+
+type Not struct {
+	Operand Node
+}
+
+// TextMatch matches Value anywhere in the field, ignoring case.
+type TextMatch struct {
+	Field Field
+	Value string
+}
+
+// Equals matches the field exactly, ignoring case.
+type Equals struct {
+	Field Field
+	Value string
+}
+
+type NumberComparison struct {
+	Field Field
+	Op    Operator
+	Value float64
+}
+
+// ColorComparison compares the set of colors in the field against Colors,
+// a subset of "WUBRG". Equal is an exact match, LessOrEqual a subset and
+// GreaterOrEqual a superset.
+type ColorComparison struct {
+	Field  Field
+	Op     Operator
+	Colors string
+}
+
+// ColorCountComparison compares the number of colors in the field.
+type ColorCountComparison struct {
+	Field Field
+	Op    Operator
+	Count int
+}
+
+// RarityComparison compares rarities in Scryfall's order, from common to
+// bonus.
+type RarityComparison struct {
+	Op     Operator
+	Rarity string
+}
+
+func (And) node()                  {}
+func (Or) node()                   {}
+func (Not) node()                  {}
+func (TextMatch) node()            {}
+func (Equals) node()               {}
+func (NumberComparison) node()     {}
+func (ColorComparison) node()      {}
+func (ColorCountComparison) node() {}
+func (RarityComparison) node()     {}
@@ -0,0 +1,42 @@
+package cardquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Positions are 1-based columns in the query string.
+
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Pos, e.Msg)
+}
+
+type UnsupportedKeywordError struct {
+	Keyword string
+	Pos     int
+}
+
+func (e *UnsupportedKeywordError) Error() string {
+	return fmt.Sprintf(
+		"unsupported keyword %q at column %d, supported keywords are %s",
+		e.Keyword,
+		e.Pos,
+		strings.Join(supportedKeywords(), ", "),
+	)
+}
+
+type InvalidValueError struct {
+	Keyword string
+	Value   string
+	Pos     int
+	Reason  string
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s at column %d: %s", e.Value, e.Keyword, e.Pos, e.Reason)
+}
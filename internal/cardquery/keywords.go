@@ -0,0 +1,208 @@
+package cardquery
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/source"
+)
+
+type keyword struct {
+	field Field
+	parse func(tok token, field Field) (Node, error)
+}
+
+var keywords = map[string]keyword{
+	"t":         {field: FieldType, parse: parseText},
+	"type":      {field: FieldType, parse: parseText},
+	"o":         {field: FieldOracle, parse: parseText},
+	"oracle":    {field: FieldOracle, parse: parseText},
+	"s":         {field: FieldSet, parse: parseEquals},
+	"set":       {field: FieldSet, parse: parseEquals},
+	"e":         {field: FieldSet, parse: parseEquals},
+	"edition":   {field: FieldSet, parse: parseEquals},
+	"lang":      {field: FieldLanguage, parse: parseEquals},
+	"language":  {field: FieldLanguage, parse: parseEquals},
+	"cmc":       {field: FieldManaValue, parse: parseNumber},
+	"mv":        {field: FieldManaValue, parse: parseNumber},
+	"manavalue": {field: FieldManaValue, parse: parseNumber},
+	"c":         {field: FieldColors, parse: parseColors},
+	"color":     {field: FieldColors, parse: parseColors},
+	"id":        {field: FieldIdentity, parse: parseColors},
+	"identity":  {field: FieldIdentity, parse: parseColors},
+	"r":         {field: FieldRarity, parse: parseRarity},
+	"rarity":    {field: FieldRarity, parse: parseRarity},
+}
+
+func supportedKeywords() []string {
+	return slices.Sorted(maps.Keys(keywords))
+}
+
+var colorNames = map[string]string{
+	"white": "W",
+	"blue":  "U",
+	"black": "B",
+	"red":   "R",
+	"green": "G",
+
+	"azorius":  "WU",
+	"dimir":    "UB",
+	"rakdos":   "BR",
+	"gruul":    "RG",
+	"selesnya": "WG",
+	"orzhov":   "WB",
+	"izzet":    "UR",
+	"golgari":  "BG",
+	"boros":    "WR",
+	"simic":    "UG",
+
+	"bant":   "WUG",
+	"esper":  "WUB",
+	"grixis": "UBR",
+	"jund":   "BRG",
+	"naya":   "WRG",
+	"abzan":  "WBG",
+	"jeskai": "WUR",
+	"sultai": "UBG",
+	"mardu":  "WBR",
+	"temur":  "URG",
+}
+
+// Scryfall's order, used for rarity comparisons.
+var rarities = source.Rarities
+
+var rarityAbbreviations = map[string]string{
+	"c": "common",
+	"u": "uncommon",
+	"r": "rare",
+	"s": "special",
+	"m": "mythic",
+	"b": "bonus",
+}
+
+func onlyEquality(tok token) error {
+	if tok.op != Colon && tok.op != Equal && tok.op != NotEqual {
+		return &SyntaxError{Pos: tok.pos, Msg: "operator " + string(tok.op) + " not supported for " + tok.key}
+	}
+
+	return nil
+}
+
+func negateIfNotEqual(tok token, node Node) Node {
+	if tok.op == NotEqual {
+		return Not{Operand: node}
+	}
+
+	return node
+}
+
+func parseText(tok token, field Field) (Node, error) {
+	if err := onlyEquality(tok); err != nil {
+		return nil, err
+	}
+
+	return negateIfNotEqual(tok, TextMatch{Field: field, Value: tok.value}), nil
+}
+
+func parseEquals(tok token, field Field) (Node, error) {
+	if err := onlyEquality(tok); err != nil {
+		return nil, err
+	}
+
+	return negateIfNotEqual(tok, Equals{Field: field, Value: strings.ToLower(tok.value)}), nil
+}
+
+func parseNumber(tok token, field Field) (Node, error) {
+	value, err := strconv.ParseFloat(tok.value, 64)
+	if err != nil {
+		return nil, &InvalidValueError{Keyword: tok.key, Value: tok.value, Pos: tok.pos, Reason: "expected a number"}
+	}
+
+	op := tok.op
+	if op == Colon {
+		op = Equal
+	}
+
+	return NumberComparison{Field: field, Op: op, Value: value}, nil
+}
+
+func parseColors(tok token, field Field) (Node, error) {
+	value := strings.ToLower(tok.value)
+
+	// ":" means "at least these colors" for colors, but "fits in a deck of
+	// these colors" for identity, as in Scryfall
+	op := tok.op
+	if op == Colon {
+		op = GreaterOrEqual
+		if field == FieldIdentity {
+			op = LessOrEqual
+		}
+	}
+
+	if count, err := strconv.Atoi(value); err == nil {
+		if tok.op == Colon {
+			op = Equal
+		}
+		return ColorCountComparison{Field: field, Op: op, Count: count}, nil
+	}
+
+	switch value {
+	case "c", "colorless":
+		if tok.op == Colon {
+			op = Equal
+		}
+		return ColorComparison{Field: field, Op: op, Colors: ""}, nil
+	case "m", "multicolor":
+		if err := onlyEquality(tok); err != nil {
+			return nil, err
+		}
+		return negateIfNotEqual(tok, ColorCountComparison{Field: field, Op: Greater, Count: 1}), nil
+	}
+
+	if colors, ok := colorNames[value]; ok {
+		return ColorComparison{Field: field, Op: op, Colors: colors}, nil
+	}
+
+	colors := ""
+	for _, color := range "WUBRG" {
+		if strings.ContainsRune(value, color+'a'-'A') {
+			colors += string(color)
+		}
+	}
+
+	if len(colors) != len(value) {
+		return nil, &InvalidValueError{
+			Keyword: tok.key,
+			Value:   tok.value,
+			Pos:     tok.pos,
+			Reason:  "expected a number, color letters from wubrg, c, m or a color name",
+		}
+	}
+
+	return ColorComparison{Field: field, Op: op, Colors: colors}, nil
+}
+
+func parseRarity(tok token, field Field) (Node, error) {
+	value := strings.ToLower(tok.value)
+	if full, ok := rarityAbbreviations[value]; ok {
+		value = full
+	}
+
+	if !slices.Contains(rarities, value) {
+		return nil, &InvalidValueError{
+			Keyword: tok.key,
+			Value:   tok.value,
+			Pos:     tok.pos,
+			Reason:  "expected one of " + strings.Join(rarities, ", "),
+		}
+	}
+
+	op := tok.op
+	if op == Colon {
+		op = Equal
+	}
+
+	return RarityComparison{Op: op, Rarity: value}, nil
+}
@@ -0,0 +1,167 @@
+package cardquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenWord
+	tokenTerm
+)
+
+type token struct {
+	kind  tokenKind
+	pos   int
+	key   string
+	op    Operator
+	value string
+}
+
+// Longer operators first, so "<=" is not read as "<" followed by "=".
+var operators = []Operator{NotEqual, LessOrEqual, GreaterOrEqual, Colon, Equal, Less, Greater}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func lex(input string) ([]token, error) {
+	l := &lexer{input: []rune(input)}
+	tokens := []token{}
+
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.pos]
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+
+	start := l.pos + 1
+	switch r := l.peek(); {
+	case r == 0:
+		return token{kind: tokenEOF, pos: start}, nil
+	case r == '(':
+		l.pos++
+		return token{kind: tokenLParen, pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokenRParen, pos: start}, nil
+	case r == '-':
+		l.pos++
+		if next := l.peek(); next == 0 || unicode.IsSpace(next) {
+			return token{}, &SyntaxError{Pos: start, Msg: "expected a term after -"}
+		}
+		return token{kind: tokenNot, pos: start}, nil
+	case r == '"':
+		value, err := l.quoted()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokenWord, pos: start, value: value}, nil
+	}
+
+	keyStart := l.pos
+	for unicode.IsLetter(l.peek()) {
+		l.pos++
+	}
+	key := string(l.input[keyStart:l.pos])
+
+	if op, ok := l.operator(); ok && key != "" {
+		var value string
+		if l.peek() == '"' {
+			quoted, err := l.quoted()
+			if err != nil {
+				return token{}, err
+			}
+			value = quoted
+		} else {
+			value = l.bare()
+		}
+
+		if value == "" {
+			return token{}, &SyntaxError{Pos: start, Msg: "expected a value after " + key + string(op)}
+		}
+		return token{kind: tokenTerm, pos: start, key: strings.ToLower(key), op: op, value: value}, nil
+	}
+
+	l.pos = keyStart
+	word := l.bare()
+	switch strings.ToLower(word) {
+	case "and":
+		return token{kind: tokenAnd, pos: start}, nil
+	case "or":
+		return token{kind: tokenOr, pos: start}, nil
+	case "not":
+		return token{kind: tokenNot, pos: start}, nil
+	}
+
+	return token{kind: tokenWord, pos: start, value: word}, nil
+}
+
+func (l *lexer) operator() (Operator, bool) {
+	rest := string(l.input[l.pos:min(l.pos+2, len(l.input))])
+	for _, op := range operators {
+		if strings.HasPrefix(rest, string(op)) {
+			l.pos += len([]rune(string(op)))
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+// bare reads until whitespace, a parenthesis or a quote.
+func (l *lexer) bare() string {
+	start := l.pos
+	for r := l.peek(); r != 0 && !unicode.IsSpace(r) && r != '(' && r != ')' && r != '"'; r = l.peek() {
+		l.pos++
+	}
+
+	return string(l.input[start:l.pos])
+}
+
+func (l *lexer) quoted() (string, error) {
+	start := l.pos + 1
+	l.pos++
+
+	valueStart := l.pos
+	for l.pos < len(l.input) && l.input[l.pos] != '"' {
+		l.pos++
+	}
+
+	if l.pos >= len(l.input) {
+		return "", &SyntaxError{Pos: start, Msg: "unterminated quoted string"}
+	}
+
+	value := string(l.input[valueStart:l.pos])
+	l.pos++
+
+	return value, nil
+}
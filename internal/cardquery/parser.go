@@ -0,0 +1,138 @@
+package cardquery
+
+// Parse turns a Scryfall-style query into its AST. Terms next to each other
+// are joined with and, which binds tighter than or, and - or not negate the
+// term or parenthesized group that follows:
+//
+//	t:creature c:g cmc<=3 (r:mythic or r:rare) -s:dmu lang:es
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 1, Msg: "empty query"}
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected )"}
+	}
+
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	operands := []Node{}
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+
+		if p.peek().kind != tokenOr {
+			break
+		}
+		p.advance()
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+
+	return Or{Operands: operands}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	operands := []Node{}
+	for {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, node)
+
+		switch p.peek().kind {
+		case tokenAnd:
+			p.advance()
+			continue
+		case tokenLParen, tokenNot, tokenWord, tokenTerm:
+			continue
+		}
+		break
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+
+	return And{Operands: operands}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokenNot {
+		p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.advance()
+
+	switch tok.kind {
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "unclosed ("}
+		}
+		return node, nil
+	case tokenWord:
+		return TextMatch{Field: FieldName, Value: tok.value}, nil
+	case tokenTerm:
+		keyword, ok := keywords[tok.key]
+		if !ok {
+			return nil, &UnsupportedKeywordError{Keyword: tok.key, Pos: tok.pos}
+		}
+		return keyword.parse(tok, keyword.field)
+	case tokenEOF:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected end of query"}
+	case tokenRParen:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "unexpected )"}
+	}
+
+	return nil, &SyntaxError{Pos: tok.pos, Msg: "expected a term"}
+}
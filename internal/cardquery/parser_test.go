@@ -0,0 +1,119 @@
+package cardquery
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Node
+	}{
+		{
+			input: "t:creature c:g cmc<=3 r:mythic s:dmu lang:es",
+			expected: And{Operands: []Node{
+				TextMatch{Field: FieldType, Value: "creature"},
+				ColorComparison{Field: FieldColors, Op: GreaterOrEqual, Colors: "G"},
+				NumberComparison{Field: FieldManaValue, Op: LessOrEqual, Value: 3},
+				RarityComparison{Op: Equal, Rarity: "mythic"},
+				Equals{Field: FieldSet, Value: "dmu"},
+				Equals{Field: FieldLanguage, Value: "es"},
+			}},
+		},
+		{
+			input: "jace or (t:planeswalker and -c:u)",
+			expected: Or{Operands: []Node{
+				TextMatch{Field: FieldName, Value: "jace"},
+				And{Operands: []Node{
+					TextMatch{Field: FieldType, Value: "planeswalker"},
+					Not{Operand: ColorComparison{Field: FieldColors, Op: GreaterOrEqual, Colors: "U"}},
+				}},
+			}},
+		},
+		{
+			input: `not o:"draw a card" "lightning bolt"`,
+			expected: And{Operands: []Node{
+				Not{Operand: TextMatch{Field: FieldOracle, Value: "draw a card"}},
+				TextMatch{Field: FieldName, Value: "lightning bolt"},
+			}},
+		},
+		{
+			input: "a or b c",
+			expected: Or{Operands: []Node{
+				TextMatch{Field: FieldName, Value: "a"},
+				And{Operands: []Node{
+					TextMatch{Field: FieldName, Value: "b"},
+					TextMatch{Field: FieldName, Value: "c"},
+				}},
+			}},
+		},
+		{input: "id:azorius", expected: ColorComparison{Field: FieldIdentity, Op: LessOrEqual, Colors: "WU"}},
+		{input: "c=gu", expected: ColorComparison{Field: FieldColors, Op: Equal, Colors: "UG"}},
+		{input: "c:c", expected: ColorComparison{Field: FieldColors, Op: Equal, Colors: ""}},
+		{input: "c:m", expected: ColorCountComparison{Field: FieldColors, Op: Greater, Count: 1}},
+		{input: "c>=2", expected: ColorCountComparison{Field: FieldColors, Op: GreaterOrEqual, Count: 2}},
+		{input: "r>=rare", expected: RarityComparison{Op: GreaterOrEqual, Rarity: "rare"}},
+		{input: "R:U", expected: RarityComparison{Op: Equal, Rarity: "uncommon"}},
+		{input: "mv!=2.5", expected: NumberComparison{Field: FieldManaValue, Op: NotEqual, Value: 2.5}},
+		{input: "s!=dmu", expected: Not{Operand: Equals{Field: FieldSet, Value: "dmu"}}},
+		{input: "lim-dûl's", expected: TextMatch{Field: FieldName, Value: "lim-dûl's"}},
+		{input: "--jace", expected: Not{Operand: Not{Operand: TextMatch{Field: FieldName, Value: "jace"}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("parsing %q failed with error %v", test.input, err)
+			}
+
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Fatalf("expected %#v but got %#v", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	var syntaxErr *SyntaxError
+	var keywordErr *UnsupportedKeywordError
+	var valueErr *InvalidValueError
+
+	tests := []struct {
+		input           string
+		expectedType    any
+		expectedMessage string
+	}{
+		{input: "", expectedType: &syntaxErr, expectedMessage: "column 1: empty query"},
+		{input: "(t:creature", expectedType: &syntaxErr, expectedMessage: "column 1: unclosed ("},
+		{input: "t:creature)", expectedType: &syntaxErr, expectedMessage: "column 11: unexpected )"},
+		{input: `o:"draw`, expectedType: &syntaxErr, expectedMessage: "column 3: unterminated quoted string"},
+		{input: "jace or", expectedType: &syntaxErr, expectedMessage: "unexpected end of query"},
+		{input: "t:", expectedType: &syntaxErr, expectedMessage: "expected a value after t:"},
+		{input: "t>creature", expectedType: &syntaxErr, expectedMessage: "operator > not supported for t"},
+		{input: "c:g pow>=3", expectedType: &keywordErr, expectedMessage: `unsupported keyword "pow" at column 5`},
+		{input: "cmc<=x", expectedType: &valueErr, expectedMessage: "expected a number"},
+		{input: "c:gx", expectedType: &valueErr, expectedMessage: "color letters"},
+		{input: "r:epic", expectedType: &valueErr, expectedMessage: "expected one of common"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := Parse(test.input)
+			if err == nil {
+				t.Fatalf("expected parsing %q to fail", test.input)
+			}
+
+			if !errors.As(err, test.expectedType) {
+				t.Fatalf("expected error of type %T but got %T: %v", test.expectedType, err, err)
+			}
+
+			if !strings.Contains(err.Error(), test.expectedMessage) {
+				t.Fatalf("expected error containing %q but got %q", test.expectedMessage, err.Error())
+			}
+		})
+	}
+}
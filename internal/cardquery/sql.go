@@ -0,0 +1,226 @@
+package cardquery
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/search"
+)
+
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	Sqlite
+)
+
+func (d Dialect) placeholder(n int) string {
+	if d == Sqlite {
+		return "?" + strconv.Itoa(n)
+	}
+
+	return "$" + strconv.Itoa(n)
+}
+
+func (d Dialect) rawField(key string) string {
+	if d == Sqlite {
+		return "json_extract(c.raw, '$." + key + "')"
+	}
+
+	return "(c.raw->>'" + key + "')"
+}
+
+func (d Dialect) rawNumber(key string) string {
+	if d == Sqlite {
+		return "CAST(" + d.rawField(key) + " AS REAL)"
+	}
+
+	return d.rawField(key) + "::NUMERIC"
+}
+
+var sqlOperators = map[Operator]string{
+	Equal:          "=",
+	NotEqual:       "<>",
+	Less:           "<",
+	LessOrEqual:    "<=",
+	Greater:        ">",
+	GreaterOrEqual: ">=",
+}
+
+// Where compiles node into a boolean SQL expression over cards aliased c
+// joined to their sets aliased s, returning it with its arguments, numbered
+// from 1. User values only ever reach the SQL as arguments. Missing values
+// compare as empty or zero, so negated terms still match them.
+//
+// On Postgres it needs the search_normalize function from the card search
+// migration, which the db package registers on SQLite connections.
+func Where(node Node, dialect Dialect) (string, []any) {
+	c := &compiler{dialect: dialect}
+	return c.compile(node), c.args
+}
+
+type compiler struct {
+	dialect Dialect
+	args    []any
+}
+
+func (c *compiler) arg(value any) string {
+	c.args = append(c.args, value)
+	return c.dialect.placeholder(len(c.args))
+}
+
+func (c *compiler) compile(node Node) string {
+	switch n := node.(type) {
+	case And:
+		return c.join(n.Operands, " AND ")
+	case Or:
+		return c.join(n.Operands, " OR ")
+	case Not:
+		return "NOT " + c.compile(n.Operand)
+	case TextMatch:
+		return c.textMatch(n)
+	case Equals:
+		return c.equals(n)
+	case NumberComparison:
+		return fmt.Sprintf(
+			"COALESCE(%s, 0) %s %s",
+			c.dialect.rawNumber("cmc"),
+			sqlOperators[n.Op],
+			c.arg(n.Value),
+		)
+	case ColorComparison:
+		return c.colorComparison(n)
+	case ColorCountComparison:
+		return fmt.Sprintf("length(%s) %s %s", colorColumn(n.Field), sqlOperators[n.Op], c.arg(n.Count))
+	case RarityComparison:
+		return c.rarityComparison(n)
+	}
+
+	panic(fmt.Sprintf("unexpected node %T", node))
+}
+
+func (c *compiler) join(operands []Node, separator string) string {
+	compiled := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		compiled = append(compiled, c.compile(operand))
+	}
+
+	return "(" + strings.Join(compiled, separator) + ")"
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func (c *compiler) textMatch(n TextMatch) string {
+	switch n.Field {
+	case FieldName:
+		pattern := c.arg("%" + escapeLike(search.Normalize(n.Value)) + "%")
+		return fmt.Sprintf(
+			`(search_normalize(c.name) LIKE %s ESCAPE '\' OR COALESCE(search_normalize(c.spanish_name), '') LIKE %s ESCAPE '\')`,
+			pattern,
+			pattern,
+		)
+	case FieldType:
+		return fmt.Sprintf(
+			`lower(c.type_line) LIKE %s ESCAPE '\'`,
+			c.arg("%"+escapeLike(strings.ToLower(n.Value))+"%"),
+		)
+	case FieldOracle:
+		return fmt.Sprintf(
+			`lower(COALESCE(%s, '')) LIKE %s ESCAPE '\'`,
+			c.dialect.rawField("oracle_text"),
+			c.arg("%"+escapeLike(strings.ToLower(n.Value))+"%"),
+		)
+	}
+
+	panic(fmt.Sprintf("unexpected text field %d", n.Field))
+}
+
+func (c *compiler) equals(n Equals) string {
+	switch n.Field {
+	case FieldSet:
+		return "s.code = " + c.arg(n.Value)
+	case FieldLanguage:
+		return "c.language_code = " + c.arg(n.Value)
+	}
+
+	panic(fmt.Sprintf("unexpected equality field %d", n.Field))
+}
+
+func colorColumn(field Field) string {
+	if field == FieldIdentity {
+		return "COALESCE(c.color_identity, '')"
+	}
+
+	return "COALESCE(c.colors, '')"
+}
+
+func (c *compiler) colorComparison(n ColorComparison) string {
+	column := colorColumn(n.Field)
+
+	has := func(colors string, negate bool) []string {
+		like := " LIKE "
+		if negate {
+			like = " NOT LIKE "
+		}
+
+		conditions := []string{}
+		for _, color := range colors {
+			conditions = append(conditions, column+like+c.arg("%"+string(color)+"%"))
+		}
+		return conditions
+	}
+
+	notIn := ""
+	for _, color := range "WUBRG" {
+		if !strings.ContainsRune(n.Colors, color) {
+			notIn += string(color)
+		}
+	}
+
+	var conditions []string
+	switch n.Op {
+	case GreaterOrEqual:
+		conditions = has(n.Colors, false)
+	case Greater:
+		conditions = append(has(n.Colors, false), fmt.Sprintf("length(%s) > %s", column, c.arg(len(n.Colors))))
+	case LessOrEqual:
+		conditions = has(notIn, true)
+	case Less:
+		conditions = append(has(notIn, true), fmt.Sprintf("length(%s) < %s", column, c.arg(len(n.Colors))))
+	case Equal, NotEqual:
+		conditions = append(has(n.Colors, false), has(notIn, true)...)
+	}
+
+	expression := "TRUE"
+	if len(conditions) > 0 {
+		expression = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+
+	if n.Op == NotEqual {
+		return "NOT " + expression
+	}
+
+	return expression
+}
+
+func (c *compiler) rarityComparison(n RarityComparison) string {
+	if n.Op == Equal || n.Op == NotEqual {
+		return fmt.Sprintf("COALESCE(c.rarity, '') %s %s", sqlOperators[n.Op], c.arg(n.Rarity))
+	}
+
+	cases := make([]string, 0, len(rarities))
+	for i, rarity := range rarities {
+		cases = append(cases, fmt.Sprintf("WHEN '%s' THEN %d", rarity, i))
+	}
+
+	return fmt.Sprintf(
+		"(CASE c.rarity %s ELSE -1 END) %s %s",
+		strings.Join(cases, " "),
+		sqlOperators[n.Op],
+		c.arg(slices.Index(rarities, n.Rarity)),
+	)
+}
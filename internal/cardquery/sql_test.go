@@ -0,0 +1,194 @@
+package cardquery_test
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/dbtest"
+)
+
+func Test_WherePostgres(t *testing.T) {
+	node, err := cardquery.Parse("t:creature -c:g cmc<=3 s:dmu")
+	if err != nil {
+		t.Fatalf("parsing failed with error %v", err)
+	}
+
+	where, args := cardquery.Where(node, cardquery.Postgres)
+
+	expectedWhere := `(lower(c.type_line) LIKE $1 ESCAPE '\' AND ` +
+		`NOT (COALESCE(c.colors, '') LIKE $2) AND ` +
+		`COALESCE((c.raw->>'cmc')::NUMERIC, 0) <= $3 AND ` +
+		`s.code = $4)`
+	expectedArgs := []any{"%creature%", "%G%", 3.0, "dmu"}
+
+	if where != expectedWhere {
+		t.Fatalf("expected where clause\n%s\nbut got\n%s", expectedWhere, where)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Fatalf("expected args %#v but got %#v", expectedArgs, args)
+	}
+}
+
+type queryFixtureCard struct {
+	name          string
+	spanishName   string
+	set           string
+	language      string
+	rarity        string
+	typeLine      string
+	colors        string
+	colorIdentity string
+	raw           string
+}
+
+var queryFixtureCards = []queryFixtureCard{
+	{
+		name: "Llanowar Elves", set: "dmu", language: "en", rarity: "common",
+		typeLine: "Creature — Elf Druid", colors: "G", colorIdentity: "G",
+		raw: `{"cmc": 1, "oracle_text": "{T}: Add {G}."}`,
+	},
+	{
+		name: "Llanowar Elves", spanishName: "Elfos de Llanowar", set: "dmu", language: "es", rarity: "common",
+		typeLine: "Creature — Elf Druid", colors: "G", colorIdentity: "G",
+		raw: `{"cmc": 1, "oracle_text": "{T}: Add {G}."}`,
+	},
+	{
+		name: "Sheoldred, the Apocalypse", set: "dmu", language: "en", rarity: "mythic",
+		typeLine: "Legendary Creature — Phyrexian Praetor", colors: "B", colorIdentity: "B",
+		raw: `{"cmc": 4, "oracle_text": "Deathtouch. Whenever you draw a card, you gain 2 life."}`,
+	},
+	{
+		name: "Jace, the Mind Sculptor", spanishName: "Jace, el Escultor de Mentes", set: "a25", language: "es",
+		rarity: "mythic", typeLine: "Legendary Planeswalker — Jace", colors: "U", colorIdentity: "U",
+		raw: `{"cmc": 4}`,
+	},
+	{
+		name: "Absorb", set: "a25", language: "en", rarity: "rare",
+		typeLine: "Instant", colors: "WU", colorIdentity: "WU",
+		raw: `{"cmc": 3, "oracle_text": "Counter target spell. You gain 3 life."}`,
+	},
+	{
+		name: "Sol Ring", set: "c21", language: "en", rarity: "uncommon",
+		typeLine: "Artifact", raw: `{"cmc": 1, "oracle_text": "{T}: Add {C}{C}."}`,
+	},
+}
+
+func openQueryFixture(t *testing.T) *sql.DB {
+	t.Helper()
+
+	sqlDb, _, err := db.OpenSQL(dbtest.NewSqliteURL(t))
+	if err != nil {
+		t.Fatalf("opening db failed with error %v", err)
+	}
+	t.Cleanup(func() { sqlDb.Close() })
+
+	for _, code := range []string{"dmu", "a25", "c21"} {
+		if _, err := sqlDb.Exec(
+			"INSERT INTO sets VALUES (?, ?, ?, '2025-01-01', '2025-01-01')",
+			code+"-id",
+			code,
+			code,
+		); err != nil {
+			t.Fatalf("inserting set failed with error %v", err)
+		}
+	}
+
+	for i, card := range queryFixtureCards {
+		if _, err := sqlDb.Exec(
+			`INSERT INTO cards (
+				scryfall_id, set_id, name, collector_number, color_identity, colors, language_code,
+				spanish_name, rarity, type_line, scryfall_api_uri, scryfall_web_uri, scryfall_oracle_id,
+				created_at, updated_at, raw
+			) VALUES (?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?, ?, ?, '2025-01-01', '2025-01-01', ?)`,
+			fmt.Sprint(i),
+			card.set+"-id",
+			card.name,
+			fmt.Sprint(i),
+			card.colorIdentity,
+			card.colors,
+			card.language,
+			card.spanishName,
+			card.rarity,
+			card.typeLine,
+			fmt.Sprint("api/", i),
+			fmt.Sprint("web/", i),
+			card.name,
+			card.raw,
+		); err != nil {
+			t.Fatalf("inserting card failed with error %v", err)
+		}
+	}
+
+	return sqlDb
+}
+
+func Test_WhereSqlite(t *testing.T) {
+	sqlDb := openQueryFixture(t)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "t:creature c:g cmc<=3 r:common s:dmu lang:es", expected: []string{"1"}},
+		{query: "t:creature", expected: []string{"0", "1", "2"}},
+		{query: "-t:creature", expected: []string{"3", "4", "5"}},
+		{query: "elfos", expected: []string{"1"}},
+		{query: "escultor mentes", expected: []string{"3"}},
+		{query: `o:"draw a card"`, expected: []string{"2"}},
+		{query: "-o:draw", expected: []string{"0", "1", "3", "4", "5"}},
+		{query: "cmc>=4 or c:c", expected: []string{"2", "3", "5"}},
+		{query: "c=wu", expected: []string{"4"}},
+		{query: "c:azorius", expected: []string{"4"}},
+		{query: "c:u", expected: []string{"3", "4"}},
+		{query: "c<=wu", expected: []string{"3", "4", "5"}},
+		{query: "c<wu", expected: []string{"3", "5"}},
+		{query: "c>u", expected: []string{"4"}},
+		{query: "c!=g", expected: []string{"2", "3", "4", "5"}},
+		{query: "id:esper", expected: []string{"2", "3", "4", "5"}},
+		{query: "c:m", expected: []string{"4"}},
+		{query: "c=0", expected: []string{"5"}},
+		{query: "r>=rare", expected: []string{"2", "3", "4"}},
+		{query: "r<rare", expected: []string{"0", "1", "5"}},
+		{query: "(s:a25 or s:c21) -r:mythic", expected: []string{"4", "5"}},
+		{query: "not (t:creature or t:instant) cmc:1", expected: []string{"5"}},
+		{query: "100%", expected: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			node, err := cardquery.Parse(test.query)
+			if err != nil {
+				t.Fatalf("parsing %q failed with error %v", test.query, err)
+			}
+
+			where, args := cardquery.Where(node, cardquery.Sqlite)
+			rows, err := sqlDb.Query(
+				"SELECT c.scryfall_id FROM cards c INNER JOIN sets s ON c.set_id = s.scryfall_id WHERE "+where,
+				args...,
+			)
+			if err != nil {
+				t.Fatalf("running %q failed with error %v", where, err)
+			}
+			defer rows.Close()
+
+			got := []string{}
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					t.Fatalf("scanning failed with error %v", err)
+				}
+				got = append(got, id)
+			}
+			slices.Sort(got)
+
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected cards %v for %q but got %v (where %s)", test.expected, test.query, got, where)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package completion
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"FedeAbella/mtgdb/internal/source"
+)
+
+// Rarities orders the rarity breakdown, with any rarity Scryfall adds later
+// after these.
+var Rarities = source.Rarities
+
+// Languages are the languages the breakdown reports, the ones synced.
+var Languages = []string{"en", "es"}
+
+// Printing is a printing in the set, owned if any collection holds a copy.
+type Printing struct {
+	CollectorNumber string
+	LanguageCode    string
+	Rarity          string
+	// Variant is true for variations and boosterfun printings
+	Variant bool
+	Owned   bool
+}
+
+// variantFields are the fields of a card's Scryfall JSON telling variants
+// apart from the main printings of a set.
+type variantFields struct {
+	Variation  bool     `json:"variation"`
+	PromoTypes []string `json:"promo_types"`
+}
+
+// IsVariant tells if the card's Scryfall JSON is a variation of another
+// printing or a boosterfun treatment, like showcase or borderless art.
+func IsVariant(raw string) bool {
+	var fields variantFields
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return false
+	}
+
+	return fields.Variation || slices.Contains(fields.PromoTypes, "boosterfun")
+}
+
+type Count struct {
+	Owned int `json:"owned"`
+	Total int `json:"total"`
+}
+
+// Percent is the share owned, 0 if there is nothing to own.
+func (c Count) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+
+	return float64(c.Owned) * 100 / float64(c.Total)
+}
+
+func (c Count) add(owned bool) Count {
+	c.Total++
+	if owned {
+		c.Owned++
+	}
+
+	return c
+}
+
+type RarityCount struct {
+	Rarity string `json:"rarity"`
+	Count
+}
+
+type LanguageCount struct {
+	LanguageCode string `json:"lang"`
+	Count
+}
+
+// Report counts the collector numbers of a set owned in any language, and
+// separately the printings owned in each language.
+type Report struct {
+	SetCode    string          `json:"set"`
+	SetName    string          `json:"set_name"`
+	Variants   bool            `json:"variants"`
+	Cards      Count           `json:"cards"`
+	ByRarity   []RarityCount   `json:"by_rarity"`
+	ByLanguage []LanguageCount `json:"by_language"`
+	Missing    []string        `json:"missing"`
+}
+
+// Complete reports how much of a set is owned from its printings, given in
+// collector number order. Variants are left out unless variants is true.
+func Complete(setCode string, setName string, printings []Printing, variants bool) Report {
+	report := Report{SetCode: setCode, SetName: setName, Variants: variants, Missing: []string{}}
+
+	numbers := []string{}
+	owned := map[string]bool{}
+	rarities := map[string]string{}
+	languages := map[string]Count{}
+	for _, printing := range printings {
+		if printing.Variant && !variants {
+			continue
+		}
+
+		if _, ok := owned[printing.CollectorNumber]; !ok {
+			numbers = append(numbers, printing.CollectorNumber)
+			rarities[printing.CollectorNumber] = printing.Rarity
+		}
+		owned[printing.CollectorNumber] = owned[printing.CollectorNumber] || printing.Owned
+		languages[printing.LanguageCode] = languages[printing.LanguageCode].add(printing.Owned)
+	}
+
+	byRarity := map[string]Count{}
+	for _, number := range numbers {
+		report.Cards = report.Cards.add(owned[number])
+		byRarity[rarities[number]] = byRarity[rarities[number]].add(owned[number])
+		if !owned[number] {
+			report.Missing = append(report.Missing, number)
+		}
+	}
+
+	report.ByRarity = []RarityCount{}
+	for rarity, count := range byRarity {
+		report.ByRarity = append(report.ByRarity, RarityCount{Rarity: rarity, Count: count})
+	}
+	slices.SortFunc(report.ByRarity, func(a, b RarityCount) int {
+		return cmp.Or(cmp.Compare(rarityOrder(a.Rarity), rarityOrder(b.Rarity)), cmp.Compare(a.Rarity, b.Rarity))
+	})
+
+	report.ByLanguage = []LanguageCount{}
+	for _, language := range Languages {
+		report.ByLanguage = append(report.ByLanguage, LanguageCount{LanguageCode: language, Count: languages[language]})
+	}
+
+	return report
+}
+
+func rarityOrder(rarity string) int {
+	if i := slices.Index(Rarities, rarity); i >= 0 {
+		return i
+	}
+
+	return len(Rarities)
+}
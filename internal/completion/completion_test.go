@@ -0,0 +1,99 @@
+package completion
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testPrintings = []Printing{
+	{CollectorNumber: "1", LanguageCode: "en", Rarity: "common", Owned: true},
+	{CollectorNumber: "1", LanguageCode: "es", Rarity: "common"},
+	{CollectorNumber: "2", LanguageCode: "en", Rarity: "common"},
+	{CollectorNumber: "2", LanguageCode: "es", Rarity: "common", Owned: true},
+	{CollectorNumber: "3", LanguageCode: "en", Rarity: "mythic"},
+	{CollectorNumber: "4", LanguageCode: "en", Rarity: "uncommon"},
+	{CollectorNumber: "5", LanguageCode: "en", Rarity: "special"},
+	{CollectorNumber: "300", LanguageCode: "en", Rarity: "mythic", Variant: true, Owned: true},
+}
+
+func Test_IsVariant(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected bool
+	}{
+		{raw: `{"variation": true}`, expected: true},
+		{raw: `{"promo_types": ["showcase", "boosterfun"]}`, expected: true},
+		{raw: `{"promo_types": ["prerelease"]}`, expected: false},
+		{raw: `{}`, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			if got := IsVariant(test.raw); got != test.expected {
+				t.Fatalf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_Complete(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants bool
+		expected Report
+	}{
+		{
+			name: "without variants",
+			expected: Report{
+				SetCode: "dmu",
+				SetName: "Dominaria United",
+				Cards:   Count{Owned: 2, Total: 5},
+				ByRarity: []RarityCount{
+					{Rarity: "common", Count: Count{Owned: 2, Total: 2}},
+					{Rarity: "uncommon", Count: Count{Total: 1}},
+					{Rarity: "special", Count: Count{Total: 1}},
+					{Rarity: "mythic", Count: Count{Total: 1}},
+				},
+				ByLanguage: []LanguageCount{
+					{LanguageCode: "en", Count: Count{Owned: 1, Total: 5}},
+					{LanguageCode: "es", Count: Count{Owned: 1, Total: 2}},
+				},
+				Missing: []string{"3", "4", "5"},
+			},
+		},
+		{
+			name:     "with variants",
+			variants: true,
+			expected: Report{
+				SetCode:  "dmu",
+				SetName:  "Dominaria United",
+				Variants: true,
+				Cards:    Count{Owned: 3, Total: 6},
+				ByRarity: []RarityCount{
+					{Rarity: "common", Count: Count{Owned: 2, Total: 2}},
+					{Rarity: "uncommon", Count: Count{Total: 1}},
+					{Rarity: "special", Count: Count{Total: 1}},
+					{Rarity: "mythic", Count: Count{Owned: 1, Total: 2}},
+				},
+				ByLanguage: []LanguageCount{
+					{LanguageCode: "en", Count: Count{Owned: 2, Total: 6}},
+					{LanguageCode: "es", Count: Count{Owned: 1, Total: 2}},
+				},
+				Missing: []string{"3", "4", "5"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := Complete("dmu", "Dominaria United", testPrintings, test.variants)
+			if !reflect.DeepEqual(report, test.expected) {
+				t.Fatalf("expected %+v but got %+v", test.expected, report)
+			}
+		})
+	}
+
+	if percent := (Count{Owned: 1, Total: 4}).Percent(); percent != 25 {
+		t.Fatalf("expected 25%% but got %v", percent)
+	}
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const writeBatchSize = 5000
+
+// writeInBatches splits rows in batches written by up to writers goroutines
+// at once, cancelling the rest on the first error. Each batch commits on its
+// own, so batches written before a failure are picked up by the next sync's
+// diff as already in the db.
+func writeInBatches[T any](
+	ctx context.Context,
+	writers int,
+	rows []T,
+	write func(ctx context.Context, batch []T) error,
+) error {
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(max(writers, 1))
+
+	for batch := range slices.Chunk(rows, writeBatchSize) {
+		group.Go(func() error {
+			return write(ctx, batch)
+		})
+	}
+
+	return group.Wait()
+}
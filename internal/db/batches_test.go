@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_WriteInBatches(t *testing.T) {
+	rows := make([]int, writeBatchSize*5+1)
+	for i := range rows {
+		rows[i] = i
+	}
+
+	var mu sync.Mutex
+	written := map[int]bool{}
+	var running, maxRunning atomic.Int32
+
+	err := writeInBatches(context.Background(), 2, rows, func(ctx context.Context, batch []int) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, row := range batch {
+			written[row] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("writing batches failed with error %v", err)
+	}
+
+	if len(written) != len(rows) {
+		t.Fatalf("expected %d rows written but got %d", len(rows), len(written))
+	}
+	if maxRunning.Load() > 2 {
+		t.Fatalf("expected at most 2 concurrent batches but got %d", maxRunning.Load())
+	}
+}
+
+func Test_WriteInBatchesError(t *testing.T) {
+	rows := make([]int, writeBatchSize*3)
+	expectedErr := errors.New("batch failed")
+
+	err := writeInBatches(context.Background(), 1, rows, func(ctx context.Context, batch []int) error {
+		return expectedErr
+	})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected error %v but got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,435 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/completion"
+	"FedeAbella/mtgdb/internal/sqlc"
+	"FedeAbella/mtgdb/internal/valuation"
+)
+
+var (
+	Finishes   = []string{"nonfoil", "foil", "etched"}
+	Conditions = []string{"M", "NM", "LP", "MP", "HP", "DMG"}
+	Currencies = []string{"USD", "EUR"}
+)
+
+var ErrCollectionExists = errors.New("collection already exists")
+
+type InvalidItemError struct {
+	Field   string
+	Value   string
+	Allowed []string
+}
+
+func (e *InvalidItemError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("invalid %s %q", e.Field, e.Value)
+	}
+
+	return fmt.Sprintf("invalid %s %q, expected one of %s", e.Field, e.Value, strings.Join(e.Allowed, ", "))
+}
+
+// NewCollectionItem is a lot of copies of a printing being added. An empty
+// language defaults to the printing's.
+type NewCollectionItem struct {
+	ScryfallID         pgtype.UUID
+	Quantity           int32
+	Finish             string
+	Condition          string
+	LanguageCode       string
+	AcquiredPriceCents pgtype.Int8
+	AcquiredCurrency   pgtype.Text
+	AcquiredOn         pgtype.Date
+}
+
+func (item NewCollectionItem) validate() error {
+	if item.Quantity <= 0 {
+		return &InvalidItemError{Field: "quantity", Value: fmt.Sprint(item.Quantity)}
+	}
+	if !slices.Contains(Finishes, item.Finish) {
+		return &InvalidItemError{Field: "finish", Value: item.Finish, Allowed: Finishes}
+	}
+	if !slices.Contains(Conditions, item.Condition) {
+		return &InvalidItemError{Field: "condition", Value: item.Condition, Allowed: Conditions}
+	}
+	if item.AcquiredCurrency.Valid && !slices.Contains(Currencies, item.AcquiredCurrency.String) {
+		return &InvalidItemError{Field: "currency", Value: item.AcquiredCurrency.String, Allowed: Currencies}
+	}
+
+	return nil
+}
+
+// Collections records the printings owned. Each item is a lot added at once,
+// so lots of the same printing bought at different prices stay apart.
+type Collections struct {
+	Store CollectionStore
+}
+
+func timestampNow() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+}
+
+func (c *Collections) Create(ctx context.Context, name string) (sqlc.Collection, error) {
+	_, err := c.Store.GetCollection(ctx, name)
+	if err == nil {
+		return sqlc.Collection{}, fmt.Errorf("%w: %s", ErrCollectionExists, name)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Println(err)
+		return sqlc.Collection{}, err
+	}
+
+	return c.Store.CreateCollection(ctx, name)
+}
+
+func (c *Collections) get(ctx context.Context, store CollectionStore, name string) (sqlc.Collection, error) {
+	collection, err := store.GetCollection(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return sqlc.Collection{}, fmt.Errorf("collection %s %w", name, ErrNotFound)
+	}
+
+	return collection, err
+}
+
+// SetOwner makes owner the owner of the collection, whose copies can then be
+// traded away. An empty owner leaves the collection without one.
+func (c *Collections) SetOwner(ctx context.Context, collectionName string, owner string) error {
+	collection, err := c.get(ctx, c.Store, collectionName)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return c.Store.UpdateCollectionOwner(ctx, sqlc.UpdateCollectionOwnerParams{
+		ID:    collection.ID,
+		Owner: pgtype.Text{String: owner, Valid: owner != ""},
+	})
+}
+
+func (c *Collections) Add(ctx context.Context, collectionName string, item NewCollectionItem) (sqlc.CollectionItem, error) {
+	if err := item.validate(); err != nil {
+		return sqlc.CollectionItem{}, err
+	}
+
+	collection, err := c.get(ctx, c.Store, collectionName)
+	if err != nil {
+		log.Println(err)
+		return sqlc.CollectionItem{}, err
+	}
+
+	return c.add(ctx, c.Store, collection.ID, item)
+}
+
+// AddAll adds the items to the collection in a single transaction, adding
+// none if any of them is invalid.
+func (c *Collections) AddAll(ctx context.Context, collectionName string, items []NewCollectionItem) error {
+	for _, item := range items {
+		if err := item.validate(); err != nil {
+			return err
+		}
+	}
+
+	return c.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(CollectionStore)
+
+		collection, err := c.get(ctx, store, collectionName)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		for _, item := range items {
+			if _, err := c.add(ctx, store, collection.ID, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (c *Collections) add(
+	ctx context.Context,
+	store CollectionStore,
+	collectionID int64,
+	item NewCollectionItem,
+) (sqlc.CollectionItem, error) {
+	card, err := store.GetCard(ctx, item.ScryfallID)
+	if errors.Is(err, ErrNotFound) {
+		return sqlc.CollectionItem{}, fmt.Errorf("card %s %w", uuid.UUID(item.ScryfallID.Bytes), ErrNotFound)
+	}
+	if err != nil {
+		log.Println(err)
+		return sqlc.CollectionItem{}, err
+	}
+
+	if item.LanguageCode == "" {
+		item.LanguageCode = card.Card.LanguageCode
+	}
+
+	timestamp := timestampNow()
+	return store.InsertCollectionItem(ctx, sqlc.InsertCollectionItemParams{
+		CollectionID:       collectionID,
+		ScryfallID:         item.ScryfallID,
+		Quantity:           item.Quantity,
+		Finish:             item.Finish,
+		Condition:          item.Condition,
+		LanguageCode:       item.LanguageCode,
+		AcquiredPriceCents: item.AcquiredPriceCents,
+		AcquiredCurrency:   item.AcquiredCurrency,
+		AcquiredOn:         item.AcquiredOn,
+		CreatedAt:          timestamp,
+		UpdatedAt:          timestamp,
+	})
+}
+
+func (c *Collections) getItem(ctx context.Context, store CollectionStore, id int64) (sqlc.CollectionItem, error) {
+	item, err := store.GetCollectionItem(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return sqlc.CollectionItem{}, fmt.Errorf("collection item %d %w", id, ErrNotFound)
+	}
+
+	return item, err
+}
+
+// Remove takes quantity copies out of an item, deleting it if none are left.
+// A quantity of 0 removes them all.
+func (c *Collections) Remove(ctx context.Context, itemID int64, quantity int32) error {
+	return c.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(CollectionStore)
+
+		item, err := c.getItem(ctx, store, itemID)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		if quantity <= 0 || quantity >= item.Quantity {
+			return store.DeleteCollectionItem(ctx, itemID)
+		}
+
+		return store.UpdateCollectionItem(ctx, sqlc.UpdateCollectionItemParams{
+			ID:           item.ID,
+			CollectionID: item.CollectionID,
+			Quantity:     item.Quantity - quantity,
+			UpdatedAt:    timestampNow(),
+		})
+	})
+}
+
+// Move moves quantity copies of an item into another collection, splitting it
+// if only some of them move. A quantity of 0 moves them all. It returns the
+// item holding the moved copies.
+func (c *Collections) Move(
+	ctx context.Context,
+	itemID int64,
+	collectionName string,
+	quantity int32,
+) (sqlc.CollectionItem, error) {
+	var moved sqlc.CollectionItem
+
+	err := c.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(CollectionStore)
+
+		item, err := c.getItem(ctx, store, itemID)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		collection, err := c.get(ctx, store, collectionName)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		timestamp := timestampNow()
+		if quantity <= 0 || quantity >= item.Quantity {
+			moved = item
+			moved.CollectionID = collection.ID
+			moved.UpdatedAt = timestamp
+
+			return store.UpdateCollectionItem(ctx, sqlc.UpdateCollectionItemParams{
+				ID:           item.ID,
+				CollectionID: collection.ID,
+				Quantity:     item.Quantity,
+				UpdatedAt:    timestamp,
+			})
+		}
+
+		err = store.UpdateCollectionItem(ctx, sqlc.UpdateCollectionItemParams{
+			ID:           item.ID,
+			CollectionID: item.CollectionID,
+			Quantity:     item.Quantity - quantity,
+			UpdatedAt:    timestamp,
+		})
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		moved, err = store.InsertCollectionItem(ctx, sqlc.InsertCollectionItemParams{
+			CollectionID:       collection.ID,
+			ScryfallID:         item.ScryfallID,
+			Quantity:           quantity,
+			Finish:             item.Finish,
+			Condition:          item.Condition,
+			LanguageCode:       item.LanguageCode,
+			AcquiredPriceCents: item.AcquiredPriceCents,
+			AcquiredCurrency:   item.AcquiredCurrency,
+			AcquiredOn:         item.AcquiredOn,
+			CreatedAt:          item.CreatedAt,
+			UpdatedAt:          timestamp,
+		})
+		return err
+	})
+
+	return moved, err
+}
+
+func (c *Collections) List(ctx context.Context, collectionName string) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	collection, err := c.get(ctx, c.Store, collectionName)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return c.Store.GetCollectionItems(ctx, collection.ID)
+}
+
+// Value reports the value of the collection at the latest prices synced,
+// compared with the prices recorded by since if it is valid.
+func (c *Collections) Value(
+	ctx context.Context,
+	collectionName string,
+	top int,
+	since pgtype.Date,
+) (valuation.Report, error) {
+	collection, err := c.get(ctx, c.Store, collectionName)
+	if err != nil {
+		log.Println(err)
+		return valuation.Report{}, err
+	}
+
+	rows, err := c.Store.GetCollectionItems(ctx, collection.ID)
+	if err != nil {
+		log.Println(err)
+		return valuation.Report{}, err
+	}
+
+	previous := map[[16]byte]*valuation.Prices{}
+	if since.Valid {
+		recorded, err := c.Store.GetCollectionPricesOn(ctx, collection.ID, since)
+		if err != nil {
+			log.Println(err)
+			return valuation.Report{}, err
+		}
+
+		for _, p := range recorded {
+			previous[p.ScryfallID.Bytes] = &valuation.Prices{
+				USD:       p.UsdCents,
+				USDFoil:   p.UsdFoilCents,
+				USDEtched: p.UsdEtchedCents,
+				EUR:       p.EurCents,
+				EURFoil:   p.EurFoilCents,
+			}
+		}
+	}
+
+	items := make([]valuation.Item, 0, len(rows))
+	for _, row := range rows {
+		prices, err := valuation.ParsePrices(row.Card.Raw)
+		if err != nil {
+			log.Println(err)
+			return valuation.Report{}, err
+		}
+
+		items = append(items, valuation.Item{
+			ScryfallID:      uuid.UUID(row.Card.ScryfallID.Bytes),
+			Name:            row.Card.Name,
+			SetCode:         row.SetCode,
+			SetName:         row.SetName,
+			CollectorNumber: row.Card.CollectorNumber,
+			LanguageCode:    row.CollectionItem.LanguageCode,
+			Finish:          row.CollectionItem.Finish,
+			Quantity:        int(row.CollectionItem.Quantity),
+			Prices:          prices,
+			Previous:        previous[row.Card.ScryfallID.Bytes],
+		})
+	}
+
+	return valuation.Value(items, top, since), nil
+}
+
+// Completion reports how much of the set the collection owns, or every
+// collection if collectionName is empty. A printing counts as owned in its own
+// language, whatever the language recorded for the copies.
+func (c *Collections) Completion(
+	ctx context.Context,
+	setCode string,
+	collectionName string,
+	variants bool,
+) (completion.Report, error) {
+	set, err := c.Store.GetSet(ctx, setCode)
+	if errors.Is(err, ErrNotFound) {
+		return completion.Report{}, fmt.Errorf("set %s %w", setCode, ErrNotFound)
+	}
+	if err != nil {
+		log.Println(err)
+		return completion.Report{}, err
+	}
+
+	collections := []sqlc.Collection{}
+	if collectionName == "" {
+		collections, err = c.Store.GetAllCollections(ctx)
+	} else {
+		var collection sqlc.Collection
+		collection, err = c.get(ctx, c.Store, collectionName)
+		collections = append(collections, collection)
+	}
+	if err != nil {
+		log.Println(err)
+		return completion.Report{}, err
+	}
+
+	owned := map[[16]byte]bool{}
+	for _, collection := range collections {
+		items, err := c.Store.GetCollectionItems(ctx, collection.ID)
+		if err != nil {
+			log.Println(err)
+			return completion.Report{}, err
+		}
+
+		for _, item := range items {
+			owned[item.Card.ScryfallID.Bytes] = true
+		}
+	}
+
+	printings := []completion.Printing{}
+	err = c.Store.GetAllCardsWithSets(ctx, CardFilter{SetCode: set.Code}, func(card CardWithSet) error {
+		printings = append(printings, completion.Printing{
+			CollectorNumber: card.Card.CollectorNumber,
+			LanguageCode:    card.Card.LanguageCode,
+			Rarity:          card.Card.Rarity.String,
+			Variant:         completion.IsVariant(card.Card.Raw),
+			Owned:           owned[card.Card.ScryfallID.Bytes],
+		})
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+		return completion.Report{}, err
+	}
+
+	return completion.Complete(set.Code, set.Name, printings, variants), nil
+}
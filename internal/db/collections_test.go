@@ -0,0 +1,249 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	syncFixtureCardAID = pgtype.UUID{Bytes: uuid.MustParse("00000000-0000-4000-8000-000000000001"), Valid: true}
+	syncFixtureCardBID = pgtype.UUID{Bytes: uuid.MustParse("00000000-0000-4000-8000-000000000002"), Valid: true}
+)
+
+func quantities(t *testing.T, collections *Collections, name string) map[string]int32 {
+	t.Helper()
+
+	items, err := collections.List(context.Background(), name)
+	if err != nil {
+		t.Fatalf("listing %s failed with error %v", name, err)
+	}
+
+	got := map[string]int32{}
+	for _, item := range items {
+		got[item.Card.Name+" "+item.CollectionItem.Finish] += item.CollectionItem.Quantity
+	}
+
+	return got
+}
+
+func expectQuantities(t *testing.T, collections *Collections, name string, expected map[string]int32) {
+	t.Helper()
+
+	got := quantities(t, collections, name)
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v in %s but got %v", expected, name, got)
+	}
+	for key, quantity := range expected {
+		if got[key] != quantity {
+			t.Fatalf("expected %v in %s but got %v", expected, name, got)
+		}
+	}
+}
+
+func Test_Collections(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	dbConf := DbConf{Store: store, Workers: 1}
+	collections := &Collections{Store: store}
+
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA, syncFixtureCardB)); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+
+	for _, name := range []string{"binder", "trade"} {
+		if _, err := collections.Create(ctx, name); err != nil {
+			t.Fatalf("creating %s failed with error %v", name, err)
+		}
+	}
+	if _, err := collections.Create(ctx, "binder"); !errors.Is(err, ErrCollectionExists) {
+		t.Fatalf("expected ErrCollectionExists but got %v", err)
+	}
+
+	foil, err := collections.Add(ctx, "binder", NewCollectionItem{
+		ScryfallID:         syncFixtureCardAID,
+		Quantity:           4,
+		Finish:             "foil",
+		Condition:          "NM",
+		AcquiredPriceCents: pgtype.Int8{Int64: 150, Valid: true},
+		AcquiredCurrency:   pgtype.Text{String: "USD", Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("adding item failed with error %v", err)
+	}
+	if foil.LanguageCode != "en" {
+		t.Fatalf("expected the language to default to the printing's, got %q", foil.LanguageCode)
+	}
+
+	spanish, err := collections.Add(ctx, "binder", NewCollectionItem{
+		ScryfallID: syncFixtureCardBID,
+		Quantity:   2,
+		Finish:     "nonfoil",
+		Condition:  "LP",
+	})
+	if err != nil {
+		t.Fatalf("adding item failed with error %v", err)
+	}
+
+	invalid := []NewCollectionItem{
+		{ScryfallID: syncFixtureCardAID, Quantity: 1, Finish: "shiny", Condition: "NM"},
+		{ScryfallID: syncFixtureCardAID, Quantity: 1, Finish: "foil", Condition: "mint"},
+		{ScryfallID: syncFixtureCardAID, Quantity: 0, Finish: "foil", Condition: "NM"},
+	}
+	for _, item := range invalid {
+		var invalidErr *InvalidItemError
+		if _, err := collections.Add(ctx, "binder", item); !errors.As(err, &invalidErr) {
+			t.Fatalf("expected InvalidItemError for %+v but got %v", item, err)
+		}
+	}
+
+	unknownCard := NewCollectionItem{
+		ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Quantity:   1,
+		Finish:     "foil",
+		Condition:  "NM",
+	}
+	if _, err := collections.Add(ctx, "binder", unknownCard); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown card but got %v", err)
+	}
+
+	moved, err := collections.Move(ctx, foil.ID, "trade", 1)
+	if err != nil {
+		t.Fatalf("moving item failed with error %v", err)
+	}
+	if moved.ID == foil.ID || moved.AcquiredPriceCents.Int64 != 150 {
+		t.Fatalf("expected a split item keeping the acquired price, got %+v", moved)
+	}
+	if _, err := collections.Move(ctx, spanish.ID, "trade", 0); err != nil {
+		t.Fatalf("moving item failed with error %v", err)
+	}
+	if err := collections.Remove(ctx, foil.ID, 1); err != nil {
+		t.Fatalf("removing item failed with error %v", err)
+	}
+
+	expectQuantities(t, collections, "binder", map[string]int32{"Card A foil": 2})
+	expectQuantities(t, collections, "trade", map[string]int32{"Card A foil": 1, "Card B nonfoil": 2})
+
+	// Syncing an update to an owned card keeps the items
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA2, syncFixtureCardB)); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+	expectQuantities(t, collections, "binder", map[string]int32{"Card A foil": 2})
+
+	if err := collections.Remove(ctx, foil.ID, 0); err != nil {
+		t.Fatalf("removing item failed with error %v", err)
+	}
+	expectQuantities(t, collections, "binder", map[string]int32{})
+
+	if err := collections.Remove(ctx, foil.ID, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound removing a removed item but got %v", err)
+	}
+}
+
+func Test_CollectionsValue(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	dbConf := DbConf{Store: store, Workers: 1}
+	collections := &Collections{Store: store}
+
+	priced := func(prices string) string {
+		return strings.Replace(syncFixtureCardA, `"rarity"`, `"prices": `+prices+`, "rarity"`, 1)
+	}
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, priced(`{"usd": "1.00", "usd_foil": "4.00", "eur": "0.80"}`))); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+
+	if _, err := collections.Create(ctx, "binder"); err != nil {
+		t.Fatalf("creating collection failed with error %v", err)
+	}
+	for _, finish := range []string{"nonfoil", "foil"} {
+		item := NewCollectionItem{ScryfallID: syncFixtureCardAID, Quantity: 2, Finish: finish, Condition: "NM"}
+		if _, err := collections.Add(ctx, "binder", item); err != nil {
+			t.Fatalf("adding item failed with error %v", err)
+		}
+	}
+
+	since := pgtype.Date{Time: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	recorded, err := store.RecordCollectionPrices(ctx, since)
+	if err != nil || recorded != 1 {
+		t.Fatalf("expected the owned printing's prices recorded, got %d and error %v", recorded, err)
+	}
+
+	summary, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, priced(`{"usd": "1.50", "usd_foil": "3.00", "eur": "0.80"}`)))
+	if err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+	if summary.PricesRecorded != 1 {
+		t.Fatalf("expected the sync to record prices, got %+v", summary)
+	}
+
+	before := pgtype.Date{Time: since.Time.AddDate(0, 0, -1), Valid: true}
+	if prices, err := store.GetCollectionPricesOn(ctx, 1, before); err != nil || len(prices) != 0 {
+		t.Fatalf("expected no prices recorded before %v, got %+v and error %v", before.Time, prices, err)
+	}
+
+	report, err := collections.Value(ctx, "binder", 1, since)
+	if err != nil {
+		t.Fatalf("valuing collection failed with error %v", err)
+	}
+	if report.Value.USD != 900 || report.Value.EUR != 160 || report.UnpricedEUR != 2 {
+		t.Fatalf("expected 9.00 USD and 1.60 EUR with the foils unpriced in EUR, got %+v", report)
+	}
+	if len(report.Top) != 1 || report.Top[0].Finish != "foil" {
+		t.Fatalf("expected the foils on top, got %+v", report.Top)
+	}
+	if report.Change == nil || report.Change.Delta.USD != -100 || report.Change.Delta.EUR != 0 {
+		t.Fatalf("expected a change of -1.00 USD, got %+v", report.Change)
+	}
+
+	if _, err := collections.Value(ctx, "deck", 1, since); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown collection but got %v", err)
+	}
+}
+
+func Test_CollectionsCompletion(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	dbConf := DbConf{Store: store, Workers: 1}
+	collections := &Collections{Store: store}
+
+	variant := strings.Replace(syncFixtureCardC, `"rarity"`, `"variation": true, "rarity"`, 1)
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA, syncFixtureCardB, variant)); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+
+	for _, name := range []string{"binder", "trade"} {
+		if _, err := collections.Create(ctx, name); err != nil {
+			t.Fatalf("creating %s failed with error %v", name, err)
+		}
+	}
+	item := NewCollectionItem{ScryfallID: syncFixtureCardAID, Quantity: 1, Finish: "nonfoil", Condition: "NM"}
+	if _, err := collections.Add(ctx, "binder", item); err != nil {
+		t.Fatalf("adding item failed with error %v", err)
+	}
+
+	report, err := collections.Completion(ctx, "s1", "", false)
+	if err != nil {
+		t.Fatalf("completion failed with error %v", err)
+	}
+	if report.SetName != "Set 1" || report.Cards.Owned != 1 || report.Cards.Total != 2 || fmt.Sprint(report.Missing) != "[2]" {
+		t.Fatalf("expected 1 of 2 cards owned with 2 missing, got %+v", report)
+	}
+
+	if report, _ := collections.Completion(ctx, "s1", "trade", true); report.Cards.Owned != 0 || report.Cards.Total != 3 {
+		t.Fatalf("expected none of 3 cards owned in trade, got %+v", report)
+	}
+
+	if _, err := collections.Completion(ctx, "s2", "", false); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown set but got %v", err)
+	}
+	if _, err := collections.Completion(ctx, "s1", "deck", false); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown collection but got %v", err)
+	}
+}
@@ -0,0 +1,352 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var ErrDeckExists = errors.New("deck already exists")
+
+// NewDeckCard is a card being added to a deck, by printing if ScryfallID is
+// valid and by oracle id otherwise. An empty finish is nonfoil.
+type NewDeckCard struct {
+	Zone             deck.Zone
+	Quantity         int32
+	ScryfallOracleID pgtype.UUID
+	ScryfallID       pgtype.UUID
+	Finish           string
+}
+
+func (card NewDeckCard) validate() error {
+	if !slices.Contains(deck.Zones, card.Zone) {
+		return &InvalidItemError{Field: "zone", Value: card.Zone, Allowed: deck.Zones}
+	}
+	if card.Quantity <= 0 {
+		return &InvalidItemError{Field: "quantity", Value: fmt.Sprint(card.Quantity)}
+	}
+	if !slices.Contains(Finishes, card.Finish) {
+		return &InvalidItemError{Field: "finish", Value: card.Finish, Allowed: Finishes}
+	}
+
+	return nil
+}
+
+// DeckEntry is a deck card along with its printing, or the first printing of
+// the card if the deck doesn't name one.
+type DeckEntry struct {
+	DeckCard sqlc.DeckCard
+	Card     CardWithSet
+}
+
+// Decks keeps decklists, each entry a card in one of the deck's zones.
+type Decks struct {
+	Store DeckStore
+}
+
+func (d *Decks) Create(ctx context.Context, name string, format string) (sqlc.Deck, error) {
+	if !slices.Contains(deck.Formats(), format) {
+		return sqlc.Deck{}, &deck.UnknownFormatError{Format: format}
+	}
+
+	_, err := d.Store.GetDeck(ctx, name)
+	if err == nil {
+		return sqlc.Deck{}, fmt.Errorf("%w: %s", ErrDeckExists, name)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Println(err)
+		return sqlc.Deck{}, err
+	}
+
+	return d.Store.CreateDeck(ctx, name, format)
+}
+
+func (d *Decks) get(ctx context.Context, store DeckStore, name string) (sqlc.Deck, error) {
+	stored, err := store.GetDeck(ctx, name)
+	if errors.Is(err, ErrNotFound) {
+		return sqlc.Deck{}, fmt.Errorf("deck %s %w", name, ErrNotFound)
+	}
+
+	return stored, err
+}
+
+// resolve fills in the oracle id of a card added by printing, and checks the
+// card exists either way.
+func (d *Decks) resolve(ctx context.Context, store DeckStore, card NewDeckCard) (NewDeckCard, error) {
+	oracleID, err := resolveOracleID(ctx, store, card.ScryfallID, card.ScryfallOracleID)
+	card.ScryfallOracleID = oracleID
+
+	return card, err
+}
+
+// resolveOracleID returns the oracle id of the printing if scryfallID is
+// valid, and oracleID otherwise, checking the card exists either way.
+func resolveOracleID(ctx context.Context, store Store, scryfallID pgtype.UUID, oracleID pgtype.UUID) (pgtype.UUID, error) {
+	if scryfallID.Valid {
+		printing, err := store.GetCard(ctx, scryfallID)
+		if errors.Is(err, ErrNotFound) {
+			return oracleID, fmt.Errorf("card %s %w", uuid.UUID(scryfallID.Bytes), ErrNotFound)
+		}
+		if err != nil {
+			return oracleID, err
+		}
+
+		return printing.Card.ScryfallOracleID, nil
+	}
+
+	printings, err := store.GetOraclePrintings(ctx, oracleID, Page{Limit: 1})
+	if err != nil {
+		return oracleID, err
+	}
+	if len(printings) == 0 {
+		return oracleID, fmt.Errorf("oracle card %s %w", uuid.UUID(oracleID.Bytes), ErrNotFound)
+	}
+
+	return oracleID, nil
+}
+
+// printingOf returns the printing if scryfallID is valid, and the first
+// printing of the oracle card otherwise.
+func printingOf(ctx context.Context, store Store, scryfallID pgtype.UUID, oracleID pgtype.UUID) (CardWithSet, error) {
+	if scryfallID.Valid {
+		return store.GetCard(ctx, scryfallID)
+	}
+
+	printings, err := store.GetOraclePrintings(ctx, oracleID, Page{Limit: 1})
+	if err != nil {
+		return CardWithSet{}, err
+	}
+	if len(printings) == 0 {
+		return CardWithSet{}, fmt.Errorf("oracle card %s %w", uuid.UUID(oracleID.Bytes), ErrNotFound)
+	}
+
+	return printings[0], nil
+}
+
+// Add adds the card to the deck, adding to the quantity of an entry for the
+// same card, printing and finish in the same zone if there is one.
+func (d *Decks) Add(ctx context.Context, deckName string, card NewDeckCard) (sqlc.DeckCard, error) {
+	if card.Finish == "" {
+		card.Finish = deck.FinishNonfoil
+	}
+	if err := card.validate(); err != nil {
+		return sqlc.DeckCard{}, err
+	}
+
+	var added sqlc.DeckCard
+	err := d.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(DeckStore)
+
+		stored, err := d.get(ctx, store, deckName)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		added, err = d.add(ctx, store, stored.ID, card)
+		return err
+	})
+
+	return added, err
+}
+
+func (d *Decks) add(ctx context.Context, store DeckStore, deckID int64, card NewDeckCard) (sqlc.DeckCard, error) {
+	card, err := d.resolve(ctx, store, card)
+	if err != nil {
+		log.Println(err)
+		return sqlc.DeckCard{}, err
+	}
+
+	entries, err := store.GetDeckCards(ctx, deckID)
+	if err != nil {
+		log.Println(err)
+		return sqlc.DeckCard{}, err
+	}
+
+	timestamp := timestampNow()
+	for _, entry := range entries {
+		if entry.Zone == card.Zone &&
+			entry.ScryfallOracleID == card.ScryfallOracleID &&
+			entry.ScryfallID == card.ScryfallID &&
+			entry.Finish == card.Finish {
+			entry.Quantity += card.Quantity
+			entry.UpdatedAt = timestamp
+
+			return entry, store.UpdateDeckCard(ctx, sqlc.UpdateDeckCardParams{
+				ID:        entry.ID,
+				Quantity:  entry.Quantity,
+				UpdatedAt: timestamp,
+			})
+		}
+	}
+
+	return store.InsertDeckCard(ctx, sqlc.InsertDeckCardParams{
+		DeckID:           deckID,
+		Zone:             card.Zone,
+		Quantity:         card.Quantity,
+		ScryfallOracleID: card.ScryfallOracleID,
+		ScryfallID:       card.ScryfallID,
+		CreatedAt:        timestamp,
+		UpdatedAt:        timestamp,
+		Finish:           card.Finish,
+	})
+}
+
+// Import resolves the lines of a decklist and, unless dryRun, adds the
+// resolved ones to the deck in a single transaction. Lines resolved by fuzzy
+// name are added by card rather than printing. The resolutions are returned in
+// the order of the lines, including the unresolved and ambiguous ones.
+func (d *Decks) Import(ctx context.Context, deckName string, decklist deck.Decklist, dryRun bool) ([]Resolution, error) {
+	resolver := &Resolver{Store: d.Store}
+
+	resolutions := make([]Resolution, 0, len(decklist.Lines))
+	for _, line := range decklist.Lines {
+		resolution, err := resolver.Resolve(ctx, line)
+		if err != nil {
+			return nil, err
+		}
+
+		resolutions = append(resolutions, resolution)
+	}
+
+	if dryRun {
+		return resolutions, nil
+	}
+
+	err := d.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(DeckStore)
+
+		stored, err := d.get(ctx, store, deckName)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		for _, resolution := range resolutions {
+			if !resolution.Resolved() {
+				continue
+			}
+
+			card := NewDeckCard{
+				Zone:     resolution.Line.Zone,
+				Quantity: int32(resolution.Line.Quantity),
+				Finish:   resolution.Line.Finish,
+			}
+			if resolution.Method == MatchName {
+				card.ScryfallOracleID = resolution.Card.Card.ScryfallOracleID
+			} else {
+				card.ScryfallID = resolution.Card.Card.ScryfallID
+			}
+
+			if err := card.validate(); err != nil {
+				return err
+			}
+			if _, err := d.add(ctx, store, stored.ID, card); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return resolutions, err
+}
+
+// Remove takes quantity copies of an entry out of the deck, deleting it if
+// none are left. A quantity of 0 removes them all.
+func (d *Decks) Remove(ctx context.Context, deckName string, entryID int64, quantity int32) error {
+	return d.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(DeckStore)
+
+		stored, err := d.get(ctx, store, deckName)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		entries, err := store.GetDeckCards(ctx, stored.ID)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		i := slices.IndexFunc(entries, func(entry sqlc.DeckCard) bool { return entry.ID == entryID })
+		if i < 0 {
+			return fmt.Errorf("entry %d of deck %s %w", entryID, deckName, ErrNotFound)
+		}
+
+		if quantity <= 0 || quantity >= entries[i].Quantity {
+			return store.DeleteDeckCard(ctx, entryID)
+		}
+
+		return store.UpdateDeckCard(ctx, sqlc.UpdateDeckCardParams{
+			ID:        entryID,
+			Quantity:  entries[i].Quantity - quantity,
+			UpdatedAt: timestampNow(),
+		})
+	})
+}
+
+// List returns the deck with its entries, ordered by zone as decklists print
+// them.
+func (d *Decks) List(ctx context.Context, deckName string) (sqlc.Deck, []DeckEntry, error) {
+	stored, err := d.get(ctx, d.Store, deckName)
+	if err != nil {
+		log.Println(err)
+		return sqlc.Deck{}, nil, err
+	}
+
+	cards, err := d.Store.GetDeckCards(ctx, stored.ID)
+	if err != nil {
+		log.Println(err)
+		return sqlc.Deck{}, nil, err
+	}
+
+	entries := make([]DeckEntry, 0, len(cards))
+	for _, card := range cards {
+		printing, err := printingOf(ctx, d.Store, card.ScryfallID, card.ScryfallOracleID)
+		if err != nil {
+			log.Println(err)
+			return sqlc.Deck{}, nil, err
+		}
+
+		entries = append(entries, DeckEntry{DeckCard: card, Card: printing})
+	}
+
+	slices.SortStableFunc(entries, func(a, b DeckEntry) int {
+		return slices.Index(deck.Zones, a.DeckCard.Zone) - slices.Index(deck.Zones, b.DeckCard.Zone)
+	})
+
+	return stored, entries, nil
+}
+
+// Validate checks the deck against the rules of its format, returning nothing
+// if it is legal.
+func (d *Decks) Validate(ctx context.Context, deckName string) ([]deck.Violation, error) {
+	stored, entries, err := d.List(ctx, deckName)
+	if err != nil {
+		return nil, err
+	}
+
+	cards := make([]deck.Card, 0, len(entries))
+	for _, entry := range entries {
+		cards = append(cards, deck.Card{
+			Zone:          entry.DeckCard.Zone,
+			Quantity:      int(entry.DeckCard.Quantity),
+			Name:          entry.Card.Card.Name,
+			TypeLine:      entry.Card.Card.TypeLine,
+			ColorIdentity: entry.Card.Card.ColorIdentity.String,
+			Raw:           entry.Card.Card.Raw,
+		})
+	}
+
+	return deck.Validate(stored.Format, cards)
+}
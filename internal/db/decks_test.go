@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+var syncFixtureCardCOracleID = pgtype.UUID{Bytes: uuid.MustParse("10000000-0000-4000-8000-000000000003"), Valid: true}
+
+func Test_Decks(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	dbConf := DbConf{Store: store, Workers: 1}
+	decks := &Decks{Store: store}
+
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA, syncFixtureCardC)); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+
+	var formatErr *deck.UnknownFormatError
+	if _, err := decks.Create(ctx, "burn", "two-headed"); !errors.As(err, &formatErr) {
+		t.Fatalf("expected UnknownFormatError but got %v", err)
+	}
+	if _, err := decks.Create(ctx, "burn", "modern"); err != nil {
+		t.Fatalf("creating deck failed with error %v", err)
+	}
+	if _, err := decks.Create(ctx, "burn", "modern"); !errors.Is(err, ErrDeckExists) {
+		t.Fatalf("expected ErrDeckExists but got %v", err)
+	}
+
+	adds := []NewDeckCard{
+		{Zone: deck.ZoneMain, Quantity: 2, ScryfallID: syncFixtureCardAID},
+		{Zone: deck.ZoneMain, Quantity: 3, ScryfallID: syncFixtureCardAID},
+		{Zone: deck.ZoneSideboard, Quantity: 1, ScryfallOracleID: syncFixtureCardCOracleID},
+	}
+	for _, card := range adds {
+		if _, err := decks.Add(ctx, "burn", card); err != nil {
+			t.Fatalf("adding %+v failed with error %v", card, err)
+		}
+	}
+
+	unknown := NewDeckCard{Zone: deck.ZoneMain, Quantity: 1, ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}
+	if _, err := decks.Add(ctx, "burn", unknown); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown card but got %v", err)
+	}
+	var invalidErr *InvalidItemError
+	if _, err := decks.Add(ctx, "burn", NewDeckCard{Zone: "graveyard", Quantity: 1, ScryfallID: syncFixtureCardAID}); !errors.As(err, &invalidErr) {
+		t.Fatalf("expected InvalidItemError for an unknown zone but got %v", err)
+	}
+
+	_, entries, err := decks.List(ctx, "burn")
+	if err != nil {
+		t.Fatalf("listing deck failed with error %v", err)
+	}
+	if len(entries) != 2 || entries[0].DeckCard.Quantity != 5 || entries[1].Card.Card.Name != "Card C" {
+		t.Fatalf("expected 5 Card A in the main deck and Card C in the sideboard, got %+v", entries)
+	}
+	if entries[0].DeckCard.ScryfallOracleID != entries[0].Card.Card.ScryfallOracleID {
+		t.Fatalf("expected the oracle id of a card added by printing to be filled in")
+	}
+
+	if err := decks.Remove(ctx, "burn", entries[0].DeckCard.ID, 1); err != nil {
+		t.Fatalf("removing entry failed with error %v", err)
+	}
+
+	// The fixtures carry no legalities, so the cards aren't legal anywhere
+	violations, err := decks.Validate(ctx, "burn")
+	if err != nil {
+		t.Fatalf("validating deck failed with error %v", err)
+	}
+	got := []string{}
+	for _, violation := range violations {
+		got = append(got, violation.String())
+	}
+	expected := []string{
+		"main deck has 4 cards, modern requires at least 60",
+		"Card A: not legal in modern",
+		"Card C: not legal in modern",
+	}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("expected violations %q but got %q", expected, got)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !unix
+
+package db
+
+import (
+	"os"
+	"sync"
+)
+
+// Without flock the lock only guards against syncs in the same process.
+var (
+	fileLocksMu sync.Mutex
+	fileLocks   = map[string]bool{}
+)
+
+func tryLockFile(f *os.File) error {
+	fileLocksMu.Lock()
+	defer fileLocksMu.Unlock()
+
+	if fileLocks[f.Name()] {
+		return ErrSyncInProgress
+	}
+	fileLocks[f.Name()] = true
+
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	fileLocksMu.Lock()
+	defer fileLocksMu.Unlock()
+
+	delete(fileLocks, f.Name())
+
+	return nil
+}
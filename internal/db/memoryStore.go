@@ -0,0 +1,611 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/search"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+type memoryState struct {
+	sets              map[[16]byte]sqlc.Set
+	cards             map[[16]byte]sqlc.Card
+	quarantined       []sqlc.QuarantinedCard
+	lastQuarantinedID int64
+}
+
+// MemoryStore keeps everything in maps, mirroring the constraints of the
+// Postgres schema the sync relies on, so full syncs can be tested without a
+// database. Transactions hold the store's lock until they finish, writing to
+// the state directly and undoing their writes if they fail, so other writes
+// wait for them instead of being lost.
+type MemoryStore struct {
+	mu    *sync.Mutex
+	lock  chan struct{}
+	state *memoryState
+
+	// undo is the log of the transaction the store belongs to, nil outside
+	// transactions.
+	undo *[]func()
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu:   &sync.Mutex{},
+		lock: make(chan struct{}, 1),
+		state: &memoryState{
+			sets:        map[[16]byte]sqlc.Set{},
+			cards:       map[[16]byte]sqlc.Card{},
+			quarantined: []sqlc.QuarantinedCard{},
+		},
+	}
+}
+
+func (s *MemoryStore) GetAllSets(ctx context.Context) ([]sqlc.Set, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sets := slices.Collect(maps.Values(s.state.sets))
+	slices.SortFunc(sets, func(a, b sqlc.Set) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return sets, nil
+}
+
+func (s *MemoryStore) GetAllCards(ctx context.Context) ([]sqlc.GetAllCardsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cards := make([]sqlc.GetAllCardsRow, 0, len(s.state.cards))
+	for _, card := range s.state.cards {
+		cards = append(cards, sqlc.GetAllCardsRow{
+			ScryfallID:       card.ScryfallID,
+			SetID:            card.SetID,
+			Name:             card.Name,
+			CollectorNumber:  card.CollectorNumber,
+			ColorIdentity:    card.ColorIdentity,
+			Colors:           card.Colors,
+			LanguageCode:     card.LanguageCode,
+			SpanishName:      card.SpanishName,
+			Rarity:           card.Rarity,
+			TypeLine:         card.TypeLine,
+			ScryfallApiUri:   card.ScryfallApiUri,
+			ScryfallWebUri:   card.ScryfallWebUri,
+			ScryfallOracleID: card.ScryfallOracleID,
+			CreatedAt:        card.CreatedAt,
+			UpdatedAt:        card.UpdatedAt,
+			RawHash:          card.RawHash,
+		})
+	}
+	slices.SortFunc(cards, func(a, b sqlc.GetAllCardsRow) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return cards, nil
+}
+
+func (s *MemoryStore) withSet(card sqlc.Card) CardWithSet {
+	set := s.state.sets[card.SetID.Bytes]
+	return CardWithSet{Card: card, SetCode: set.Code, SetName: set.Name}
+}
+
+func paginate[T any](items []T, page Page) []T {
+	start := max(0, min(int(page.Offset), len(items)))
+	end := max(start, min(start+int(page.Limit), len(items)))
+
+	return items[start:end]
+}
+
+// leadingNumber is the integer the collector number starts with, 0 if none.
+func leadingNumber(collectorNumber string) int {
+	digits := len(collectorNumber) - len(strings.TrimLeft(collectorNumber, "0123456789"))
+	number, _ := strconv.Atoi(collectorNumber[:digits])
+
+	return number
+}
+
+func (s *MemoryStore) GetCardNames(ctx context.Context) ([]sqlc.GetCardNamesRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[sqlc.GetCardNamesRow]bool{}
+	rows := []sqlc.GetCardNamesRow{}
+	for _, card := range s.state.cards {
+		row := sqlc.GetCardNamesRow{
+			Name:             card.Name,
+			SpanishName:      card.SpanishName,
+			ScryfallOracleID: card.ScryfallOracleID,
+		}
+		if !seen[row] {
+			seen[row] = true
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+func (s *MemoryStore) GetCardsVersion(ctx context.Context) (sqlc.GetCardsVersionRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lastUpdatedAt pgtype.Timestamp
+	for _, card := range s.state.cards {
+		if card.UpdatedAt.Time.After(lastUpdatedAt.Time) {
+			lastUpdatedAt = card.UpdatedAt
+		}
+	}
+
+	version := sqlc.GetCardsVersionRow{CardCount: int64(len(s.state.cards))}
+	if lastUpdatedAt.Valid {
+		version.LastUpdatedAt = lastUpdatedAt.Time.String()
+	}
+
+	return version, nil
+}
+
+func (s *MemoryStore) GetCard(ctx context.Context, scryfallID pgtype.UUID) (CardWithSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	card, ok := s.state.cards[scryfallID.Bytes]
+	if !ok {
+		return CardWithSet{}, ErrNotFound
+	}
+
+	return s.withSet(card), nil
+}
+
+func (s *MemoryStore) GetSet(ctx context.Context, code string) (sqlc.Set, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, set := range s.state.sets {
+		if set.Code == code {
+			return set, nil
+		}
+	}
+
+	return sqlc.Set{}, ErrNotFound
+}
+
+func (s *MemoryStore) GetOraclePrintings(
+	ctx context.Context,
+	oracleID pgtype.UUID,
+	page Page,
+) ([]CardWithSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cards := []CardWithSet{}
+	for _, card := range s.state.cards {
+		if card.ScryfallOracleID == oracleID {
+			cards = append(cards, s.withSet(card))
+		}
+	}
+
+	slices.SortFunc(cards, func(a, b CardWithSet) int {
+		return cmp.Or(
+			strings.Compare(a.SetCode, b.SetCode),
+			strings.Compare(a.Card.CollectorNumber, b.Card.CollectorNumber),
+			strings.Compare(a.Card.LanguageCode, b.Card.LanguageCode),
+		)
+	})
+
+	return paginate(cards, page), nil
+}
+
+func (s *MemoryStore) GetSetCards(ctx context.Context, code string, page Page) ([]CardWithSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cards := []CardWithSet{}
+	for _, card := range s.state.cards {
+		if cardWithSet := s.withSet(card); cardWithSet.SetCode == code {
+			cards = append(cards, cardWithSet)
+		}
+	}
+
+	slices.SortFunc(cards, func(a, b CardWithSet) int {
+		return cmp.Or(
+			cmp.Compare(leadingNumber(a.Card.CollectorNumber), leadingNumber(b.Card.CollectorNumber)),
+			strings.Compare(a.Card.CollectorNumber, b.Card.CollectorNumber),
+			strings.Compare(a.Card.LanguageCode, b.Card.LanguageCode),
+		)
+	})
+
+	return paginate(cards, page), nil
+}
+
+func (s *MemoryStore) GetAllCardsWithSets(ctx context.Context, filter CardFilter, fn func(CardWithSet) error) error {
+	s.mu.Lock()
+	cards := []CardWithSet{}
+	for _, card := range s.state.cards {
+		cardWithSet := s.withSet(card)
+		if (filter.SetCode == "" || cardWithSet.SetCode == filter.SetCode) &&
+			(filter.LanguageCode == "" || card.LanguageCode == filter.LanguageCode) &&
+			(!filter.UpdatedSince.Valid || !card.UpdatedAt.Time.Before(filter.UpdatedSince.Time)) {
+			cards = append(cards, cardWithSet)
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(cards, func(a, b CardWithSet) int {
+		return cmp.Or(
+			strings.Compare(a.SetCode, b.SetCode),
+			cmp.Compare(leadingNumber(a.Card.CollectorNumber), leadingNumber(b.Card.CollectorNumber)),
+			strings.Compare(a.Card.CollectorNumber, b.Card.CollectorNumber),
+			strings.Compare(a.Card.LanguageCode, b.Card.LanguageCode),
+		)
+	})
+
+	for _, card := range cards {
+		if err := fn(card); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) SearchCards(ctx context.Context, query string, page Page) ([]sqlc.SearchCardsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query = search.Normalize(query)
+
+	type match struct {
+		row       sqlc.SearchCardsRow
+		closeness float32
+	}
+	matches := []match{}
+	for _, card := range s.state.cards {
+		names := []string{search.Normalize(card.Name)}
+		if card.SpanishName.Valid {
+			names = append(names, search.Normalize(card.SpanishName.String))
+		}
+
+		var rank, closeness float32
+		for _, name := range names {
+			rank = max(rank, search.WordSimilarity(query, name))
+			closeness = max(closeness, search.Similarity(query, name))
+		}
+		if rank < search.WordSimilarityThreshold {
+			continue
+		}
+
+		set := s.state.sets[card.SetID.Bytes]
+		matches = append(matches, match{
+			row: sqlc.SearchCardsRow{
+				ScryfallID:       card.ScryfallID,
+				SetID:            card.SetID,
+				Name:             card.Name,
+				CollectorNumber:  card.CollectorNumber,
+				ColorIdentity:    card.ColorIdentity,
+				Colors:           card.Colors,
+				LanguageCode:     card.LanguageCode,
+				SpanishName:      card.SpanishName,
+				Rarity:           card.Rarity,
+				TypeLine:         card.TypeLine,
+				ScryfallApiUri:   card.ScryfallApiUri,
+				ScryfallWebUri:   card.ScryfallWebUri,
+				ScryfallOracleID: card.ScryfallOracleID,
+				CreatedAt:        card.CreatedAt,
+				UpdatedAt:        card.UpdatedAt,
+				Raw:              card.Raw,
+				RawHash:          card.RawHash,
+				SetCode:          set.Code,
+				SetName:          set.Name,
+				Rank:             rank,
+			},
+			closeness: closeness,
+		})
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(b.row.Rank, a.row.Rank),
+			cmp.Compare(b.closeness, a.closeness),
+			strings.Compare(a.row.Name, b.row.Name),
+			strings.Compare(a.row.SetCode, b.row.SetCode),
+			strings.Compare(a.row.CollectorNumber, b.row.CollectorNumber),
+			strings.Compare(a.row.LanguageCode, b.row.LanguageCode),
+		)
+	})
+
+	rows := []sqlc.SearchCardsRow{}
+	for _, m := range paginate(matches, page) {
+		rows = append(rows, m.row)
+	}
+
+	return rows, nil
+}
+
+func (s *MemoryStore) InsertSets(ctx context.Context, sets []sqlc.InsertSetsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, set := range sets {
+		createdAt := set.CreatedAt
+		if existing, ok := s.state.sets[set.ScryfallID.Bytes]; ok {
+			createdAt = existing.CreatedAt
+		}
+
+		s.putSet(sqlc.Set{
+			ScryfallID: set.ScryfallID,
+			Code:       set.Code,
+			Name:       set.Name,
+			CreatedAt:  createdAt,
+			UpdatedAt:  set.UpdatedAt,
+		})
+	}
+
+	return int64(len(sets)), nil
+}
+
+func (s *MemoryStore) InsertCards(ctx context.Context, cards []sqlc.InsertCardsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, card := range cards {
+		if _, ok := s.state.sets[card.SetID.Bytes]; !ok {
+			return 0, fmt.Errorf("set %s of card %s not found", card.SetID, card.ScryfallID)
+		}
+	}
+
+	for _, card := range cards {
+		createdAt := card.CreatedAt
+		if existing, ok := s.state.cards[card.ScryfallID.Bytes]; ok {
+			createdAt = existing.CreatedAt
+		}
+
+		s.putCard(sqlc.Card{
+			ScryfallID:       card.ScryfallID,
+			SetID:            card.SetID,
+			Name:             card.Name,
+			CollectorNumber:  card.CollectorNumber,
+			ColorIdentity:    card.ColorIdentity,
+			Colors:           card.Colors,
+			LanguageCode:     card.LanguageCode,
+			SpanishName:      card.SpanishName,
+			Rarity:           card.Rarity,
+			TypeLine:         card.TypeLine,
+			ScryfallApiUri:   card.ScryfallApiUri,
+			ScryfallWebUri:   card.ScryfallWebUri,
+			ScryfallOracleID: card.ScryfallOracleID,
+			CreatedAt:        createdAt,
+			UpdatedAt:        card.UpdatedAt,
+			Raw:              card.Raw,
+			RawHash:          card.RawHash,
+		})
+	}
+
+	return int64(len(cards)), nil
+}
+
+func (s *MemoryStore) InsertQuarantinedCards(
+	ctx context.Context,
+	cards []sqlc.InsertQuarantinedCardsParams,
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rememberQuarantined()
+	for _, card := range cards {
+		s.state.lastQuarantinedID++
+		s.state.quarantined = append(s.state.quarantined, sqlc.QuarantinedCard{
+			ID:         s.state.lastQuarantinedID,
+			ScryfallID: card.ScryfallID,
+			Reason:     card.Reason,
+			Raw:        card.Raw,
+			CreatedAt:  card.CreatedAt,
+		})
+	}
+
+	return int64(len(cards)), nil
+}
+
+func (s *MemoryStore) UpdateSet(ctx context.Context, set sqlc.UpdateSetParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.state.sets[set.ScryfallID.Bytes]
+	if !ok {
+		return nil
+	}
+
+	existing.Code = set.Code
+	existing.Name = set.Name
+	existing.UpdatedAt = set.UpdatedAt
+	s.putSet(existing)
+
+	return nil
+}
+
+func (s *MemoryStore) UpdateCard(ctx context.Context, card sqlc.UpdateCardParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.state.cards[card.ScryfallID.Bytes]
+	if !ok {
+		return nil
+	}
+
+	if _, ok := s.state.sets[card.SetID.Bytes]; !ok {
+		return fmt.Errorf("set %s of card %s not found", card.SetID, card.ScryfallID)
+	}
+
+	existing.SetID = card.SetID
+	existing.Name = card.Name
+	existing.CollectorNumber = card.CollectorNumber
+	existing.ColorIdentity = card.ColorIdentity
+	existing.Colors = card.Colors
+	existing.LanguageCode = card.LanguageCode
+	existing.SpanishName = card.SpanishName
+	existing.Rarity = card.Rarity
+	existing.TypeLine = card.TypeLine
+	existing.ScryfallApiUri = card.ScryfallApiUri
+	existing.ScryfallWebUri = card.ScryfallWebUri
+	existing.ScryfallOracleID = card.ScryfallOracleID
+	existing.UpdatedAt = card.UpdatedAt
+	existing.Raw = card.Raw
+	existing.RawHash = card.RawHash
+	s.putCard(existing)
+
+	return nil
+}
+
+// Deleting a set cascades to its cards, as the foreign key does in Postgres.
+func (s *MemoryStore) DeleteSet(ctx context.Context, scryfallID pgtype.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rememberSet(scryfallID.Bytes)
+	delete(s.state.sets, scryfallID.Bytes)
+	for id, card := range s.state.cards {
+		if card.SetID.Bytes == scryfallID.Bytes {
+			s.rememberCard(id)
+			delete(s.state.cards, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteCard(ctx context.Context, scryfallID pgtype.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rememberCard(scryfallID.Bytes)
+	delete(s.state.cards, scryfallID.Bytes)
+
+	return nil
+}
+
+func (s *MemoryStore) DeleteQuarantinedCards(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rememberQuarantined()
+	s.state.quarantined = []sqlc.QuarantinedCard{}
+
+	return nil
+}
+
+func (s *MemoryStore) GetQuarantinedCards() []sqlc.QuarantinedCard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.state.quarantined)
+}
+
+func (s *MemoryStore) AcquireSyncLock(ctx context.Context, wait bool) error {
+	if !wait {
+		select {
+		case s.lock <- struct{}{}:
+			return nil
+		default:
+			return ErrSyncInProgress
+		}
+	}
+
+	select {
+	case s.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *MemoryStore) ReleaseSyncLock(ctx context.Context) error {
+	select {
+	case <-s.lock:
+	default:
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	undo := []func(){}
+	txStore := &MemoryStore{
+		mu:    &sync.Mutex{},
+		lock:  s.lock,
+		state: s.state,
+		undo:  &undo,
+	}
+
+	if err := fn(txStore); err != nil {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		return err
+	}
+
+	// A nested transaction commits into the one around it, which may still
+	// roll back
+	if s.undo != nil {
+		*s.undo = append(*s.undo, undo...)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) remember(undo func()) {
+	if s.undo != nil {
+		*s.undo = append(*s.undo, undo)
+	}
+}
+
+func (s *MemoryStore) rememberSet(id [16]byte) {
+	previous, existed := s.state.sets[id]
+	s.remember(func() {
+		if existed {
+			s.state.sets[id] = previous
+		} else {
+			delete(s.state.sets, id)
+		}
+	})
+}
+
+func (s *MemoryStore) rememberCard(id [16]byte) {
+	previous, existed := s.state.cards[id]
+	s.remember(func() {
+		if existed {
+			s.state.cards[id] = previous
+		} else {
+			delete(s.state.cards, id)
+		}
+	})
+}
+
+// Appending past the remembered length leaves the remembered slice intact.
+func (s *MemoryStore) rememberQuarantined() {
+	quarantined, lastID := s.state.quarantined, s.state.lastQuarantinedID
+	s.remember(func() {
+		s.state.quarantined, s.state.lastQuarantinedID = quarantined, lastID
+	})
+}
+
+func (s *MemoryStore) putSet(set sqlc.Set) {
+	s.rememberSet(set.ScryfallID.Bytes)
+	s.state.sets[set.ScryfallID.Bytes] = set
+}
+
+func (s *MemoryStore) putCard(card sqlc.Card) {
+	s.rememberCard(card.ScryfallID.Bytes)
+	s.state.cards[card.ScryfallID.Bytes] = card
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func Test_Paginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name     string
+		page     Page
+		expected []int
+	}{
+		{name: "first page", page: Page{Limit: 2}, expected: []int{1, 2}},
+		{name: "last partial page", page: Page{Limit: 2, Offset: 4}, expected: []int{5}},
+		{name: "past the end", page: Page{Limit: 2, Offset: 10}, expected: []int{}},
+		{name: "negative offset", page: Page{Limit: 2, Offset: -4}, expected: []int{1, 2}},
+		{name: "wrapped offset", page: Page{Limit: 2, Offset: math.MinInt32}, expected: []int{1, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if page := paginate(items, test.page); !slices.Equal(page, test.expected) {
+				t.Fatalf("test %s expected %v, got %v", test.name, test.expected, page)
+			}
+		})
+	}
+}
+
+func newTestSet(code string) sqlc.InsertSetsParams {
+	now := pgtype.Timestamp{Time: time.Date(2025, 9, 5, 21, 36, 0, 0, time.UTC), Valid: true}
+	return sqlc.InsertSetsParams{
+		ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Code:       code,
+		Name:       code,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func setCodes(t *testing.T, store Store) []string {
+	t.Helper()
+
+	sets, err := store.GetAllSets(context.Background())
+	if err != nil {
+		t.Fatalf("getting sets failed with error %v", err)
+	}
+
+	codes := []string{}
+	for _, set := range sets {
+		codes = append(codes, set.Code)
+	}
+
+	return codes
+}
+
+func Test_MemoryStoreWithTxKeepsOutsideWrites(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	outsideDone := make(chan error)
+	err := store.WithTx(ctx, func(tx Store) error {
+		go func() {
+			_, err := store.InsertSets(ctx, []sqlc.InsertSetsParams{newTestSet("out")})
+			outsideDone <- err
+		}()
+		// Give the outside write time to run, were it not held back
+		time.Sleep(20 * time.Millisecond)
+
+		if _, err := tx.InsertSets(ctx, []sqlc.InsertSetsParams{newTestSet("in")}); err != nil {
+			return err
+		}
+		if codes := setCodes(t, tx); !slices.Equal(codes, []string{"in"}) {
+			t.Errorf("expected the transaction to only see its own set, got %v", codes)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction failed with error %v", err)
+	}
+
+	if err := <-outsideDone; err != nil {
+		t.Fatalf("writing outside the transaction failed with error %v", err)
+	}
+	if codes := setCodes(t, store); !slices.Equal(codes, []string{"in", "out"}) {
+		t.Fatalf("expected the sets written inside and outside the transaction, got %v", codes)
+	}
+}
+
+func Test_MemoryStoreWithTxRollback(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	seedSearchCards(t, store)
+	if _, err := store.InsertQuarantinedCards(ctx, []sqlc.InsertQuarantinedCardsParams{{Reason: "before"}}); err != nil {
+		t.Fatalf("inserting quarantined cards failed with error %v", err)
+	}
+
+	// Cards sharing a name come in any order
+	allCards := func() []sqlc.GetAllCardsRow {
+		cards, _ := store.GetAllCards(ctx)
+		slices.SortFunc(cards, func(a, b sqlc.GetAllCardsRow) int {
+			return strings.Compare(a.CollectorNumber, b.CollectorNumber)
+		})
+		return cards
+	}
+
+	sets, _ := store.GetAllSets(ctx)
+	cards := allCards()
+	quarantined := store.GetQuarantinedCards()
+
+	rollback := errors.New("rollback")
+	err := store.WithTx(ctx, func(tx Store) error {
+		if _, err := tx.InsertSets(ctx, []sqlc.InsertSetsParams{newTestSet("new")}); err != nil {
+			return err
+		}
+		if err := tx.UpdateSet(ctx, sqlc.UpdateSetParams{ScryfallID: sets[0].ScryfallID, Code: "upd"}); err != nil {
+			return err
+		}
+		if err := tx.DeleteSet(ctx, sets[1].ScryfallID); err != nil {
+			return err
+		}
+		if err := tx.DeleteQuarantinedCards(ctx); err != nil {
+			return err
+		}
+		if _, err := tx.InsertQuarantinedCards(ctx, []sqlc.InsertQuarantinedCardsParams{{Reason: "during"}}); err != nil {
+			return err
+		}
+
+		// Committing a nested transaction keeps its writes in the outer one
+		return tx.WithTx(ctx, func(nested Store) error {
+			if err := nested.DeleteCard(ctx, cards[0].ScryfallID); err != nil {
+				return err
+			}
+
+			return rollback
+		})
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("expected the transaction to fail with %v, got %v", rollback, err)
+	}
+
+	if after, _ := store.GetAllSets(ctx); !reflect.DeepEqual(after, sets) {
+		t.Errorf("expected sets %v after rolling back, got %v", sets, after)
+	}
+	if after := allCards(); !reflect.DeepEqual(after, cards) {
+		t.Errorf("expected cards %v after rolling back, got %v", cards, after)
+	}
+	if after := store.GetQuarantinedCards(); !reflect.DeepEqual(after, quarantined) {
+		t.Errorf("expected quarantined cards %v after rolling back, got %v", quarantined, after)
+	}
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// database/sql driver names, also used to pick the migrations to run.
+const (
+	DriverPostgres = "pgx"
+	DriverSqlite   = "sqlite"
+)
+
+const sqliteMemory = ":memory:"
+
+// Foreign keys are off by default in SQLite, and the sync relies on the
+// cascade from sets to cards.
+const sqlitePragmas = "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite"
+
+type UnsupportedDbURLError struct {
+	URL string
+}
+
+func (e *UnsupportedDbURLError) Error() string {
+	return fmt.Sprintf("unsupported db url %q, expected postgres://, postgresql:// or sqlite://", e.URL)
+}
+
+// ParseDbURL returns the database/sql driver and data source name for a db
+// url, picking the backend by its scheme.
+func ParseDbURL(dbURL string) (string, string, error) {
+	if strings.HasPrefix(dbURL, "postgres://") || strings.HasPrefix(dbURL, "postgresql://") {
+		return DriverPostgres, dbURL, nil
+	}
+
+	if path, ok := strings.CutPrefix(dbURL, "sqlite://"); ok && path != "" {
+		return DriverSqlite, "file:" + path + sqlitePragmas, nil
+	}
+
+	return "", "", &UnsupportedDbURLError{URL: dbURL}
+}
+
+// OpenSQL opens a database/sql handle on the db url, returning the driver it
+// picked along with it.
+func OpenSQL(dbURL string) (*sql.DB, string, error) {
+	driver, dsn, err := ParseDbURL(dbURL)
+	if err != nil {
+		log.Println(err)
+		return nil, "", err
+	}
+
+	sqlDb, err := sql.Open(driver, dsn)
+	if err != nil {
+		log.Println(err)
+		return nil, "", err
+	}
+
+	// A single connection serializes writers, and keeps in-memory databases
+	// from being one per connection
+	if driver == DriverSqlite {
+		sqlDb.SetMaxOpenConns(1)
+	}
+
+	return sqlDb, driver, nil
+}
+
+// PoolConf configures the Postgres connection pool. Zero values keep the
+// pgxpool defaults and no statement timeout. SQLite ignores it, as it writes
+// through a single connection.
+type PoolConf struct {
+	MaxConns         int32
+	StatementTimeout time.Duration
+}
+
+func newPool(ctx context.Context, dbURL string, poolConf PoolConf) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if poolConf.MaxConns > 0 {
+		config.MaxConns = poolConf.MaxConns
+	}
+
+	if poolConf.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(
+			poolConf.StatementTimeout.Milliseconds(),
+			10,
+		)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// Open connects the Store for the db url. The returned function closes it.
+func Open(ctx context.Context, dbURL string, poolConf PoolConf) (Store, func(), error) {
+	driver, _, err := ParseDbURL(dbURL)
+	if err != nil {
+		log.Println(err)
+		return nil, nil, err
+	}
+
+	if driver == DriverPostgres {
+		pool, err := newPool(ctx, dbURL, poolConf)
+		if err != nil {
+			log.Println(err)
+			return nil, nil, err
+		}
+
+		return NewPostgresStore(pool), pool.Close, nil
+	}
+
+	sqlDb, _, err := OpenSQL(dbURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = sqlDb.PingContext(ctx); err != nil {
+		log.Println(err)
+		sqlDb.Close()
+		return nil, nil, err
+	}
+
+	path := strings.TrimPrefix(dbURL, "sqlite://")
+	lockPath := ""
+	if path != sqliteMemory {
+		lockPath = path + ".lock"
+	}
+
+	return NewSqliteStore(sqlDb, lockPath), func() { sqlDb.Close() }, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func (s *PostgresStore) CreateCollection(ctx context.Context, name string) (sqlc.Collection, error) {
+	return s.queries.CreateCollection(ctx, sqlc.CreateCollectionParams{
+		Name:      name,
+		CreatedAt: pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	})
+}
+
+func (s *PostgresStore) GetCollection(ctx context.Context, name string) (sqlc.Collection, error) {
+	collection, err := s.queries.GetCollectionByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Collection{}, ErrNotFound
+	}
+
+	return collection, err
+}
+
+func (s *PostgresStore) GetAllCollections(ctx context.Context) ([]sqlc.Collection, error) {
+	return s.queries.GetAllCollections(ctx)
+}
+
+func (s *PostgresStore) UpdateCollectionOwner(ctx context.Context, collection sqlc.UpdateCollectionOwnerParams) error {
+	return s.queries.UpdateCollectionOwner(ctx, collection)
+}
+
+func (s *PostgresStore) InsertCollectionItem(
+	ctx context.Context,
+	item sqlc.InsertCollectionItemParams,
+) (sqlc.CollectionItem, error) {
+	return s.queries.InsertCollectionItem(ctx, item)
+}
+
+func (s *PostgresStore) GetCollectionItem(ctx context.Context, id int64) (sqlc.CollectionItem, error) {
+	item, err := s.queries.GetCollectionItem(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.CollectionItem{}, ErrNotFound
+	}
+
+	return item, err
+}
+
+func (s *PostgresStore) GetCollectionItems(
+	ctx context.Context,
+	collectionID int64,
+) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	return s.queries.GetCollectionItemsWithCards(ctx, collectionID)
+}
+
+func (s *PostgresStore) GetOwnerCollectionItems(
+	ctx context.Context,
+	owner string,
+) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	rows, err := s.queries.GetOwnerCollectionItemsWithCards(ctx, pgtype.Text{String: owner, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]sqlc.GetCollectionItemsWithCardsRow, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, sqlc.GetCollectionItemsWithCardsRow(row))
+	}
+
+	return items, nil
+}
+
+func (s *PostgresStore) UpdateCollectionItem(ctx context.Context, item sqlc.UpdateCollectionItemParams) error {
+	return s.queries.UpdateCollectionItem(ctx, item)
+}
+
+func (s *PostgresStore) DeleteCollectionItem(ctx context.Context, id int64) error {
+	return s.queries.DeleteCollectionItem(ctx, id)
+}
+
+func (s *PostgresStore) RecordCollectionPrices(ctx context.Context, date pgtype.Date) (int64, error) {
+	return s.queries.RecordCollectionPrices(ctx, date)
+}
+
+func (s *PostgresStore) GetCollectionPricesOn(
+	ctx context.Context,
+	collectionID int64,
+	date pgtype.Date,
+) ([]sqlc.CardPrice, error) {
+	return s.queries.GetCollectionPricesOn(ctx, sqlc.GetCollectionPricesOnParams{
+		CollectionID: collectionID,
+		PriceDate:    date,
+	})
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func (s *PostgresStore) CreateDeck(ctx context.Context, name string, format string) (sqlc.Deck, error) {
+	timestamp := pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+
+	return s.queries.CreateDeck(ctx, sqlc.CreateDeckParams{
+		Name:      name,
+		Format:    format,
+		CreatedAt: timestamp,
+		UpdatedAt: timestamp,
+	})
+}
+
+func (s *PostgresStore) GetDeck(ctx context.Context, name string) (sqlc.Deck, error) {
+	deck, err := s.queries.GetDeckByName(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Deck{}, ErrNotFound
+	}
+
+	return deck, err
+}
+
+func (s *PostgresStore) GetAllDecks(ctx context.Context) ([]sqlc.Deck, error) {
+	return s.queries.GetAllDecks(ctx)
+}
+
+func (s *PostgresStore) InsertDeckCard(ctx context.Context, card sqlc.InsertDeckCardParams) (sqlc.DeckCard, error) {
+	return s.queries.InsertDeckCard(ctx, card)
+}
+
+func (s *PostgresStore) GetDeckCards(ctx context.Context, deckID int64) ([]sqlc.DeckCard, error) {
+	return s.queries.GetDeckCards(ctx, deckID)
+}
+
+func (s *PostgresStore) UpdateDeckCard(ctx context.Context, card sqlc.UpdateDeckCardParams) error {
+	return s.queries.UpdateDeckCard(ctx, card)
+}
+
+func (s *PostgresStore) DeleteDeckCard(ctx context.Context, id int64) error {
+	return s.queries.DeleteDeckCard(ctx, id)
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// pgxConn is satisfied by both *pgxpool.Pool and pgx.Tx, the latter starting
+// savepoints on Begin.
+type pgxConn interface {
+	sqlc.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type PostgresStore struct {
+	conn    pgxConn
+	queries *sqlc.Queries
+
+	pool     *pgxpool.Pool
+	lockConn *pgx.Conn
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{
+		conn:    pool,
+		queries: sqlc.New(pool),
+		pool:    pool,
+	}
+}
+
+func (s *PostgresStore) GetAllSets(ctx context.Context) ([]sqlc.Set, error) {
+	return s.queries.GetAllSets(ctx)
+}
+
+func (s *PostgresStore) GetAllCards(ctx context.Context) ([]sqlc.GetAllCardsRow, error) {
+	return s.queries.GetAllCards(ctx)
+}
+
+func (s *PostgresStore) GetCardNames(ctx context.Context) ([]sqlc.GetCardNamesRow, error) {
+	return s.queries.GetCardNames(ctx)
+}
+
+func (s *PostgresStore) GetCardsVersion(ctx context.Context) (sqlc.GetCardsVersionRow, error) {
+	return s.queries.GetCardsVersion(ctx)
+}
+
+func (s *PostgresStore) GetCard(ctx context.Context, scryfallID pgtype.UUID) (CardWithSet, error) {
+	row, err := s.queries.GetCardWithSet(ctx, scryfallID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CardWithSet{}, ErrNotFound
+	}
+
+	return CardWithSet(row), err
+}
+
+func (s *PostgresStore) GetSet(ctx context.Context, code string) (sqlc.Set, error) {
+	set, err := s.queries.GetSetByCode(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Set{}, ErrNotFound
+	}
+
+	return set, err
+}
+
+func (s *PostgresStore) GetOraclePrintings(
+	ctx context.Context,
+	oracleID pgtype.UUID,
+	page Page,
+) ([]CardWithSet, error) {
+	rows, err := s.queries.GetOracleCardsWithSets(ctx, sqlc.GetOracleCardsWithSetsParams{
+		ScryfallOracleID: oracleID,
+		Limit:            page.Limit,
+		Offset:           page.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCardsWithSets(rows), nil
+}
+
+func (s *PostgresStore) GetSetCards(ctx context.Context, code string, page Page) ([]CardWithSet, error) {
+	rows, err := s.queries.GetSetCardsWithSets(ctx, sqlc.GetSetCardsWithSetsParams{
+		Code:   code,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCardsWithSets(rows), nil
+}
+
+func (s *PostgresStore) SearchCards(ctx context.Context, query string, page Page) ([]sqlc.SearchCardsRow, error) {
+	return s.queries.SearchCards(ctx, sqlc.SearchCardsParams{
+		Query:        query,
+		MaxResults:   page.Limit,
+		ResultOffset: page.Offset,
+	})
+}
+
+// Written by hand rather than with sqlc, as sqlc's :many queries collect every
+// row before returning and the export streams whole card tables through here.
+var postgresGetAllCardsWithSets = "SELECT c." + strings.Join(cardsColumns, ", c.") + `, s.code, s.name
+FROM cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE ($1 = '' OR s.code = $1)
+    AND ($2 = '' OR c.language_code = $2)
+    AND ($3::TIMESTAMP IS NULL OR c.updated_at >= $3)
+ORDER BY
+    s.code,
+    COALESCE(substring(c.collector_number FROM '^[0-9]+')::INTEGER, 0),
+    c.collector_number,
+    c.language_code ASC`
+
+func (s *PostgresStore) GetAllCardsWithSets(ctx context.Context, filter CardFilter, fn func(CardWithSet) error) error {
+	rows, err := s.conn.Query(
+		ctx,
+		postgresGetAllCardsWithSets,
+		filter.SetCode,
+		filter.LanguageCode,
+		filter.UpdatedSince,
+	)
+	if err != nil {
+		return err
+	}
+
+	var card CardWithSet
+	_, err = pgx.ForEachRow(rows, cardWithSetDest(&card), func() error {
+		return fn(card)
+	})
+	return err
+}
+
+func (s *PostgresStore) QueryCards(ctx context.Context, node cardquery.Node, page Page) ([]CardWithSet, error) {
+	query, args := queryCardsSQL(node, cardquery.Postgres, page)
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (CardWithSet, error) {
+		var card CardWithSet
+		err := row.Scan(cardWithSetDest(&card)...)
+		return card, err
+	})
+}
+
+func (s *PostgresStore) InsertSets(ctx context.Context, sets []sqlc.InsertSetsParams) (int64, error) {
+	return s.mergeSets(ctx, sets)
+}
+
+func (s *PostgresStore) InsertCards(ctx context.Context, cards []sqlc.InsertCardsParams) (int64, error) {
+	return s.mergeCards(ctx, cards)
+}
+
+func (s *PostgresStore) InsertQuarantinedCards(
+	ctx context.Context,
+	cards []sqlc.InsertQuarantinedCardsParams,
+) (int64, error) {
+	return s.queries.InsertQuarantinedCards(ctx, cards)
+}
+
+func (s *PostgresStore) UpdateSet(ctx context.Context, set sqlc.UpdateSetParams) error {
+	return s.queries.UpdateSet(ctx, set)
+}
+
+func (s *PostgresStore) UpdateCard(ctx context.Context, card sqlc.UpdateCardParams) error {
+	return s.queries.UpdateCard(ctx, card)
+}
+
+func (s *PostgresStore) DeleteSet(ctx context.Context, scryfallID pgtype.UUID) error {
+	return s.queries.DeleteSet(ctx, scryfallID)
+}
+
+func (s *PostgresStore) DeleteCard(ctx context.Context, scryfallID pgtype.UUID) error {
+	return s.queries.DeleteCard(ctx, scryfallID)
+}
+
+func (s *PostgresStore) DeleteQuarantinedCards(ctx context.Context) error {
+	return s.queries.DeleteQuarantinedCards(ctx)
+}
+
+// The advisory lock is held by the session until ReleaseSyncLock or, if the
+// process dies, until it closes. It gets a connection of its own outside the
+// pool, so a small pool isn't left a connection short for the whole sync, and
+// without statement timeout, so waiting for the lock isn't cut short by it.
+func (s *PostgresStore) AcquireSyncLock(ctx context.Context, wait bool) error {
+	config := s.pool.Config().ConnConfig.Copy()
+	config.RuntimeParams["statement_timeout"] = "0"
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return err
+	}
+	queries := sqlc.New(conn)
+
+	if wait {
+		log.Println("Waiting for sync lock")
+		if err = queries.AcquireSyncLock(ctx); err != nil {
+			conn.Close(context.Background())
+			return err
+		}
+
+		s.lockConn = conn
+		return nil
+	}
+
+	acquired, err := queries.TryAcquireSyncLock(ctx)
+	if err != nil {
+		conn.Close(context.Background())
+		return err
+	}
+
+	if !acquired {
+		conn.Close(context.Background())
+		return ErrSyncInProgress
+	}
+
+	s.lockConn = conn
+	return nil
+}
+
+// Closing the session drops the lock as well, so the connection is closed
+// even if unlocking fails.
+func (s *PostgresStore) ReleaseSyncLock(ctx context.Context) error {
+	if s.lockConn == nil {
+		return nil
+	}
+
+	conn := s.lockConn
+	s.lockConn = nil
+
+	_, err := sqlc.New(conn).ReleaseSyncLock(ctx)
+	if closeErr := conn.Close(context.Background()); closeErr != nil {
+		log.Println(closeErr)
+	}
+
+	return err
+}
+
+func (s *PostgresStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	txStore := &PostgresStore{
+		conn:    tx,
+		queries: s.queries.WithTx(tx),
+	}
+	if err = fn(txStore); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
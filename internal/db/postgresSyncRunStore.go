@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func (s *PostgresStore) InsertSyncRun(ctx context.Context, run sqlc.InsertSyncRunParams) (sqlc.SyncRun, error) {
+	return s.queries.InsertSyncRun(ctx, run)
+}
+
+func (s *PostgresStore) GetLastSyncRun(ctx context.Context) (sqlc.SyncRun, error) {
+	run, err := s.queries.GetLastSyncRun(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.SyncRun{}, ErrNotFound
+	}
+
+	return run, err
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func (s *PostgresStore) InsertWant(ctx context.Context, want sqlc.InsertWantParams) (sqlc.Want, error) {
+	return s.queries.InsertWant(ctx, want)
+}
+
+func (s *PostgresStore) GetWants(ctx context.Context, owner string) ([]sqlc.Want, error) {
+	return s.queries.GetWants(ctx, owner)
+}
+
+func (s *PostgresStore) UpdateWant(ctx context.Context, want sqlc.UpdateWantParams) error {
+	return s.queries.UpdateWant(ctx, want)
+}
+
+func (s *PostgresStore) DeleteWant(ctx context.Context, id int64) error {
+	return s.queries.DeleteWant(ctx, id)
+}
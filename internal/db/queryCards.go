@@ -0,0 +1,33 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+)
+
+func queryCardsSQL(node cardquery.Node, dialect cardquery.Dialect, page Page) (string, []any) {
+	where, args := cardquery.Where(node, dialect)
+
+	placeholder := "$"
+	if dialect == cardquery.Sqlite {
+		placeholder = "?"
+	}
+	args = append(args, page.Limit, page.Offset)
+
+	return fmt.Sprintf(
+		`SELECT c.%s, s.code, s.name
+FROM cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE %s
+ORDER BY c.name, s.code, c.collector_number, c.language_code
+LIMIT %s%d OFFSET %s%d`,
+		strings.Join(cardsColumns, ", c."),
+		where,
+		placeholder,
+		len(args)-1,
+		placeholder,
+		len(args),
+	), args
+}
@@ -0,0 +1,287 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/search"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+const (
+	// MatchScryfallID resolved the line by the scryfall id it carries
+	MatchScryfallID = "scryfall_id"
+	// MatchPrinting resolved the line by set code and collector number
+	MatchPrinting = "printing"
+	// MatchSet resolved the line by name within its set
+	MatchSet = "set"
+	// MatchName resolved the line by fuzzy name to a card, not a printing
+	MatchName = "name"
+)
+
+// fuzzyCandidates bounds the search results considered for a fuzzy match,
+// enough for the printings of a card in every language to come before any
+// weaker match.
+const fuzzyCandidates = 200
+
+// CardRef names a card the way decklists and vendor exports do. Only the name
+// is required.
+type CardRef struct {
+	ScryfallID pgtype.UUID
+	Name       string
+	SetCode    string
+	// SetName finds the set when the code is missing or not Scryfall's
+	SetName         string
+	CollectorNumber string
+	// LanguageCode picks among the printings of a set, following the
+	// language of the name if empty
+	LanguageCode string
+}
+
+// Resolution is a decklist line, or any other card reference, matched against
+// the cards.
+type Resolution struct {
+	// Line is empty for references not read from a decklist
+	Line deck.Line
+	// Method is empty if the line didn't resolve
+	Method string
+	// Card is the printing named by the line, or the best match of its name
+	// when resolved by MatchName
+	Card CardWithSet
+	// Candidates are the names of the cards an ambiguous line could be
+	Candidates []string
+}
+
+func (r Resolution) Resolved() bool {
+	return r.Method != ""
+}
+
+func (r Resolution) Ambiguous() bool {
+	return len(r.Candidates) > 0
+}
+
+// Resolver matches card references against the cards, first by scryfall id,
+// then by set and collector number, then by name within the set and last by
+// fuzzy name. Names match in English or Spanish. Sets and their printings are
+// loaded once, so a Resolver is meant for a single import.
+type Resolver struct {
+	Store Store
+
+	sets     map[string][]CardWithSet
+	setCodes map[string]string
+}
+
+// setCode returns the code of the set named by ref, empty if there is no such
+// set.
+func (r *Resolver) setCode(ctx context.Context, ref CardRef) (string, error) {
+	if r.setCodes == nil {
+		sets, err := r.Store.GetAllSets(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		r.setCodes = map[string]string{}
+		for _, set := range sets {
+			r.setCodes[set.Code] = set.Code
+		}
+		// Codes take precedence over names
+		for _, set := range sets {
+			if _, ok := r.setCodes[search.Normalize(set.Name)]; !ok {
+				r.setCodes[search.Normalize(set.Name)] = set.Code
+			}
+		}
+	}
+
+	if code, ok := r.setCodes[strings.ToLower(ref.SetCode)]; ok {
+		return code, nil
+	}
+	if ref.SetName != "" {
+		return r.setCodes[search.Normalize(ref.SetName)], nil
+	}
+
+	return "", nil
+}
+
+func (r *Resolver) setCards(ctx context.Context, code string) ([]CardWithSet, error) {
+	if cards, ok := r.sets[code]; ok {
+		return cards, nil
+	}
+
+	cards, err := r.Store.GetSetCards(ctx, code, Page{Limit: math.MaxInt32})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.sets == nil {
+		r.sets = map[string][]CardWithSet{}
+	}
+	r.sets[code] = cards
+
+	return cards, nil
+}
+
+// nameMatches compares normalized names, matching double faced cards by their
+// front face as Arena lists them.
+func nameMatches(name string, cardName string) bool {
+	cardName = search.Normalize(cardName)
+	return cardName == name || strings.HasPrefix(cardName, name+" // ")
+}
+
+func cardNameMatches(name string, card sqlc.Card) bool {
+	return nameMatches(name, card.Name) || (card.SpanishName.Valid && nameMatches(name, card.SpanishName.String))
+}
+
+// pickPrinting chooses among printings of the same card in a set, preferring
+// the one in the language asked for, then the Spanish one when the card is
+// named in Spanish and the English one otherwise.
+func pickPrinting(name string, language string, printings []CardWithSet) CardWithSet {
+	for _, printing := range printings {
+		if language != "" && printing.Card.LanguageCode == language {
+			return printing
+		}
+	}
+
+	for _, printing := range printings {
+		if printing.Card.SpanishName.Valid && nameMatches(name, printing.Card.SpanishName.String) {
+			return printing
+		}
+	}
+
+	for _, printing := range printings {
+		if printing.Card.LanguageCode == "en" {
+			return printing
+		}
+	}
+
+	return printings[0]
+}
+
+func searchRowCard(row sqlc.SearchCardsRow) CardWithSet {
+	return CardWithSet{
+		Card: sqlc.Card{
+			ScryfallID:       row.ScryfallID,
+			SetID:            row.SetID,
+			Name:             row.Name,
+			CollectorNumber:  row.CollectorNumber,
+			ColorIdentity:    row.ColorIdentity,
+			Colors:           row.Colors,
+			LanguageCode:     row.LanguageCode,
+			SpanishName:      row.SpanishName,
+			Rarity:           row.Rarity,
+			TypeLine:         row.TypeLine,
+			ScryfallApiUri:   row.ScryfallApiUri,
+			ScryfallWebUri:   row.ScryfallWebUri,
+			ScryfallOracleID: row.ScryfallOracleID,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Raw:              row.Raw,
+			RawHash:          row.RawHash,
+		},
+		SetCode: row.SetCode,
+		SetName: row.SetName,
+	}
+}
+
+func (r *Resolver) Resolve(ctx context.Context, line deck.Line) (Resolution, error) {
+	resolution, err := r.ResolveRef(ctx, CardRef{Name: line.Name, SetCode: line.SetCode, CollectorNumber: line.CollectorNumber})
+	resolution.Line = line
+
+	return resolution, err
+}
+
+func (r *Resolver) ResolveRef(ctx context.Context, ref CardRef) (Resolution, error) {
+	resolution := Resolution{}
+	name := search.Normalize(ref.Name)
+
+	if ref.ScryfallID.Valid {
+		card, err := r.Store.GetCard(ctx, ref.ScryfallID)
+		if err == nil {
+			resolution.Method = MatchScryfallID
+			resolution.Card = card
+			return resolution, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			log.Println(err)
+			return resolution, err
+		}
+	}
+
+	setCode, err := r.setCode(ctx, ref)
+	if err != nil {
+		log.Println(err)
+		return resolution, err
+	}
+
+	if setCode != "" {
+		printings, err := r.setCards(ctx, setCode)
+		if err != nil {
+			log.Println(err)
+			return resolution, err
+		}
+
+		named := slices.DeleteFunc(slices.Clone(printings), func(printing CardWithSet) bool {
+			return !cardNameMatches(name, printing.Card)
+		})
+
+		numbered := slices.DeleteFunc(slices.Clone(named), func(printing CardWithSet) bool {
+			return !strings.EqualFold(printing.Card.CollectorNumber, ref.CollectorNumber)
+		})
+		if ref.CollectorNumber != "" && len(numbered) > 0 {
+			resolution.Method = MatchPrinting
+			resolution.Card = pickPrinting(name, ref.LanguageCode, numbered)
+			return resolution, nil
+		}
+
+		if len(named) > 0 {
+			resolution.Method = MatchSet
+			resolution.Card = pickPrinting(name, ref.LanguageCode, named)
+			return resolution, nil
+		}
+	}
+
+	rows, err := r.Store.SearchCards(ctx, ref.Name, Page{Limit: fuzzyCandidates})
+	if err != nil {
+		log.Println(err)
+		return resolution, err
+	}
+	if len(rows) == 0 {
+		return resolution, nil
+	}
+
+	// An exact name wins over the cards merely containing it, otherwise every
+	// card as close as the best one is a candidate
+	matches := slices.DeleteFunc(slices.Clone(rows), func(row sqlc.SearchCardsRow) bool {
+		return !nameMatches(name, row.Name) && !(row.SpanishName.Valid && nameMatches(name, row.SpanishName.String))
+	})
+	if len(matches) == 0 {
+		best := rows[0].Rank
+		matches = slices.DeleteFunc(rows, func(row sqlc.SearchCardsRow) bool {
+			return row.Rank < best
+		})
+	}
+
+	candidates := []string{}
+	oracles := map[[16]byte]bool{}
+	for _, match := range matches {
+		if !oracles[match.ScryfallOracleID.Bytes] {
+			oracles[match.ScryfallOracleID.Bytes] = true
+			candidates = append(candidates, match.Name)
+		}
+	}
+
+	if len(candidates) > 1 {
+		resolution.Candidates = candidates
+		return resolution, nil
+	}
+
+	resolution.Method = MatchName
+	resolution.Card = searchRowCard(matches[0])
+	return resolution, nil
+}
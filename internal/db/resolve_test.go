@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func seedDecklistCards(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	now := pgtype.Timestamp{Time: time.Date(2025, 9, 5, 21, 36, 0, 0, time.UTC), Valid: true}
+
+	sets := []sqlc.InsertSetsParams{
+		{ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: "m10", Name: "Magic 2010", CreatedAt: now, UpdatedAt: now},
+		{ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: "isd", Name: "Innistrad", CreatedAt: now, UpdatedAt: now},
+	}
+	if _, err := store.InsertSets(ctx, sets); err != nil {
+		t.Fatalf("inserting sets failed with error %v", err)
+	}
+
+	boltOracleID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	cards := []struct {
+		set             int
+		name            string
+		spanishName     string
+		language        string
+		collectorNumber string
+		oracleID        pgtype.UUID
+	}{
+		{set: 0, name: "Lightning Bolt", language: "es", spanishName: "Relámpago", collectorNumber: "146", oracleID: boltOracleID},
+		{set: 0, name: "Lightning Bolt", language: "en", collectorNumber: "146", oracleID: boltOracleID},
+		{set: 0, name: "Bolt of Keranos", language: "en", collectorNumber: "147"},
+		{set: 1, name: "Delver of Secrets // Insectile Aberration", language: "en", collectorNumber: "51"},
+	}
+
+	params := make([]sqlc.InsertCardsParams, 0, len(cards))
+	for i, card := range cards {
+		oracleID := card.oracleID
+		if !oracleID.Valid {
+			oracleID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		}
+
+		params = append(params, sqlc.InsertCardsParams{
+			ScryfallID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			SetID:            sets[card.set].ScryfallID,
+			Name:             card.name,
+			CollectorNumber:  card.collectorNumber,
+			LanguageCode:     card.language,
+			SpanishName:      pgtype.Text{String: card.spanishName, Valid: card.spanishName != ""},
+			ScryfallApiUri:   fmt.Sprintf("api/%d", i),
+			ScryfallWebUri:   fmt.Sprintf("web/%d", i),
+			ScryfallOracleID: oracleID,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			Raw:              "{}",
+		})
+	}
+	if _, err := store.InsertCards(ctx, params); err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+}
+
+func Test_Resolver(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	seedDecklistCards(t, store)
+
+	tests := []struct {
+		line               deck.Line
+		expectedMethod     string
+		expectedName       string
+		expectedSet        string
+		expectedLanguage   string
+		expectedCandidates []string
+	}{
+		{
+			line:             deck.Line{Name: "Lightning Bolt", SetCode: "m10", CollectorNumber: "146"},
+			expectedMethod:   MatchPrinting,
+			expectedName:     "Lightning Bolt",
+			expectedSet:      "m10",
+			expectedLanguage: "en",
+		},
+		{
+			line:             deck.Line{Name: "Relámpago", SetCode: "m10", CollectorNumber: "146"},
+			expectedMethod:   MatchPrinting,
+			expectedName:     "Lightning Bolt",
+			expectedSet:      "m10",
+			expectedLanguage: "es",
+		},
+		{
+			line:             deck.Line{Name: "lightning bolt", SetCode: "m10", CollectorNumber: "999"},
+			expectedMethod:   MatchSet,
+			expectedName:     "Lightning Bolt",
+			expectedSet:      "m10",
+			expectedLanguage: "en",
+		},
+		{
+			line:           deck.Line{Name: "Delver of Secrets", SetCode: "isd"},
+			expectedMethod: MatchSet,
+			expectedName:   "Delver of Secrets // Insectile Aberration",
+			expectedSet:    "isd",
+		},
+		{
+			line:           deck.Line{Name: "relampago", SetCode: "xyz", CollectorNumber: "1"},
+			expectedMethod: MatchName,
+			expectedName:   "Lightning Bolt",
+		},
+		{
+			line:           deck.Line{Name: "Lightning Bolt"},
+			expectedMethod: MatchName,
+			expectedName:   "Lightning Bolt",
+		},
+		{
+			line:               deck.Line{Name: "Bolt"},
+			expectedCandidates: []string{"Lightning Bolt", "Bolt of Keranos"},
+		},
+		{
+			line: deck.Line{Name: "Counterspell"},
+		},
+	}
+
+	resolver := &Resolver{Store: store}
+	for _, test := range tests {
+		t.Run(test.line.Name, func(t *testing.T) {
+			resolution, err := resolver.Resolve(ctx, test.line)
+			if err != nil {
+				t.Fatalf("resolving failed with error %v", err)
+			}
+
+			if resolution.Method != test.expectedMethod {
+				t.Fatalf("expected method %q but got %q", test.expectedMethod, resolution.Method)
+			}
+			if !slices.Equal(resolution.Candidates, test.expectedCandidates) {
+				t.Fatalf("expected candidates %q but got %q", test.expectedCandidates, resolution.Candidates)
+			}
+			if !resolution.Resolved() {
+				return
+			}
+
+			card := resolution.Card
+			if card.Card.Name != test.expectedName {
+				t.Fatalf("expected %q but got %q", test.expectedName, card.Card.Name)
+			}
+			if test.expectedSet != "" && card.SetCode != test.expectedSet {
+				t.Fatalf("expected set %q but got %q", test.expectedSet, card.SetCode)
+			}
+			if test.expectedLanguage != "" && card.Card.LanguageCode != test.expectedLanguage {
+				t.Fatalf("expected language %q but got %q", test.expectedLanguage, card.Card.LanguageCode)
+			}
+		})
+	}
+}
+
+func Test_DecksImport(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	seedDecklistCards(t, store)
+	decks := &Decks{Store: store}
+
+	if _, err := decks.Create(ctx, "burn", "modern"); err != nil {
+		t.Fatalf("creating deck failed with error %v", err)
+	}
+
+	decklist := deck.Decklist{Lines: []deck.Line{
+		{Number: 1, Zone: deck.ZoneMain, Quantity: 3, Name: "Lightning Bolt", SetCode: "m10", CollectorNumber: "146", Finish: deck.FinishFoil},
+		{Number: 2, Zone: deck.ZoneMain, Quantity: 1, Name: "Relámpago", Finish: deck.FinishNonfoil},
+		{Number: 3, Zone: deck.ZoneSideboard, Quantity: 2, Name: "Bolt", Finish: deck.FinishNonfoil},
+	}}
+
+	resolutions, err := decks.Import(ctx, "burn", decklist, true)
+	if err != nil {
+		t.Fatalf("dry run failed with error %v", err)
+	}
+	if len(resolutions) != 3 || !resolutions[0].Resolved() || !resolutions[1].Resolved() || !resolutions[2].Ambiguous() {
+		t.Fatalf("expected two resolved lines and an ambiguous one, got %+v", resolutions)
+	}
+	if _, entries, _ := decks.List(ctx, "burn"); len(entries) != 0 {
+		t.Fatalf("expected a dry run to add nothing, got %+v", entries)
+	}
+
+	if _, err := decks.Import(ctx, "burn", decklist, false); err != nil {
+		t.Fatalf("import failed with error %v", err)
+	}
+	_, entries, err := decks.List(ctx, "burn")
+	if err != nil {
+		t.Fatalf("listing deck failed with error %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries but got %+v", entries)
+	}
+	if entries[0].DeckCard.Quantity != 3 || entries[0].DeckCard.Finish != deck.FinishFoil || !entries[0].DeckCard.ScryfallID.Valid {
+		t.Fatalf("expected 3 foil Lightning Bolt by printing, got %+v", entries[0].DeckCard)
+	}
+	if entries[1].DeckCard.Quantity != 1 || entries[1].DeckCard.ScryfallID.Valid {
+		t.Fatalf("expected 1 Lightning Bolt by card, got %+v", entries[1].DeckCard)
+	}
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func seedSearchCards(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	now := pgtype.Timestamp{Time: time.Date(2025, 9, 5, 21, 36, 0, 0, time.UTC), Valid: true}
+
+	sets := []sqlc.InsertSetsParams{
+		{ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: "wwk", Name: "Worldwake", CreatedAt: now, UpdatedAt: now},
+		{ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, Code: "a25", Name: "Masters 25", CreatedAt: now, UpdatedAt: now},
+	}
+	if _, err := store.InsertSets(ctx, sets); err != nil {
+		t.Fatalf("inserting sets failed with error %v", err)
+	}
+
+	cards := []struct {
+		set         int
+		name        string
+		spanishName string
+		language    string
+	}{
+		{set: 0, name: "Jace, the Mind Sculptor", language: "en"},
+		{set: 0, name: "Jace, the Mind Sculptor", spanishName: "Jace, el Escultor de Mentes", language: "es"},
+		{set: 1, name: "Jace, the Mind Sculptor", language: "en"},
+		{set: 1, name: "Lightning Bolt", spanishName: "Relámpago", language: "es"},
+		{set: 1, name: "Counterspell", language: "en"},
+	}
+
+	params := make([]sqlc.InsertCardsParams, 0, len(cards))
+	for i, card := range cards {
+		params = append(params, sqlc.InsertCardsParams{
+			ScryfallID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			SetID:            sets[card.set].ScryfallID,
+			Name:             card.name,
+			CollectorNumber:  fmt.Sprint(i),
+			LanguageCode:     card.language,
+			SpanishName:      pgtype.Text{String: card.spanishName, Valid: card.spanishName != ""},
+			ScryfallApiUri:   fmt.Sprintf("api/%d", i),
+			ScryfallWebUri:   fmt.Sprintf("web/%d", i),
+			ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			Raw:              "{}",
+		})
+	}
+	if _, err := store.InsertCards(ctx, params); err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+}
+
+func Test_SearchCards(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		limit    int32
+		expected []string
+	}{
+		{
+			name:     "spanish name ranks the spanish printing first",
+			query:    "escultor de mentes",
+			limit:    10,
+			expected: []string{"wwk Jace, el Escultor de Mentes"},
+		},
+		{
+			name:  "english name matches every printing",
+			query: "JACE mind sculptor",
+			limit: 10,
+			expected: []string{
+				"a25 Jace, the Mind Sculptor",
+				"wwk Jace, the Mind Sculptor",
+				"wwk Jace, el Escultor de Mentes",
+			},
+		},
+		{name: "misspelled", query: "lightnig bolt", limit: 10, expected: []string{"a25 Relámpago"}},
+		{name: "accented query", query: "relámpago", limit: 10, expected: []string{"a25 Relámpago"}},
+		{name: "limit", query: "jace", limit: 1, expected: []string{"a25 Jace, the Mind Sculptor"}},
+		{name: "no match", query: "llanowar elves", limit: 10, expected: []string{}},
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": newSqliteTestStore(t),
+	}
+
+	for backend, store := range stores {
+		seedSearchCards(t, store)
+
+		for _, test := range tests {
+			t.Run(backend+"/"+test.name, func(t *testing.T) {
+				rows, err := store.SearchCards(context.Background(), test.query, Page{Limit: test.limit})
+				if err != nil {
+					t.Fatalf("search failed with error %v", err)
+				}
+
+				got := make([]string, 0, len(rows))
+				for _, row := range rows {
+					name := row.Name
+					if row.SpanishName.Valid {
+						name = row.SpanishName.String
+					}
+					got = append(got, row.SetCode+" "+name)
+				}
+
+				if fmt.Sprint(got) != fmt.Sprint(test.expected) {
+					t.Fatalf("expected results %q but got %q", test.expected, got)
+				}
+			})
+		}
+	}
+}
+
+func Test_GetAllCardsWithSets(t *testing.T) {
+	seeded := pgtype.Timestamp{Time: time.Date(2025, 9, 5, 21, 36, 0, 0, time.UTC), Valid: true}
+	later := pgtype.Timestamp{Time: seeded.Time.Add(time.Hour), Valid: true}
+
+	tests := []struct {
+		name     string
+		filter   CardFilter
+		expected []string
+	}{
+		{name: "every card", expected: []string{"a25 2", "a25 3", "a25 4", "wwk 0", "wwk 1"}},
+		{name: "set", filter: CardFilter{SetCode: "wwk"}, expected: []string{"wwk 0", "wwk 1"}},
+		{name: "language", filter: CardFilter{LanguageCode: "es"}, expected: []string{"a25 3", "wwk 1"}},
+		{name: "updated since", filter: CardFilter{SetCode: "a25", UpdatedSince: seeded}, expected: []string{"a25 2", "a25 3", "a25 4"}},
+		{name: "not updated since", filter: CardFilter{UpdatedSince: later}, expected: []string{}},
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": newSqliteTestStore(t),
+	}
+
+	for backend, store := range stores {
+		seedSearchCards(t, store)
+
+		for _, test := range tests {
+			t.Run(backend+"/"+test.name, func(t *testing.T) {
+				got := []string{}
+				err := store.GetAllCardsWithSets(context.Background(), test.filter, func(card CardWithSet) error {
+					got = append(got, card.SetCode+" "+card.Card.CollectorNumber)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("listing cards failed with error %v", err)
+				}
+
+				if fmt.Sprint(got) != fmt.Sprint(test.expected) {
+					t.Fatalf("expected cards %q but got %q", test.expected, got)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var collectionItemsColumns = []string{
+	"id",
+	"collection_id",
+	"scryfall_id",
+	"quantity",
+	"finish",
+	"condition",
+	"language_code",
+	"acquired_price_cents",
+	"acquired_currency",
+	"acquired_on",
+	"created_at",
+	"updated_at",
+}
+
+const sqliteCreateCollection = `INSERT INTO collections (name, created_at) VALUES (?, ?)
+RETURNING id, name, created_at, owner`
+
+const sqliteGetCollection = `SELECT id, name, created_at, owner
+FROM collections
+WHERE name = ?`
+
+const sqliteGetAllCollections = `SELECT id, name, created_at, owner
+FROM collections
+ORDER BY name ASC`
+
+var sqliteInsertCollectionItem = `INSERT INTO collection_items (
+    collection_id,
+    scryfall_id,
+    quantity,
+    finish,
+    condition,
+    language_code,
+    acquired_price_cents,
+    acquired_currency,
+    acquired_on,
+    created_at,
+    updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING ` + strings.Join(collectionItemsColumns, ", ")
+
+var sqliteGetCollectionItem = "SELECT " + strings.Join(collectionItemsColumns, ", ") + `
+FROM collection_items
+WHERE id = ?`
+
+var sqliteGetCollectionItems = "SELECT ci." + strings.Join(collectionItemsColumns, ", ci.") + `,
+    c.` + strings.Join(cardsColumns, ", c.") + `,
+    s.code,
+    s.name
+FROM collection_items ci
+INNER JOIN cards c ON ci.scryfall_id = c.scryfall_id
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE ci.collection_id = ?
+ORDER BY s.code ASC, c.collector_number ASC, ci.id ASC`
+
+var sqliteGetOwnerCollectionItems = "SELECT ci." + strings.Join(collectionItemsColumns, ", ci.") + `,
+    c.` + strings.Join(cardsColumns, ", c.") + `,
+    s.code,
+    s.name
+FROM collection_items ci
+INNER JOIN collections col ON ci.collection_id = col.id
+INNER JOIN cards c ON ci.scryfall_id = c.scryfall_id
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE col.owner = ?
+ORDER BY s.code ASC, c.collector_number ASC, ci.id ASC`
+
+const sqliteUpdateCollectionItem = `UPDATE collection_items
+SET collection_id = ?2,
+    quantity = ?3,
+    updated_at = ?4
+WHERE id = ?1`
+
+// Prices are stored in cents, as Scryfall's decimal strings become numbers
+// when multiplied
+const sqliteRecordCollectionPrices = `INSERT INTO card_prices (
+    scryfall_id,
+    price_date,
+    usd_cents,
+    usd_foil_cents,
+    usd_etched_cents,
+    eur_cents,
+    eur_foil_cents
+)
+SELECT
+    scryfall_id,
+    ?,
+    CAST(round(json_extract(raw, '$.prices.usd') * 100) AS INTEGER),
+    CAST(round(json_extract(raw, '$.prices.usd_foil') * 100) AS INTEGER),
+    CAST(round(json_extract(raw, '$.prices.usd_etched') * 100) AS INTEGER),
+    CAST(round(json_extract(raw, '$.prices.eur') * 100) AS INTEGER),
+    CAST(round(json_extract(raw, '$.prices.eur_foil') * 100) AS INTEGER)
+FROM cards
+WHERE scryfall_id IN (SELECT scryfall_id FROM collection_items)
+ON CONFLICT (scryfall_id, price_date) DO UPDATE SET
+    usd_cents = excluded.usd_cents,
+    usd_foil_cents = excluded.usd_foil_cents,
+    usd_etched_cents = excluded.usd_etched_cents,
+    eur_cents = excluded.eur_cents,
+    eur_foil_cents = excluded.eur_foil_cents`
+
+const sqliteGetCollectionPricesOn = `SELECT
+    p.scryfall_id,
+    p.price_date,
+    p.usd_cents,
+    p.usd_foil_cents,
+    p.usd_etched_cents,
+    p.eur_cents,
+    p.eur_foil_cents
+FROM card_prices p
+WHERE p.scryfall_id IN (SELECT scryfall_id FROM collection_items WHERE collection_id = ?1)
+    AND p.price_date = (
+        SELECT max(latest.price_date)
+        FROM card_prices latest
+        WHERE latest.scryfall_id = p.scryfall_id AND latest.price_date <= ?2
+    )`
+
+func collectionDest(collection *sqlc.Collection) []any {
+	return []any{&collection.ID, &collection.Name, &collection.CreatedAt, &collection.Owner}
+}
+
+func collectionItemDest(item *sqlc.CollectionItem) []any {
+	return []any{
+		&item.ID,
+		&item.CollectionID,
+		&item.ScryfallID,
+		&item.Quantity,
+		&item.Finish,
+		&item.Condition,
+		&item.LanguageCode,
+		&item.AcquiredPriceCents,
+		&item.AcquiredCurrency,
+		&item.AcquiredOn,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	}
+}
+
+func (s *SqliteStore) CreateCollection(ctx context.Context, name string) (sqlc.Collection, error) {
+	var collection sqlc.Collection
+	err := s.conn().QueryRowContext(
+		ctx,
+		sqliteCreateCollection,
+		name,
+		pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+	).Scan(collectionDest(&collection)...)
+
+	return collection, err
+}
+
+func (s *SqliteStore) GetCollection(ctx context.Context, name string) (sqlc.Collection, error) {
+	var collection sqlc.Collection
+	err := s.conn().QueryRowContext(ctx, sqliteGetCollection, name).Scan(collectionDest(&collection)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Collection{}, ErrNotFound
+	}
+
+	return collection, err
+}
+
+func (s *SqliteStore) GetAllCollections(ctx context.Context) ([]sqlc.Collection, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetAllCollections)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.Collection
+	for rows.Next() {
+		var i sqlc.Collection
+		if err := rows.Scan(collectionDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) UpdateCollectionOwner(ctx context.Context, collection sqlc.UpdateCollectionOwnerParams) error {
+	_, err := s.conn().ExecContext(ctx, "UPDATE collections SET owner = ? WHERE id = ?", collection.Owner, collection.ID)
+	return err
+}
+
+func (s *SqliteStore) InsertCollectionItem(
+	ctx context.Context,
+	item sqlc.InsertCollectionItemParams,
+) (sqlc.CollectionItem, error) {
+	var inserted sqlc.CollectionItem
+	err := s.conn().QueryRowContext(
+		ctx,
+		sqliteInsertCollectionItem,
+		item.CollectionID,
+		item.ScryfallID,
+		item.Quantity,
+		item.Finish,
+		item.Condition,
+		item.LanguageCode,
+		item.AcquiredPriceCents,
+		item.AcquiredCurrency,
+		item.AcquiredOn,
+		item.CreatedAt,
+		item.UpdatedAt,
+	).Scan(collectionItemDest(&inserted)...)
+
+	return inserted, err
+}
+
+func (s *SqliteStore) GetCollectionItem(ctx context.Context, id int64) (sqlc.CollectionItem, error) {
+	var item sqlc.CollectionItem
+	err := s.conn().QueryRowContext(ctx, sqliteGetCollectionItem, id).Scan(collectionItemDest(&item)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.CollectionItem{}, ErrNotFound
+	}
+
+	return item, err
+}
+
+func (s *SqliteStore) GetCollectionItems(
+	ctx context.Context,
+	collectionID int64,
+) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	return s.getCollectionItems(ctx, sqliteGetCollectionItems, collectionID)
+}
+
+func (s *SqliteStore) GetOwnerCollectionItems(
+	ctx context.Context,
+	owner string,
+) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	return s.getCollectionItems(ctx, sqliteGetOwnerCollectionItems, owner)
+}
+
+func (s *SqliteStore) getCollectionItems(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]sqlc.GetCollectionItemsWithCardsRow, error) {
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.GetCollectionItemsWithCardsRow
+	for rows.Next() {
+		var i sqlc.GetCollectionItemsWithCardsRow
+		dest := append(collectionItemDest(&i.CollectionItem), cardDest(&i.Card)...)
+		if err := rows.Scan(append(dest, &i.SetCode, &i.SetName)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) UpdateCollectionItem(ctx context.Context, item sqlc.UpdateCollectionItemParams) error {
+	_, err := s.conn().ExecContext(
+		ctx,
+		sqliteUpdateCollectionItem,
+		item.ID,
+		item.CollectionID,
+		item.Quantity,
+		item.UpdatedAt,
+	)
+	return err
+}
+
+func (s *SqliteStore) DeleteCollectionItem(ctx context.Context, id int64) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM collection_items WHERE id = ?", id)
+	return err
+}
+
+func (s *SqliteStore) RecordCollectionPrices(ctx context.Context, date pgtype.Date) (int64, error) {
+	result, err := s.conn().ExecContext(ctx, sqliteRecordCollectionPrices, date)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *SqliteStore) GetCollectionPricesOn(
+	ctx context.Context,
+	collectionID int64,
+	date pgtype.Date,
+) ([]sqlc.CardPrice, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetCollectionPricesOn, collectionID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []sqlc.CardPrice
+	for rows.Next() {
+		var p sqlc.CardPrice
+		err := rows.Scan(
+			&p.ScryfallID,
+			&p.PriceDate,
+			&p.UsdCents,
+			&p.UsdFoilCents,
+			&p.UsdEtchedCents,
+			&p.EurCents,
+			&p.EurFoilCents,
+		)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+
+	return prices, rows.Err()
+}
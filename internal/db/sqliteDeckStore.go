@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var deckCardsColumns = []string{
+	"id",
+	"deck_id",
+	"zone",
+	"quantity",
+	"scryfall_oracle_id",
+	"scryfall_id",
+	"created_at",
+	"updated_at",
+	"finish",
+}
+
+const sqliteCreateDeck = `INSERT INTO decks (name, format, created_at, updated_at) VALUES (?, ?, ?, ?)
+RETURNING id, name, format, created_at, updated_at`
+
+const sqliteGetDeck = `SELECT id, name, format, created_at, updated_at
+FROM decks
+WHERE name = ?`
+
+const sqliteGetAllDecks = `SELECT id, name, format, created_at, updated_at
+FROM decks
+ORDER BY name ASC`
+
+var sqliteInsertDeckCard = `INSERT INTO deck_cards (
+    deck_id,
+    zone,
+    quantity,
+    scryfall_oracle_id,
+    scryfall_id,
+    created_at,
+    updated_at,
+    finish
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING ` + strings.Join(deckCardsColumns, ", ")
+
+var sqliteGetDeckCards = "SELECT " + strings.Join(deckCardsColumns, ", ") + `
+FROM deck_cards
+WHERE deck_id = ?
+ORDER BY id ASC`
+
+const sqliteUpdateDeckCard = `UPDATE deck_cards
+SET quantity = ?2,
+    updated_at = ?3
+WHERE id = ?1`
+
+func deckDest(deck *sqlc.Deck) []any {
+	return []any{&deck.ID, &deck.Name, &deck.Format, &deck.CreatedAt, &deck.UpdatedAt}
+}
+
+func deckCardDest(card *sqlc.DeckCard) []any {
+	return []any{
+		&card.ID,
+		&card.DeckID,
+		&card.Zone,
+		&card.Quantity,
+		&card.ScryfallOracleID,
+		&card.ScryfallID,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+		&card.Finish,
+	}
+}
+
+func (s *SqliteStore) CreateDeck(ctx context.Context, name string, format string) (sqlc.Deck, error) {
+	timestamp := pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+
+	var deck sqlc.Deck
+	err := s.conn().QueryRowContext(ctx, sqliteCreateDeck, name, format, timestamp, timestamp).Scan(deckDest(&deck)...)
+
+	return deck, err
+}
+
+func (s *SqliteStore) GetDeck(ctx context.Context, name string) (sqlc.Deck, error) {
+	var deck sqlc.Deck
+	err := s.conn().QueryRowContext(ctx, sqliteGetDeck, name).Scan(deckDest(&deck)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Deck{}, ErrNotFound
+	}
+
+	return deck, err
+}
+
+func (s *SqliteStore) GetAllDecks(ctx context.Context) ([]sqlc.Deck, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetAllDecks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.Deck
+	for rows.Next() {
+		var i sqlc.Deck
+		if err := rows.Scan(deckDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) InsertDeckCard(ctx context.Context, card sqlc.InsertDeckCardParams) (sqlc.DeckCard, error) {
+	var inserted sqlc.DeckCard
+	err := s.conn().QueryRowContext(
+		ctx,
+		sqliteInsertDeckCard,
+		card.DeckID,
+		card.Zone,
+		card.Quantity,
+		card.ScryfallOracleID,
+		card.ScryfallID,
+		card.CreatedAt,
+		card.UpdatedAt,
+		card.Finish,
+	).Scan(deckCardDest(&inserted)...)
+
+	return inserted, err
+}
+
+func (s *SqliteStore) GetDeckCards(ctx context.Context, deckID int64) ([]sqlc.DeckCard, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetDeckCards, deckID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.DeckCard
+	for rows.Next() {
+		var i sqlc.DeckCard
+		if err := rows.Scan(deckCardDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) UpdateDeckCard(ctx context.Context, card sqlc.UpdateDeckCardParams) error {
+	_, err := s.conn().ExecContext(ctx, sqliteUpdateDeckCard, card.ID, card.Quantity, card.UpdatedAt)
+	return err
+}
+
+func (s *SqliteStore) DeleteDeckCard(ctx context.Context, id int64) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM deck_cards WHERE id = ?", id)
+	return err
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"modernc.org/sqlite"
+
+	"FedeAbella/mtgdb/internal/search"
+)
+
+// SQLite has no pg_trgm or unaccent, so the functions card searches need are
+// registered for every connection, the trigram ones normalizing their
+// arguments themselves.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("word_similarity", 2, trigramFunction(search.WordSimilarity))
+	sqlite.MustRegisterDeterministicScalarFunction("similarity", 2, trigramFunction(search.Similarity))
+	sqlite.MustRegisterDeterministicScalarFunction("search_normalize", 1, searchNormalize)
+}
+
+func searchNormalize(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	value, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a text argument but got %T", args[0])
+	}
+
+	return search.Normalize(value), nil
+}
+
+func trigramFunction(
+	fn func(a, b string) float32,
+) func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	return func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+
+		a, aOk := args[0].(string)
+		b, bOk := args[1].(string)
+		if !aOk || !bOk {
+			return nil, fmt.Errorf("expected text arguments but got %T and %T", args[0], args[1])
+		}
+
+		return float64(fn(search.Normalize(a), search.Normalize(b))), nil
+	}
+}
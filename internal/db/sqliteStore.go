@@ -0,0 +1,599 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+	"FedeAbella/mtgdb/internal/search"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Rows per multi-row INSERT, keeping the cards batch well under SQLite's
+// limit of bound parameters per statement.
+const sqliteBatchRows = 500
+
+const sqliteLockPollInterval = 500 * time.Millisecond
+
+const sqliteGetAllSets = `SELECT scryfall_id, code, name, created_at, updated_at
+FROM sets
+ORDER BY code ASC`
+
+var sqliteGetAllCards = "SELECT " + strings.Join(cardHashColumns, ", ") + `
+FROM cards
+ORDER BY name ASC`
+
+// The ranks are computed once per card in the subquery, as the registered
+// trigram functions cannot use an index anyway.
+var sqliteSearchCards = "SELECT c." + strings.Join(cardsColumns, ", c.") + `, s.code, s.name, r.rank
+FROM (
+    SELECT
+        scryfall_id,
+        max(word_similarity(?1, name), coalesce(word_similarity(?1, spanish_name), 0)) AS rank,
+        max(similarity(?1, name), coalesce(similarity(?1, spanish_name), 0)) AS closeness
+    FROM cards
+) r
+INNER JOIN cards c ON c.scryfall_id = r.scryfall_id
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE r.rank >= ?2
+ORDER BY r.rank DESC, r.closeness DESC, c.name ASC, s.code ASC, c.collector_number ASC, c.language_code ASC
+LIMIT ?3 OFFSET ?4`
+
+var sqliteCardsWithSets = "SELECT c." + strings.Join(cardsColumns, ", c.") + `, s.code, s.name
+FROM cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id`
+
+var sqliteGetCard = sqliteCardsWithSets + `
+WHERE c.scryfall_id = ?`
+
+var sqliteGetOraclePrintings = sqliteCardsWithSets + `
+WHERE c.scryfall_oracle_id = ?
+ORDER BY s.code, c.collector_number, c.language_code ASC
+LIMIT ? OFFSET ?`
+
+var sqliteGetAllCardsWithSets = sqliteCardsWithSets + `
+WHERE (?1 = '' OR s.code = ?1)
+    AND (?2 = '' OR c.language_code = ?2)
+    AND (?3 IS NULL OR c.updated_at >= ?3)
+ORDER BY s.code, CAST(c.collector_number AS INTEGER), c.collector_number, c.language_code ASC`
+
+// CAST reads the leading digits of the collector number, and 0 if there are
+// none, as the Postgres query does.
+var sqliteGetSetCards = sqliteCardsWithSets + `
+WHERE s.code = ?
+ORDER BY CAST(c.collector_number AS INTEGER), c.collector_number, c.language_code ASC
+LIMIT ? OFFSET ?`
+
+const sqliteGetSet = `SELECT scryfall_id, code, name, created_at, updated_at
+FROM sets
+WHERE code = ?`
+
+const sqliteGetCardNames = `SELECT DISTINCT name, spanish_name, scryfall_oracle_id
+FROM cards`
+
+const sqliteGetCardsVersion = `SELECT count(*), COALESCE(max(updated_at), '')
+FROM cards`
+
+const sqliteUpdateSet = `UPDATE sets
+SET code = ?,
+    name = ?,
+    updated_at = ?
+WHERE scryfall_id = ?`
+
+const sqliteUpdateCard = `UPDATE cards
+SET set_id = ?,
+    name = ?,
+    collector_number = ?,
+    color_identity = ?,
+    colors = ?,
+    language_code = ?,
+    spanish_name = ?,
+    rarity = ?,
+    type_line = ?,
+    scryfall_api_uri = ?,
+    scryfall_web_uri = ?,
+    scryfall_oracle_id = ?,
+    updated_at = ?,
+    raw = ?,
+    raw_hash = ?
+WHERE scryfall_id = ?`
+
+const sqliteInsertQuarantinedCard = `INSERT INTO quarantined_cards (scryfall_id, reason, raw, created_at)
+VALUES (?, ?, ?, ?)`
+
+// sqliteConn is satisfied by both *sql.DB and *sql.Tx.
+type sqliteConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SqliteStore keeps the card DB in a single SQLite file. The sync lock is an
+// advisory lock on a file next to it, so it is released if the process dies.
+type SqliteStore struct {
+	db         *sql.DB
+	tx         *sql.Tx
+	savepoints int
+
+	lockPath string
+	lockFile *os.File
+}
+
+// NewSqliteStore wraps an open SQLite handle. An empty lockPath disables the
+// sync lock, for in-memory databases no other process can reach.
+func NewSqliteStore(db *sql.DB, lockPath string) *SqliteStore {
+	return &SqliteStore{
+		db:       db,
+		lockPath: lockPath,
+	}
+}
+
+func (s *SqliteStore) conn() sqliteConn {
+	if s.tx != nil {
+		return s.tx
+	}
+
+	return s.db
+}
+
+func (s *SqliteStore) GetAllSets(ctx context.Context) ([]sqlc.Set, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetAllSets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.Set
+	for rows.Next() {
+		var i sqlc.Set
+		if err := rows.Scan(
+			&i.ScryfallID,
+			&i.Code,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) GetAllCards(ctx context.Context) ([]sqlc.GetAllCardsRow, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetAllCards)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.GetAllCardsRow
+	for rows.Next() {
+		var i sqlc.GetAllCardsRow
+		if err := rows.Scan(cardHashDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) GetCard(ctx context.Context, scryfallID pgtype.UUID) (CardWithSet, error) {
+	var card CardWithSet
+	err := s.conn().QueryRowContext(ctx, sqliteGetCard, scryfallID).Scan(cardWithSetDest(&card)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CardWithSet{}, ErrNotFound
+	}
+
+	return card, err
+}
+
+func (s *SqliteStore) GetCardNames(ctx context.Context) ([]sqlc.GetCardNamesRow, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetCardNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.GetCardNamesRow
+	for rows.Next() {
+		var i sqlc.GetCardNamesRow
+		if err := rows.Scan(&i.Name, &i.SpanishName, &i.ScryfallOracleID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) GetCardsVersion(ctx context.Context) (sqlc.GetCardsVersionRow, error) {
+	var version sqlc.GetCardsVersionRow
+	err := s.conn().QueryRowContext(ctx, sqliteGetCardsVersion).Scan(&version.CardCount, &version.LastUpdatedAt)
+
+	return version, err
+}
+
+func (s *SqliteStore) GetSet(ctx context.Context, code string) (sqlc.Set, error) {
+	var set sqlc.Set
+	err := s.conn().QueryRowContext(ctx, sqliteGetSet, code).Scan(
+		&set.ScryfallID,
+		&set.Code,
+		&set.Name,
+		&set.CreatedAt,
+		&set.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.Set{}, ErrNotFound
+	}
+
+	return set, err
+}
+
+func (s *SqliteStore) GetOraclePrintings(
+	ctx context.Context,
+	oracleID pgtype.UUID,
+	page Page,
+) ([]CardWithSet, error) {
+	return s.queryCardsWithSets(ctx, sqliteGetOraclePrintings, oracleID, page.Limit, page.Offset)
+}
+
+func (s *SqliteStore) GetSetCards(ctx context.Context, code string, page Page) ([]CardWithSet, error) {
+	return s.queryCardsWithSets(ctx, sqliteGetSetCards, code, page.Limit, page.Offset)
+}
+
+func (s *SqliteStore) GetAllCardsWithSets(ctx context.Context, filter CardFilter, fn func(CardWithSet) error) error {
+	rows, err := s.conn().QueryContext(
+		ctx,
+		sqliteGetAllCardsWithSets,
+		filter.SetCode,
+		filter.LanguageCode,
+		filter.UpdatedSince,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card CardWithSet
+		if err := rows.Scan(cardWithSetDest(&card)...); err != nil {
+			return err
+		}
+		if err := fn(card); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *SqliteStore) QueryCards(ctx context.Context, node cardquery.Node, page Page) ([]CardWithSet, error) {
+	query, args := queryCardsSQL(node, cardquery.Sqlite, page)
+	return s.queryCardsWithSets(ctx, query, args...)
+}
+
+func (s *SqliteStore) queryCardsWithSets(ctx context.Context, query string, args ...any) ([]CardWithSet, error) {
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []CardWithSet
+	for rows.Next() {
+		var i CardWithSet
+		if err := rows.Scan(cardWithSetDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) SearchCards(ctx context.Context, query string, page Page) ([]sqlc.SearchCardsRow, error) {
+	rows, err := s.conn().QueryContext(
+		ctx,
+		sqliteSearchCards,
+		query,
+		search.WordSimilarityThreshold,
+		page.Limit,
+		page.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.SearchCardsRow
+	for rows.Next() {
+		var i sqlc.SearchCardsRow
+		if err := rows.Scan(
+			&i.ScryfallID,
+			&i.SetID,
+			&i.Name,
+			&i.CollectorNumber,
+			&i.ColorIdentity,
+			&i.Colors,
+			&i.LanguageCode,
+			&i.SpanishName,
+			&i.Rarity,
+			&i.TypeLine,
+			&i.ScryfallApiUri,
+			&i.ScryfallWebUri,
+			&i.ScryfallOracleID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Raw,
+			&i.RawHash,
+			&i.SetCode,
+			&i.SetName,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) InsertSets(ctx context.Context, sets []sqlc.InsertSetsParams) (int64, error) {
+	rows := make([][]any, 0, len(sets))
+	for _, set := range sets {
+		rows = append(rows, setRow(set))
+	}
+
+	return s.upsertBatches(ctx, "sets", setsColumns, rows)
+}
+
+func (s *SqliteStore) InsertCards(ctx context.Context, cards []sqlc.InsertCardsParams) (int64, error) {
+	rows := make([][]any, 0, len(cards))
+	for _, card := range cards {
+		rows = append(rows, cardRow(card))
+	}
+
+	return s.upsertBatches(ctx, "cards", cardsColumns, rows)
+}
+
+func (s *SqliteStore) InsertQuarantinedCards(
+	ctx context.Context,
+	cards []sqlc.InsertQuarantinedCardsParams,
+) (int64, error) {
+	var inserted int64
+	err := s.withTx(ctx, func(txStore *SqliteStore) error {
+		for _, card := range cards {
+			if _, err := txStore.conn().ExecContext(
+				ctx,
+				sqliteInsertQuarantinedCard,
+				card.ScryfallID,
+				card.Reason,
+				card.Raw,
+				card.CreatedAt,
+			); err != nil {
+				return err
+			}
+			inserted++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+// upsertBatches writes rows with multi-row INSERTs in a single transaction,
+// updating every column but created_at when the scryfall id already exists.
+func (s *SqliteStore) upsertBatches(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column == "scryfall_id" || column == "created_at" {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf("%s = excluded.%s", column, column))
+	}
+
+	var inserted int64
+	err := s.withTx(ctx, func(txStore *SqliteStore) error {
+		for batch := range slices.Chunk(rows, sqliteBatchRows) {
+			values := make([]string, 0, len(batch))
+			args := make([]any, 0, len(batch)*len(columns))
+			for _, row := range batch {
+				values = append(values, placeholders)
+				args = append(args, row...)
+			}
+
+			query := fmt.Sprintf(
+				"INSERT INTO %s (%s) VALUES %s ON CONFLICT (scryfall_id) DO UPDATE SET %s",
+				table,
+				strings.Join(columns, ", "),
+				strings.Join(values, ", "),
+				strings.Join(updates, ", "),
+			)
+
+			result, err := txStore.conn().ExecContext(ctx, query, args...)
+			if err != nil {
+				log.Println(err)
+				return err
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			inserted += affected
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+func (s *SqliteStore) UpdateSet(ctx context.Context, set sqlc.UpdateSetParams) error {
+	_, err := s.conn().ExecContext(
+		ctx,
+		sqliteUpdateSet,
+		set.Code,
+		set.Name,
+		set.UpdatedAt,
+		set.ScryfallID,
+	)
+	return err
+}
+
+func (s *SqliteStore) UpdateCard(ctx context.Context, card sqlc.UpdateCardParams) error {
+	_, err := s.conn().ExecContext(
+		ctx,
+		sqliteUpdateCard,
+		card.SetID,
+		card.Name,
+		card.CollectorNumber,
+		card.ColorIdentity,
+		card.Colors,
+		card.LanguageCode,
+		card.SpanishName,
+		card.Rarity,
+		card.TypeLine,
+		card.ScryfallApiUri,
+		card.ScryfallWebUri,
+		card.ScryfallOracleID,
+		card.UpdatedAt,
+		card.Raw,
+		card.RawHash,
+		card.ScryfallID,
+	)
+	return err
+}
+
+func (s *SqliteStore) DeleteSet(ctx context.Context, scryfallID pgtype.UUID) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM sets WHERE scryfall_id = ?", scryfallID)
+	return err
+}
+
+func (s *SqliteStore) DeleteCard(ctx context.Context, scryfallID pgtype.UUID) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM cards WHERE scryfall_id = ?", scryfallID)
+	return err
+}
+
+func (s *SqliteStore) DeleteQuarantinedCards(ctx context.Context) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM quarantined_cards")
+	return err
+}
+
+func (s *SqliteStore) AcquireSyncLock(ctx context.Context, wait bool) error {
+	if s.lockPath == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(s.lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if wait {
+		log.Println("Waiting for sync lock")
+	}
+
+	ticker := time.NewTicker(sqliteLockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		err = tryLockFile(f)
+		if err == nil {
+			s.lockFile = f
+			return nil
+		}
+
+		if !errors.Is(err, ErrSyncInProgress) || !wait {
+			f.Close()
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SqliteStore) ReleaseSyncLock(ctx context.Context) error {
+	if s.lockFile == nil {
+		return nil
+	}
+
+	f := s.lockFile
+	s.lockFile = nil
+	if err := unlockFile(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *SqliteStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	return s.withTx(ctx, func(txStore *SqliteStore) error {
+		return fn(txStore)
+	})
+}
+
+// withTx starts a savepoint instead when s is already bound to a transaction.
+func (s *SqliteStore) withTx(ctx context.Context, fn func(*SqliteStore) error) error {
+	if s.tx != nil {
+		return s.withSavepoint(ctx, fn)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(&SqliteStore{db: s.db, tx: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SqliteStore) withSavepoint(ctx context.Context, fn func(*SqliteStore) error) error {
+	savepoints := s.savepoints + 1
+	name := fmt.Sprintf("sp%d", savepoints)
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(&SqliteStore{db: s.db, tx: s.tx, savepoints: savepoints}); err != nil {
+		if _, rollbackErr := s.tx.ExecContext(context.Background(), "ROLLBACK TO "+name); rollbackErr != nil {
+			log.Println(rollbackErr)
+		}
+		return err
+	}
+
+	_, err := s.tx.ExecContext(ctx, "RELEASE "+name)
+	return err
+}
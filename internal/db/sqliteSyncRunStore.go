@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var syncRunsColumns = []string{
+	"id",
+	"started_at",
+	"finished_at",
+	"sets_inserted",
+	"sets_updated",
+	"cards_inserted",
+	"cards_updated",
+	"cards_quarantined",
+	"prices_recorded",
+}
+
+var sqliteInsertSyncRun = `INSERT INTO sync_runs (
+    started_at,
+    finished_at,
+    sets_inserted,
+    sets_updated,
+    cards_inserted,
+    cards_updated,
+    cards_quarantined,
+    prices_recorded
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING ` + strings.Join(syncRunsColumns, ", ")
+
+var sqliteGetLastSyncRun = "SELECT " + strings.Join(syncRunsColumns, ", ") + `
+FROM sync_runs
+ORDER BY id DESC
+LIMIT 1`
+
+func syncRunDest(run *sqlc.SyncRun) []any {
+	return []any{
+		&run.ID,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.SetsInserted,
+		&run.SetsUpdated,
+		&run.CardsInserted,
+		&run.CardsUpdated,
+		&run.CardsQuarantined,
+		&run.PricesRecorded,
+	}
+}
+
+func (s *SqliteStore) InsertSyncRun(ctx context.Context, run sqlc.InsertSyncRunParams) (sqlc.SyncRun, error) {
+	var inserted sqlc.SyncRun
+	err := s.conn().QueryRowContext(
+		ctx,
+		sqliteInsertSyncRun,
+		run.StartedAt,
+		run.FinishedAt,
+		run.SetsInserted,
+		run.SetsUpdated,
+		run.CardsInserted,
+		run.CardsUpdated,
+		run.CardsQuarantined,
+		run.PricesRecorded,
+	).Scan(syncRunDest(&inserted)...)
+
+	return inserted, err
+}
+
+func (s *SqliteStore) GetLastSyncRun(ctx context.Context) (sqlc.SyncRun, error) {
+	var run sqlc.SyncRun
+	err := s.conn().QueryRowContext(ctx, sqliteGetLastSyncRun).Scan(syncRunDest(&run)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sqlc.SyncRun{}, ErrNotFound
+	}
+
+	return run, err
+}
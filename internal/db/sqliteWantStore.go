@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var wantsColumns = []string{
+	"id",
+	"owner",
+	"quantity",
+	"scryfall_oracle_id",
+	"scryfall_id",
+	"finish",
+	"created_at",
+	"updated_at",
+}
+
+var sqliteInsertWant = `INSERT INTO wants (
+    owner,
+    quantity,
+    scryfall_oracle_id,
+    scryfall_id,
+    finish,
+    created_at,
+    updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING ` + strings.Join(wantsColumns, ", ")
+
+var sqliteGetWants = "SELECT " + strings.Join(wantsColumns, ", ") + `
+FROM wants
+WHERE owner = ?
+ORDER BY id ASC`
+
+const sqliteUpdateWant = `UPDATE wants
+SET quantity = ?2,
+    updated_at = ?3
+WHERE id = ?1`
+
+func wantDest(want *sqlc.Want) []any {
+	return []any{
+		&want.ID,
+		&want.Owner,
+		&want.Quantity,
+		&want.ScryfallOracleID,
+		&want.ScryfallID,
+		&want.Finish,
+		&want.CreatedAt,
+		&want.UpdatedAt,
+	}
+}
+
+func (s *SqliteStore) InsertWant(ctx context.Context, want sqlc.InsertWantParams) (sqlc.Want, error) {
+	var inserted sqlc.Want
+	err := s.conn().QueryRowContext(
+		ctx,
+		sqliteInsertWant,
+		want.Owner,
+		want.Quantity,
+		want.ScryfallOracleID,
+		want.ScryfallID,
+		want.Finish,
+		want.CreatedAt,
+		want.UpdatedAt,
+	).Scan(wantDest(&inserted)...)
+
+	return inserted, err
+}
+
+func (s *SqliteStore) GetWants(ctx context.Context, owner string) ([]sqlc.Want, error) {
+	rows, err := s.conn().QueryContext(ctx, sqliteGetWants, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []sqlc.Want
+	for rows.Next() {
+		var i sqlc.Want
+		if err := rows.Scan(wantDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (s *SqliteStore) UpdateWant(ctx context.Context, want sqlc.UpdateWantParams) error {
+	_, err := s.conn().ExecContext(ctx, sqliteUpdateWant, want.ID, want.Quantity, want.UpdatedAt)
+	return err
+}
+
+func (s *SqliteStore) DeleteWant(ctx context.Context, id int64) error {
+	_, err := s.conn().ExecContext(ctx, "DELETE FROM wants WHERE id = ?", id)
+	return err
+}
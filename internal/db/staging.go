@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Rows are copied into a temporary table dropped on commit and merged from
+// there, so a row written between the diff and the insert (another writer, or
+// a rerun after a partial failure) updates it instead of failing the batch.
+func (s *PostgresStore) mergeThroughStaging(
+	ctx context.Context,
+	fn func(queries *sqlc.Queries) (int64, error),
+) (int64, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback(context.Background())
+
+	merged, err := fn(s.queries.WithTx(tx))
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return merged, nil
+}
+
+func (s *PostgresStore) mergeSets(ctx context.Context, sets []sqlc.InsertSetsParams) (int64, error) {
+	return s.mergeThroughStaging(ctx, func(queries *sqlc.Queries) (int64, error) {
+		if err := queries.CreateSetsStaging(ctx); err != nil {
+			return 0, err
+		}
+
+		if _, err := queries.InsertSets(ctx, sets); err != nil {
+			return 0, err
+		}
+
+		return queries.MergeSetsStaging(ctx)
+	})
+}
+
+func (s *PostgresStore) mergeCards(ctx context.Context, cards []sqlc.InsertCardsParams) (int64, error) {
+	return s.mergeThroughStaging(ctx, func(queries *sqlc.Queries) (int64, error) {
+		if err := queries.CreateCardsStaging(ctx); err != nil {
+			return 0, err
+		}
+
+		if _, err := queries.InsertCards(ctx, cards); err != nil {
+			return 0, err
+		}
+
+		return queries.MergeCardsStaging(ctx)
+	})
+}
@@ -0,0 +1,287 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/cardquery"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Store is the storage the sync runs against. Inserts behave as upserts on
+// the scryfall id, so retrying a batch is safe.
+type Store interface {
+	GetAllSets(ctx context.Context) ([]sqlc.Set, error)
+	// GetAllCards leaves out the raw JSON, as the sync compares its hash only.
+	GetAllCards(ctx context.Context) ([]sqlc.GetAllCardsRow, error)
+
+	// GetCard and GetSet return ErrNotFound if there is no such row.
+	GetCard(ctx context.Context, scryfallID pgtype.UUID) (CardWithSet, error)
+	GetSet(ctx context.Context, code string) (sqlc.Set, error)
+	// GetOraclePrintings returns every printing of a card, ordered by set and
+	// collector number.
+	GetOraclePrintings(ctx context.Context, oracleID pgtype.UUID, page Page) ([]CardWithSet, error)
+	// GetSetCards returns the printings in a set ordered by collector number,
+	// numerically first so 10 sorts after 9.
+	GetSetCards(ctx context.Context, code string, page Page) ([]CardWithSet, error)
+
+	// GetAllCardsWithSets calls fn with every printing matching filter, in
+	// set and collector number order, without loading them all at once. It
+	// stops at the first error fn returns and returns it.
+	GetAllCardsWithSets(ctx context.Context, filter CardFilter, fn func(CardWithSet) error) error
+
+	// SearchCards returns printings whose English or Spanish name fuzzy
+	// matches query, ignoring case and accents, best matches first.
+	SearchCards(ctx context.Context, query string, page Page) ([]sqlc.SearchCardsRow, error)
+
+	// GetCardNames returns each distinct name pair with its oracle id.
+	GetCardNames(ctx context.Context) ([]sqlc.GetCardNamesRow, error)
+	// GetCardsVersion changes whenever a sync writes cards, so readers
+	// caching them can tell when to reload.
+	GetCardsVersion(ctx context.Context) (sqlc.GetCardsVersionRow, error)
+
+	InsertSets(ctx context.Context, sets []sqlc.InsertSetsParams) (int64, error)
+	InsertCards(ctx context.Context, cards []sqlc.InsertCardsParams) (int64, error)
+	InsertQuarantinedCards(ctx context.Context, cards []sqlc.InsertQuarantinedCardsParams) (int64, error)
+
+	UpdateSet(ctx context.Context, set sqlc.UpdateSetParams) error
+	UpdateCard(ctx context.Context, card sqlc.UpdateCardParams) error
+
+	DeleteSet(ctx context.Context, scryfallID pgtype.UUID) error
+	DeleteCard(ctx context.Context, scryfallID pgtype.UUID) error
+	DeleteQuarantinedCards(ctx context.Context) error
+
+	// AcquireSyncLock returns ErrSyncInProgress if another sync holds the
+	// lock and wait is false.
+	AcquireSyncLock(ctx context.Context, wait bool) error
+	ReleaseSyncLock(ctx context.Context) error
+
+	// WithTx runs fn against a Store bound to a new transaction, committing
+	// it if fn returns nil and rolling it back otherwise.
+	WithTx(ctx context.Context, fn func(Store) error) error
+}
+
+// CardQuerier is implemented by stores that can filter cards with a
+// Scryfall-style query, ordered by name.
+type CardQuerier interface {
+	QueryCards(ctx context.Context, node cardquery.Node, page Page) ([]CardWithSet, error)
+}
+
+// CollectionStore is implemented by stores that keep collections of owned
+// printings alongside the cards.
+type CollectionStore interface {
+	Store
+
+	CreateCollection(ctx context.Context, name string) (sqlc.Collection, error)
+	// GetCollection and GetCollectionItem return ErrNotFound if there is no
+	// such row.
+	GetCollection(ctx context.Context, name string) (sqlc.Collection, error)
+	GetAllCollections(ctx context.Context) ([]sqlc.Collection, error)
+	UpdateCollectionOwner(ctx context.Context, collection sqlc.UpdateCollectionOwnerParams) error
+
+	InsertCollectionItem(ctx context.Context, item sqlc.InsertCollectionItemParams) (sqlc.CollectionItem, error)
+	GetCollectionItem(ctx context.Context, id int64) (sqlc.CollectionItem, error)
+	// GetCollectionItems returns the items with their printings, ordered by
+	// set and collector number.
+	GetCollectionItems(ctx context.Context, collectionID int64) ([]sqlc.GetCollectionItemsWithCardsRow, error)
+	// GetOwnerCollectionItems returns the items in every collection of owner,
+	// ordered the same way.
+	GetOwnerCollectionItems(ctx context.Context, owner string) ([]sqlc.GetCollectionItemsWithCardsRow, error)
+	UpdateCollectionItem(ctx context.Context, item sqlc.UpdateCollectionItemParams) error
+	DeleteCollectionItem(ctx context.Context, id int64) error
+
+	// RecordCollectionPrices stores the current prices of the printings in
+	// any collection as their prices on date, returning how many it stored.
+	RecordCollectionPrices(ctx context.Context, date pgtype.Date) (int64, error)
+	// GetCollectionPricesOn returns the latest prices recorded on or before
+	// date for the printings in a collection.
+	GetCollectionPricesOn(ctx context.Context, collectionID int64, date pgtype.Date) ([]sqlc.CardPrice, error)
+}
+
+// DeckStore is implemented by stores that keep decklists alongside the cards.
+type DeckStore interface {
+	Store
+
+	CreateDeck(ctx context.Context, name string, format string) (sqlc.Deck, error)
+	// GetDeck returns ErrNotFound if there is no such deck.
+	GetDeck(ctx context.Context, name string) (sqlc.Deck, error)
+	GetAllDecks(ctx context.Context) ([]sqlc.Deck, error)
+
+	InsertDeckCard(ctx context.Context, card sqlc.InsertDeckCardParams) (sqlc.DeckCard, error)
+	GetDeckCards(ctx context.Context, deckID int64) ([]sqlc.DeckCard, error)
+	UpdateDeckCard(ctx context.Context, card sqlc.UpdateDeckCardParams) error
+	DeleteDeckCard(ctx context.Context, id int64) error
+}
+
+// WantStore is implemented by stores that keep want lists alongside the
+// collections they are matched against.
+type WantStore interface {
+	CollectionStore
+
+	InsertWant(ctx context.Context, want sqlc.InsertWantParams) (sqlc.Want, error)
+	GetWants(ctx context.Context, owner string) ([]sqlc.Want, error)
+	UpdateWant(ctx context.Context, want sqlc.UpdateWantParams) error
+	DeleteWant(ctx context.Context, id int64) error
+}
+
+// SyncRunStore is implemented by stores that keep a record of the syncs they
+// went through.
+type SyncRunStore interface {
+	Store
+
+	InsertSyncRun(ctx context.Context, run sqlc.InsertSyncRunParams) (sqlc.SyncRun, error)
+	// GetLastSyncRun returns ErrNotFound if no sync finished yet.
+	GetLastSyncRun(ctx context.Context) (sqlc.SyncRun, error)
+}
+
+var ErrNotFound = errors.New("not found")
+
+type Page struct {
+	Limit  int32
+	Offset int32
+}
+
+// CardFilter narrows the printings GetAllCardsWithSets returns, each field
+// only if set.
+type CardFilter struct {
+	SetCode      string
+	LanguageCode string
+	UpdatedSince pgtype.Timestamp
+}
+
+// CardWithSet has the layout of the sqlc rows embedding a card with its set,
+// so any of them converts to it.
+type CardWithSet struct {
+	Card    sqlc.Card
+	SetCode string
+	SetName string
+}
+
+func toCardsWithSets[T ~struct {
+	Card    sqlc.Card
+	SetCode string
+	SetName string
+}](rows []T) []CardWithSet {
+	cards := make([]CardWithSet, 0, len(rows))
+	for _, row := range rows {
+		cards = append(cards, CardWithSet(row))
+	}
+
+	return cards
+}
+
+// cardDest returns pointers to card's fields in the order of cardsColumns,
+// for both pgx and database/sql scans.
+func cardDest(card *sqlc.Card) []any {
+	return []any{
+		&card.ScryfallID,
+		&card.SetID,
+		&card.Name,
+		&card.CollectorNumber,
+		&card.ColorIdentity,
+		&card.Colors,
+		&card.LanguageCode,
+		&card.SpanishName,
+		&card.Rarity,
+		&card.TypeLine,
+		&card.ScryfallApiUri,
+		&card.ScryfallWebUri,
+		&card.ScryfallOracleID,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+		&card.Raw,
+		&card.RawHash,
+	}
+}
+
+// cardHashDest returns pointers to card's fields in the order of the
+// GetAllCards columns, every card column but raw.
+func cardHashDest(card *sqlc.GetAllCardsRow) []any {
+	return []any{
+		&card.ScryfallID,
+		&card.SetID,
+		&card.Name,
+		&card.CollectorNumber,
+		&card.ColorIdentity,
+		&card.Colors,
+		&card.LanguageCode,
+		&card.SpanishName,
+		&card.Rarity,
+		&card.TypeLine,
+		&card.ScryfallApiUri,
+		&card.ScryfallWebUri,
+		&card.ScryfallOracleID,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+		&card.RawHash,
+	}
+}
+
+func cardWithSetDest(card *CardWithSet) []any {
+	return append(cardDest(&card.Card), &card.SetCode, &card.SetName)
+}
+
+var setsColumns = []string{"scryfall_id", "code", "name", "created_at", "updated_at"}
+
+var cardsColumns = []string{
+	"scryfall_id",
+	"set_id",
+	"name",
+	"collector_number",
+	"color_identity",
+	"colors",
+	"language_code",
+	"spanish_name",
+	"rarity",
+	"type_line",
+	"scryfall_api_uri",
+	"scryfall_web_uri",
+	"scryfall_oracle_id",
+	"created_at",
+	"updated_at",
+	"raw",
+	"raw_hash",
+}
+
+// cardHashColumns are the columns GetAllCards reads, in the order of
+// cardHashDest.
+var cardHashColumns = slices.DeleteFunc(slices.Clone(cardsColumns), func(column string) bool {
+	return column == "raw"
+})
+
+// setRow and cardRow return values in the order of setsColumns and
+// cardsColumns, for backends writing rows in bulk.
+func setRow(set sqlc.InsertSetsParams) []any {
+	return []any{
+		set.ScryfallID,
+		set.Code,
+		set.Name,
+		set.CreatedAt,
+		set.UpdatedAt,
+	}
+}
+
+func cardRow(card sqlc.InsertCardsParams) []any {
+	return []any{
+		card.ScryfallID,
+		card.SetID,
+		card.Name,
+		card.CollectorNumber,
+		card.ColorIdentity,
+		card.Colors,
+		card.LanguageCode,
+		card.SpanishName,
+		card.Rarity,
+		card.TypeLine,
+		card.ScryfallApiUri,
+		card.ScryfallWebUri,
+		card.ScryfallOracleID,
+		card.CreatedAt,
+		card.UpdatedAt,
+		card.Raw,
+		card.RawHash,
+	}
+}
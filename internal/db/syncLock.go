@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+var ErrSyncInProgress = errors.New("sync already in progress")
+
+func (db *DbConf) acquireSyncLock(ctx context.Context) error {
+	if err := db.Store.AcquireSyncLock(ctx, db.WaitForLock); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+func (db *DbConf) releaseSyncLock() {
+	// Released even if the sync context was cancelled
+	if err := db.Store.ReleaseSyncLock(context.Background()); err != nil {
+		log.Println(err)
+	}
+}
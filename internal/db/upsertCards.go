@@ -3,6 +3,8 @@ package db
 import (
 	"context"
 	"log"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,21 +13,65 @@ import (
 	"FedeAbella/mtgdb/internal/sqlc"
 )
 
+// The file cards are split in chunks diffed concurrently against the db
+// cards, each worker building its own slices to avoid sharing them.
 func mapCardsToInsertAndUpdate(
 	fileCardMap map[uuid.UUID]source.CardPrinting,
-	dbCards []sqlc.Card,
+	dbCards []sqlc.GetAllCardsRow,
 	now time.Time,
+	workers int,
 ) ([]sqlc.InsertCardsParams, []sqlc.UpdateCardParams) {
-	dbCardMap := map[string]sqlc.Card{}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	dbCardMap := map[string]sqlc.GetAllCardsRow{}
 	for _, dbCard := range dbCards {
 		dbCardMap[dbCard.ScryfallID.String()] = dbCard
 	}
 
+	fileCards := make([]source.CardPrinting, 0, len(fileCardMap))
+	for _, fileCard := range fileCardMap {
+		fileCards = append(fileCards, fileCard)
+	}
+
+	chunkSize := (len(fileCards) + workers - 1) / workers
+	chunkInserts := make([][]sqlc.InsertCardsParams, workers)
+	chunkUpdates := make([][]sqlc.UpdateCardParams, workers)
+
+	wg := sync.WaitGroup{}
+	for worker := range workers {
+		start := min(worker*chunkSize, len(fileCards))
+		end := min(start+chunkSize, len(fileCards))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			chunkInserts[worker], chunkUpdates[worker] = diffCards(fileCards[start:end], dbCardMap, now)
+		}()
+	}
+	wg.Wait()
+
+	cardsToInsert := make([]sqlc.InsertCardsParams, 0)
+	cardsToUpdate := make([]sqlc.UpdateCardParams, 0)
+	for worker := range workers {
+		cardsToInsert = append(cardsToInsert, chunkInserts[worker]...)
+		cardsToUpdate = append(cardsToUpdate, chunkUpdates[worker]...)
+	}
+
+	return cardsToInsert, cardsToUpdate
+}
+
+func diffCards(
+	fileCards []source.CardPrinting,
+	dbCardMap map[string]sqlc.GetAllCardsRow,
+	now time.Time,
+) ([]sqlc.InsertCardsParams, []sqlc.UpdateCardParams) {
 	cardsToInsert := make([]sqlc.InsertCardsParams, 0)
 	cardsToUpdate := make([]sqlc.UpdateCardParams, 0)
 
-	for fileCardId, fileCard := range fileCardMap {
-		dbCard, inDb := dbCardMap[fileCardId.String()]
+	for _, fileCard := range fileCards {
+		dbCard, inDb := dbCardMap[fileCard.ScryfallId.String()]
 		if !inDb {
 			cardsToInsert = append(cardsToInsert, fileCard.ToDbInsertCard(now))
 			continue
@@ -39,13 +85,17 @@ func mapCardsToInsertAndUpdate(
 	return cardsToInsert, cardsToUpdate
 }
 
-func (db *DbConf) insertCards(cardsToInsert []sqlc.InsertCardsParams) error {
+func (db *DbConf) insertCards(ctx context.Context, cardsToInsert []sqlc.InsertCardsParams) error {
 	if len(cardsToInsert) == 0 {
 		return nil
 	}
 
 	insertStart := time.Now()
-	if _, err := db.Queries.InsertCards(context.Background(), cardsToInsert); err != nil {
+	err := writeInBatches(ctx, db.Writers, cardsToInsert, func(ctx context.Context, batch []sqlc.InsertCardsParams) error {
+		_, err := db.Store.InsertCards(ctx, batch)
+		return err
+	})
+	if err != nil {
 		log.Println(err)
 		return err
 	}
@@ -59,30 +109,25 @@ func (db *DbConf) insertCards(cardsToInsert []sqlc.InsertCardsParams) error {
 	return nil
 }
 
-func (db *DbConf) updateCards(cardsToUpdate []sqlc.UpdateCardParams) error {
+func (db *DbConf) updateCards(ctx context.Context, cardsToUpdate []sqlc.UpdateCardParams) error {
 	if len(cardsToUpdate) == 0 {
 		return nil
 	}
 
-	tx, err := db.Conn.Begin(context.Background())
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-	defer tx.Rollback(context.Background())
-
-	txq := db.Queries.WithTx(tx)
-	log.Println("Starting db card update transaction")
-
+	// Each batch updates in a transaction of its own, so a failed sync keeps
+	// the batches already written, as with inserts
 	updateStart := time.Now()
-	for _, card := range cardsToUpdate {
-		if err := txq.UpdateCard(context.Background(), card); err != nil {
-			log.Println(err)
-			return err
-		}
-	}
-
-	if err = tx.Commit(context.Background()); err != nil {
+	err := writeInBatches(ctx, db.Writers, cardsToUpdate, func(ctx context.Context, batch []sqlc.UpdateCardParams) error {
+		return db.Store.WithTx(ctx, func(tx Store) error {
+			for _, card := range batch {
+				if err := tx.UpdateCard(ctx, card); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
 		log.Println(err)
 		return err
 	}
@@ -96,28 +141,28 @@ func (db *DbConf) updateCards(cardsToUpdate []sqlc.UpdateCardParams) error {
 	return nil
 }
 
-func (db *DbConf) upsertCards(fileCardMap map[uuid.UUID]source.CardPrinting) error {
+func (db *DbConf) upsertCards(ctx context.Context, fileCardMap map[uuid.UUID]source.CardPrinting) (int, int, error) {
 
-	dbCards, err := db.Queries.GetAllCards(context.Background())
+	dbCards, err := db.Store.GetAllCards(ctx)
 	if err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
-	cardsToInsert, cardsToUpdate := mapCardsToInsertAndUpdate(fileCardMap, dbCards, time.Now())
+	cardsToInsert, cardsToUpdate := mapCardsToInsertAndUpdate(fileCardMap, dbCards, time.Now(), db.Workers)
 
 	log.Printf("%d cards to be inserted in db", len(cardsToInsert))
 	log.Printf("%d cards to be updated in db", len(cardsToUpdate))
 
-	if err = db.insertCards(cardsToInsert); err != nil {
+	if err = db.insertCards(ctx, cardsToInsert); err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
-	if err = db.updateCards(cardsToUpdate); err != nil {
+	if err = db.updateCards(ctx, cardsToUpdate); err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
-	return nil
+	return len(cardsToInsert), len(cardsToUpdate), nil
 }
@@ -18,14 +18,14 @@ func Test_MapToInsertAndUpdate(t *testing.T) {
 
 	tests := []struct {
 		name                string
-		cardsInDb           []sqlc.Card
+		cardsInDb           []sqlc.GetAllCardsRow
 		cardsInFile         map[uuid.UUID]source.CardPrinting
 		expectedInsertCards []sqlc.InsertCardsParams
 		expectedUpdateCards []sqlc.UpdateCardParams
 	}{
 		{
 			name:      "no cards in DB",
-			cardsInDb: []sqlc.Card{},
+			cardsInDb: []sqlc.GetAllCardsRow{},
 			cardsInFile: map[uuid.UUID]source.CardPrinting{
 				uuid.MustParse("7d9e0a23-d2a8-40a6-9076-ed6fb539141b"): {
 					CollectorNumber:  "94",
@@ -270,7 +270,7 @@ func Test_MapToInsertAndUpdate(t *testing.T) {
 		},
 		{
 			name: "all cards in DB",
-			cardsInDb: []sqlc.Card{
+			cardsInDb: []sqlc.GetAllCardsRow{
 				{
 					CollectorNumber: "94",
 					ColorIdentity: pgtype.Text{
@@ -515,7 +515,7 @@ func Test_MapToInsertAndUpdate(t *testing.T) {
 		},
 		{
 			name: "some cards to insert, some to update",
-			cardsInDb: []sqlc.Card{
+			cardsInDb: []sqlc.GetAllCardsRow{
 				{
 					CollectorNumber: "94",
 					ColorIdentity: pgtype.Text{
@@ -844,7 +844,18 @@ func Test_MapToInsertAndUpdate(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			gotInsert, gotUpdate := mapCardsToInsertAndUpdate(test.cardsInFile, test.cardsInDb, now)
+			gotInsert, gotUpdate := mapCardsToInsertAndUpdate(test.cardsInFile, test.cardsInDb, now, 4)
+			if len(gotInsert) != len(test.expectedInsertCards) || len(gotUpdate) != len(test.expectedUpdateCards) {
+				t.Fatalf(
+					"test %s expected %d cards to insert and %d to update, but got %d and %d",
+					test.name,
+					len(test.expectedInsertCards),
+					len(test.expectedUpdateCards),
+					len(gotInsert),
+					len(gotUpdate),
+				)
+			}
+
 			for _, expectedInsert := range test.expectedInsertCards {
 				if !slices.Contains(gotInsert, expectedInsert) {
 					t.Fatalf(
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"FedeAbella/mtgdb/internal/source"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// The quarantine table only holds the rejects of the latest sync, so it is
+// cleared and refilled in a single transaction.
+func (db *DbConf) replaceQuarantinedCards(ctx context.Context, quarantined []source.QuarantinedCard) error {
+	now := time.Now()
+	cardsToInsert := make([]sqlc.InsertQuarantinedCardsParams, 0, len(quarantined))
+	for _, card := range quarantined {
+		cardsToInsert = append(cardsToInsert, card.ToDbInsertQuarantinedCard(now))
+	}
+
+	err := db.Store.WithTx(ctx, func(tx Store) error {
+		if err := tx.DeleteQuarantinedCards(ctx); err != nil {
+			return err
+		}
+
+		if len(cardsToInsert) == 0 {
+			return nil
+		}
+
+		_, err := tx.InsertQuarantinedCards(ctx, cardsToInsert)
+		return err
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	log.Printf("stored %d quarantined cards in db", len(cardsToInsert))
+
+	return nil
+}
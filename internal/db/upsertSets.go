@@ -39,13 +39,13 @@ func mapSetsToInsertAndUpdate(
 	return setsToInsert, setsToUpdate
 }
 
-func (db *DbConf) insertSets(setsToInsert []sqlc.InsertSetsParams) error {
+func (db *DbConf) insertSets(ctx context.Context, setsToInsert []sqlc.InsertSetsParams) error {
 	if len(setsToInsert) == 0 {
 		return nil
 	}
 
 	insertStart := time.Now()
-	if _, err := db.Queries.InsertSets(context.Background(), setsToInsert); err != nil {
+	if _, err := db.Store.InsertSets(ctx, setsToInsert); err != nil {
 		log.Println(err)
 		return err
 
@@ -60,30 +60,23 @@ func (db *DbConf) insertSets(setsToInsert []sqlc.InsertSetsParams) error {
 	return nil
 }
 
-func (db *DbConf) updateSets(setsToUpdate []sqlc.UpdateSetParams) error {
+func (db *DbConf) updateSets(ctx context.Context, setsToUpdate []sqlc.UpdateSetParams) error {
 	if len(setsToUpdate) == 0 {
 		return nil
 	}
 
-	tx, err := db.Conn.Begin(context.Background())
-	if err != nil {
-		log.Println(err)
-		return err
-	}
-	defer tx.Rollback(context.Background())
-
-	txq := db.Queries.WithTx(tx)
 	log.Println("Starting db set update transaction")
 
 	updateStart := time.Now()
-	for _, set := range setsToUpdate {
-		if err := txq.UpdateSet(context.Background(), set); err != nil {
-			log.Println(err)
-			return err
+	err := db.Store.WithTx(ctx, func(tx Store) error {
+		for _, set := range setsToUpdate {
+			if err := tx.UpdateSet(ctx, set); err != nil {
+				return err
+			}
 		}
-	}
-
-	if err = tx.Commit(context.Background()); err != nil {
+		return nil
+	})
+	if err != nil {
 		log.Println(err)
 		return err
 	}
@@ -97,11 +90,11 @@ func (db *DbConf) updateSets(setsToUpdate []sqlc.UpdateSetParams) error {
 	return nil
 }
 
-func (db *DbConf) upsertSets(fileSetMap map[uuid.UUID]source.Set) error {
-	dbSets, err := db.Queries.GetAllSets(context.Background())
+func (db *DbConf) upsertSets(ctx context.Context, fileSetMap map[uuid.UUID]source.Set) (int, int, error) {
+	dbSets, err := db.Store.GetAllSets(ctx)
 	if err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
 	setsToInsert, setsToUpdate := mapSetsToInsertAndUpdate(fileSetMap, dbSets, time.Now())
@@ -109,15 +102,15 @@ func (db *DbConf) upsertSets(fileSetMap map[uuid.UUID]source.Set) error {
 	log.Printf("%d sets to be inserted in db", len(setsToInsert))
 	log.Printf("%d sets to be updated in db", len(setsToUpdate))
 
-	if err = db.insertSets(setsToInsert); err != nil {
+	if err = db.insertSets(ctx, setsToInsert); err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
-	if err = db.updateSets(setsToUpdate); err != nil {
+	if err = db.updateSets(ctx, setsToUpdate); err != nil {
 		log.Println(err)
-		return err
+		return 0, 0, err
 	}
 
-	return nil
+	return len(setsToInsert), len(setsToUpdate), nil
 }
@@ -1,27 +1,110 @@
 package db
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"FedeAbella/mtgdb/internal/source"
+	"FedeAbella/mtgdb/internal/sqlc"
 )
 
-func (db *DbConf) UpsertSetsAndCards() error {
-	setMap, cardMap, err := source.GetScryfallData()
+type SyncSummary struct {
+	SetsInserted     int
+	SetsUpdated      int
+	CardsInserted    int
+	CardsUpdated     int
+	CardsQuarantined int
+	PricesRecorded   int
+}
+
+func (s SyncSummary) String() string {
+	return fmt.Sprintf(
+		"sets: %d inserted, %d updated; cards: %d inserted, %d updated, %d quarantined; prices: %d recorded",
+		s.SetsInserted,
+		s.SetsUpdated,
+		s.CardsInserted,
+		s.CardsUpdated,
+		s.CardsQuarantined,
+		s.PricesRecorded,
+	)
+}
+
+func (db *DbConf) UpsertSetsAndCards(ctx context.Context, path string) (SyncSummary, error) {
+	summary := SyncSummary{}
+
+	if err := db.acquireSyncLock(ctx); err != nil {
+		log.Println(err)
+		return summary, err
+	}
+	defer db.releaseSyncLock()
+	startedAt := time.Now().UTC()
+
+	setMap, cardMap, quarantined, err := source.GetScryfallData(ctx, path, db.Workers, db.StrictSchema)
 	if err != nil {
 		log.Println(err)
-		return err
+		return summary, err
 	}
 
-	if err = db.upsertSets(setMap); err != nil {
+	if summary.SetsInserted, summary.SetsUpdated, err = db.upsertSets(ctx, setMap); err != nil {
 		log.Println(err)
-		return err
+		return summary, err
+	}
+
+	if summary.CardsInserted, summary.CardsUpdated, err = db.upsertCards(ctx, cardMap); err != nil {
+		log.Println(err)
+		return summary, err
 	}
 
-	if err = db.upsertCards(cardMap); err != nil {
+	if err = db.replaceQuarantinedCards(ctx, quarantined); err != nil {
 		log.Println(err)
+		return summary, err
+	}
+	summary.CardsQuarantined = len(quarantined)
+
+	// Collections keep a daily price history of the printings they hold, for
+	// valuations to compare against
+	if store, ok := db.Store.(CollectionStore); ok {
+		today := pgtype.Date{Time: time.Now().UTC().Truncate(24 * time.Hour), Valid: true}
+		recorded, err := store.RecordCollectionPrices(ctx, today)
+		if err != nil {
+			log.Println(err)
+			return summary, err
+		}
+		summary.PricesRecorded = int(recorded)
+	}
+
+	if err = db.recordSyncRun(ctx, startedAt, summary); err != nil {
+		log.Println(err)
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+func (db *DbConf) recordSyncRun(ctx context.Context, startedAt time.Time, summary SyncSummary) error {
+	store, ok := db.Store.(SyncRunStore)
+	if !ok {
+		return nil
+	}
+
+	run, err := store.InsertSyncRun(ctx, sqlc.InsertSyncRunParams{
+		StartedAt:        pgtype.Timestamp{Time: startedAt, Valid: true},
+		FinishedAt:       pgtype.Timestamp{Time: time.Now().UTC(), Valid: true},
+		SetsInserted:     int32(summary.SetsInserted),
+		SetsUpdated:      int32(summary.SetsUpdated),
+		CardsInserted:    int32(summary.CardsInserted),
+		CardsUpdated:     int32(summary.CardsUpdated),
+		CardsQuarantined: int32(summary.CardsQuarantined),
+		PricesRecorded:   int32(summary.PricesRecorded),
+	})
+	if err != nil {
 		return err
 	}
 
+	log.Printf("Recorded sync run %d", run.ID)
 	return nil
 }
@@ -0,0 +1,223 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"FedeAbella/mtgdb/internal/migrations"
+)
+
+const (
+	syncFixtureSet    = `"set_id": "20000000-0000-4000-8000-000000000001", "set": "s1", "set_name": "Set 1"`
+	syncFixtureCardA  = `{"id": "00000000-0000-4000-8000-000000000001", "uri": "https://api.scryfall.com/cards/1", "scryfall_uri": "https://scryfall.com/card/1", "oracle_id": "10000000-0000-4000-8000-000000000001", "name": "Card A", "lang": "en", "games": ["paper"], ` + syncFixtureSet + `, "collector_number": "1", "rarity": "common"}`
+	syncFixtureCardB  = `{"id": "00000000-0000-4000-8000-000000000002", "uri": "https://api.scryfall.com/cards/2", "scryfall_uri": "https://scryfall.com/card/2", "oracle_id": "10000000-0000-4000-8000-000000000002", "name": "Card B", "printed_name": "Carta B", "lang": "es", "games": ["paper"], ` + syncFixtureSet + `, "collector_number": "2", "rarity": "rare"}`
+	syncFixtureCardC  = `{"id": "00000000-0000-4000-8000-000000000003", "uri": "https://api.scryfall.com/cards/3", "scryfall_uri": "https://scryfall.com/card/3", "oracle_id": "10000000-0000-4000-8000-000000000003", "name": "Card C", "lang": "en", "games": ["paper"], ` + syncFixtureSet + `, "collector_number": "3", "rarity": "mythic"}`
+	syncFixtureBad    = `{"id": "00000000-0000-4000-8000-000000000004", "uri": "https://api.scryfall.com/cards/4", "scryfall_uri": "https://scryfall.com/card/4", "oracle_id": "10000000-0000-4000-8000-000000000004", "name": "Bad Card", "lang": "en", "games": ["paper"], ` + syncFixtureSet + `, "rarity": "ultra"}`
+	syncFixtureCardA2 = `{"id": "00000000-0000-4000-8000-000000000001", "uri": "https://api.scryfall.com/cards/1", "scryfall_uri": "https://scryfall.com/card/1", "oracle_id": "10000000-0000-4000-8000-000000000001", "name": "Card A", "lang": "en", "games": ["paper"], ` + syncFixtureSet + `, "collector_number": "1a", "rarity": "common"}`
+)
+
+func writeSyncFixture(t *testing.T, cards ...string) string {
+	t.Helper()
+
+	content := "["
+	for i, card := range cards {
+		if i > 0 {
+			content += ",\n"
+		}
+		content += card
+	}
+	content += "]"
+
+	path := filepath.Join(t.TempDir(), "all-cards.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing fixture failed with error %v", err)
+	}
+
+	return path
+}
+
+func newSqliteTestStore(t *testing.T) *SqliteStore {
+	t.Helper()
+	ctx := context.Background()
+	dbURL := "sqlite://" + filepath.Join(t.TempDir(), "cards.db")
+
+	sqlDb, driver, err := OpenSQL(dbURL)
+	if err != nil {
+		t.Fatalf("opening db failed with error %v", err)
+	}
+	defer sqlDb.Close()
+
+	migrator, err := migrations.New(sqlDb, driver)
+	if err != nil {
+		t.Fatalf("creating migrator failed with error %v", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating failed with error %v", err)
+	}
+
+	store, closeStore, err := Open(ctx, dbURL, PoolConf{})
+	if err != nil {
+		t.Fatalf("opening store failed with error %v", err)
+	}
+	t.Cleanup(closeStore)
+
+	return store.(*SqliteStore)
+}
+
+func countQuarantinedCards(t *testing.T, store Store) int {
+	t.Helper()
+
+	switch s := store.(type) {
+	case *MemoryStore:
+		return len(s.GetQuarantinedCards())
+	case *SqliteStore:
+		var count int
+		if err := s.db.QueryRow("SELECT count(*) FROM quarantined_cards").Scan(&count); err != nil {
+			t.Fatalf("counting quarantined cards failed with error %v", err)
+		}
+		return count
+	}
+
+	t.Fatalf("unexpected store %T", store)
+	return 0
+}
+
+func Test_UpsertSetsAndCards(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		cards           []string
+		expectedSummary SyncSummary
+		expectedCards   map[string]string
+	}{
+		{
+			name:  "first sync inserts everything",
+			cards: []string{syncFixtureCardA, syncFixtureCardB, syncFixtureBad},
+			expectedSummary: SyncSummary{
+				SetsInserted:     1,
+				CardsInserted:    2,
+				CardsQuarantined: 1,
+			},
+			expectedCards: map[string]string{"Card A": "1", "Card B": "2"},
+		},
+		{
+			name:            "same file is a no-op",
+			cards:           []string{syncFixtureCardA, syncFixtureCardB},
+			expectedSummary: SyncSummary{},
+			expectedCards:   map[string]string{"Card A": "1", "Card B": "2"},
+		},
+		{
+			name:  "changed and new cards",
+			cards: []string{syncFixtureCardA2, syncFixtureCardB, syncFixtureCardC},
+			expectedSummary: SyncSummary{
+				CardsInserted: 1,
+				CardsUpdated:  1,
+			},
+			expectedCards: map[string]string{"Card A": "1a", "Card B": "2", "Card C": "3"},
+		},
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": newSqliteTestStore(t),
+	}
+
+	for backend, store := range stores {
+		dbConf := DbConf{Store: store, Workers: 2, Writers: 2}
+
+		// Cases run in order against the same store, each sync building on
+		// the previous one
+		for _, test := range tests {
+			t.Run(backend+"/"+test.name, func(t *testing.T) {
+				summary, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, test.cards...))
+				if err != nil {
+					t.Fatalf("sync failed with error %v", err)
+				}
+
+				if summary != test.expectedSummary {
+					t.Fatalf("expected summary %v but got %v", test.expectedSummary, summary)
+				}
+
+				cards, err := store.GetAllCards(ctx)
+				if err != nil {
+					t.Fatalf("getting cards failed with error %v", err)
+				}
+				if len(cards) != len(test.expectedCards) {
+					t.Fatalf("expected %d cards in store but got %d", len(test.expectedCards), len(cards))
+				}
+				for _, card := range cards {
+					if test.expectedCards[card.Name] != card.CollectorNumber {
+						t.Fatalf(
+							"expected card %s to have collector number %q but got %q",
+							card.Name,
+							test.expectedCards[card.Name],
+							card.CollectorNumber,
+						)
+					}
+				}
+
+				if quarantined := countQuarantinedCards(t, store); quarantined != test.expectedSummary.CardsQuarantined {
+					t.Fatalf(
+						"expected %d quarantined cards but got %d",
+						test.expectedSummary.CardsQuarantined,
+						quarantined,
+					)
+				}
+
+				if runs, ok := store.(SyncRunStore); ok {
+					run, err := runs.GetLastSyncRun(ctx)
+					if err != nil {
+						t.Fatalf("getting last sync run failed with error %v", err)
+					}
+					recorded := SyncSummary{
+						SetsInserted:     int(run.SetsInserted),
+						SetsUpdated:      int(run.SetsUpdated),
+						CardsInserted:    int(run.CardsInserted),
+						CardsUpdated:     int(run.CardsUpdated),
+						CardsQuarantined: int(run.CardsQuarantined),
+						PricesRecorded:   int(run.PricesRecorded),
+					}
+					if recorded != summary || run.FinishedAt.Time.Before(run.StartedAt.Time) {
+						t.Fatalf("expected sync run recording %v but got %+v", summary, run)
+					}
+				}
+			})
+		}
+	}
+}
+
+func Test_UpsertSetsAndCardsSyncInProgress(t *testing.T) {
+	for backend, store := range map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": newSqliteTestStore(t),
+	} {
+		t.Run(backend, func(t *testing.T) {
+			testSyncInProgress(t, store)
+		})
+	}
+}
+
+func testSyncInProgress(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if err := store.AcquireSyncLock(ctx, false); err != nil {
+		t.Fatalf("acquiring lock failed with error %v", err)
+	}
+
+	dbConf := DbConf{Store: store, Workers: 1}
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA)); !errors.Is(err, ErrSyncInProgress) {
+		t.Fatalf("expected ErrSyncInProgress but got %v", err)
+	}
+
+	if err := store.ReleaseSyncLock(ctx); err != nil {
+		t.Fatalf("releasing lock failed with error %v", err)
+	}
+
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, syncFixtureCardA)); err != nil {
+		t.Fatalf("sync after releasing lock failed with error %v", err)
+	}
+}
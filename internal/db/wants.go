@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+	"FedeAbella/mtgdb/internal/trade"
+	"FedeAbella/mtgdb/internal/valuation"
+)
+
+// NewWant is a card wanted, by printing if ScryfallID is valid and by oracle
+// id otherwise, in any finish unless Finish is valid.
+type NewWant struct {
+	Owner            string
+	Quantity         int32
+	ScryfallOracleID pgtype.UUID
+	ScryfallID       pgtype.UUID
+	Finish           pgtype.Text
+}
+
+func (want NewWant) validate() error {
+	if want.Owner == "" {
+		return &InvalidItemError{Field: "owner", Value: want.Owner}
+	}
+	if want.Quantity <= 0 {
+		return &InvalidItemError{Field: "quantity", Value: fmt.Sprint(want.Quantity)}
+	}
+	if want.Finish.Valid && !slices.Contains(Finishes, want.Finish.String) {
+		return &InvalidItemError{Field: "finish", Value: want.Finish.String, Allowed: Finishes}
+	}
+
+	return nil
+}
+
+// WantEntry is a want along with its printing, or the first printing of the
+// card if any printing will do.
+type WantEntry struct {
+	Want sqlc.Want
+	Card CardWithSet
+}
+
+// Wants keeps the want lists of collection owners, matched against each
+// other's collections to find trades.
+type Wants struct {
+	Store WantStore
+}
+
+// Add adds the card to the owner's want list, adding to the quantity of a
+// want for the same card, printing and finish if there is one.
+func (w *Wants) Add(ctx context.Context, want NewWant) (sqlc.Want, error) {
+	if err := want.validate(); err != nil {
+		return sqlc.Want{}, err
+	}
+
+	var added sqlc.Want
+	err := w.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(WantStore)
+
+		oracleID, err := resolveOracleID(ctx, store, want.ScryfallID, want.ScryfallOracleID)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		wants, err := store.GetWants(ctx, want.Owner)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		timestamp := timestampNow()
+		for _, existing := range wants {
+			if existing.ScryfallOracleID == oracleID &&
+				existing.ScryfallID == want.ScryfallID &&
+				existing.Finish == want.Finish {
+				existing.Quantity += want.Quantity
+				existing.UpdatedAt = timestamp
+				added = existing
+
+				return store.UpdateWant(ctx, sqlc.UpdateWantParams{
+					ID:        existing.ID,
+					Quantity:  existing.Quantity,
+					UpdatedAt: timestamp,
+				})
+			}
+		}
+
+		added, err = store.InsertWant(ctx, sqlc.InsertWantParams{
+			Owner:            want.Owner,
+			Quantity:         want.Quantity,
+			ScryfallOracleID: oracleID,
+			ScryfallID:       want.ScryfallID,
+			Finish:           want.Finish,
+			CreatedAt:        timestamp,
+			UpdatedAt:        timestamp,
+		})
+		return err
+	})
+
+	return added, err
+}
+
+// Remove takes quantity copies out of one of the owner's wants, deleting it
+// if none are left. A quantity of 0 removes them all.
+func (w *Wants) Remove(ctx context.Context, owner string, wantID int64, quantity int32) error {
+	return w.Store.WithTx(ctx, func(tx Store) error {
+		store := tx.(WantStore)
+
+		wants, err := store.GetWants(ctx, owner)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		i := slices.IndexFunc(wants, func(want sqlc.Want) bool { return want.ID == wantID })
+		if i < 0 {
+			return fmt.Errorf("want %d of %s %w", wantID, owner, ErrNotFound)
+		}
+
+		if quantity <= 0 || quantity >= wants[i].Quantity {
+			return store.DeleteWant(ctx, wantID)
+		}
+
+		return store.UpdateWant(ctx, sqlc.UpdateWantParams{
+			ID:        wantID,
+			Quantity:  wants[i].Quantity - quantity,
+			UpdatedAt: timestampNow(),
+		})
+	})
+}
+
+func (w *Wants) List(ctx context.Context, owner string) ([]WantEntry, error) {
+	wants, err := w.Store.GetWants(ctx, owner)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	entries := make([]WantEntry, 0, len(wants))
+	for _, want := range wants {
+		printing, err := printingOf(ctx, w.Store, want.ScryfallID, want.ScryfallOracleID)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		entries = append(entries, WantEntry{Want: want, Card: printing})
+	}
+
+	return entries, nil
+}
+
+// Match finds what owner can give other from the collections owner owns to
+// fill other's wants, and what other can give back, valued in currency. If
+// balance, the sides are trimmed as trade.Balance does.
+func (w *Wants) Match(
+	ctx context.Context,
+	owner string,
+	other string,
+	currency string,
+	balance bool,
+) (trade.Side, trade.Side, error) {
+	if !slices.Contains(Currencies, currency) {
+		return trade.Side{}, trade.Side{}, &InvalidItemError{Field: "currency", Value: currency, Allowed: Currencies}
+	}
+
+	gives, err := w.offers(ctx, owner, other, currency)
+	if err != nil {
+		return trade.Side{}, trade.Side{}, err
+	}
+	gets, err := w.offers(ctx, other, owner, currency)
+	if err != nil {
+		return trade.Side{}, trade.Side{}, err
+	}
+
+	if balance {
+		gives, gets = trade.Balance(gives, gets)
+	}
+
+	return gives, gets, nil
+}
+
+// offers is what from can give to fill the wants of to.
+func (w *Wants) offers(ctx context.Context, from string, to string, currency string) (trade.Side, error) {
+	stored, err := w.Store.GetWants(ctx, to)
+	if err != nil {
+		log.Println(err)
+		return trade.Side{}, err
+	}
+
+	wants := make([]trade.Want, 0, len(stored))
+	for _, want := range stored {
+		wants = append(wants, trade.Want{
+			ID:         want.ID,
+			OracleID:   uuid.UUID(want.ScryfallOracleID.Bytes),
+			ScryfallID: uuid.UUID(want.ScryfallID.Bytes),
+			Finish:     want.Finish.String,
+			Quantity:   int(want.Quantity),
+		})
+	}
+
+	rows, err := w.Store.GetOwnerCollectionItems(ctx, from)
+	if err != nil {
+		log.Println(err)
+		return trade.Side{}, err
+	}
+
+	items := make([]trade.Item, 0, len(rows))
+	for _, row := range rows {
+		prices, err := valuation.ParsePrices(row.Card.Raw)
+		if err != nil {
+			log.Println(err)
+			return trade.Side{}, err
+		}
+
+		price, eur := prices.For(row.CollectionItem.Finish)
+		if currency == "EUR" {
+			price = eur
+		}
+
+		items = append(items, trade.Item{
+			ID:              row.CollectionItem.ID,
+			OracleID:        uuid.UUID(row.Card.ScryfallOracleID.Bytes),
+			ScryfallID:      uuid.UUID(row.Card.ScryfallID.Bytes),
+			Name:            row.Card.Name,
+			SetCode:         row.SetCode,
+			CollectorNumber: row.Card.CollectorNumber,
+			LanguageCode:    row.CollectionItem.LanguageCode,
+			Finish:          row.CollectionItem.Finish,
+			Condition:       row.CollectionItem.Condition,
+			Quantity:        int(row.CollectionItem.Quantity),
+			PriceCents:      price,
+		})
+	}
+
+	return trade.NewSide(from, to, trade.Match(wants, items)), nil
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func Test_Wants(t *testing.T) {
+	ctx := context.Background()
+	store := newSqliteTestStore(t)
+	dbConf := DbConf{Store: store, Workers: 1}
+	collections := &Collections{Store: store}
+	wants := &Wants{Store: store}
+
+	cardA := strings.Replace(syncFixtureCardA, `"rarity"`, `"prices": {"usd": "2.00"}, "rarity"`, 1)
+	cardB := strings.Replace(syncFixtureCardB, `"rarity"`, `"prices": {"usd": "5.00"}, "rarity"`, 1)
+	if _, err := dbConf.UpsertSetsAndCards(ctx, writeSyncFixture(t, cardA, cardB)); err != nil {
+		t.Fatalf("sync failed with error %v", err)
+	}
+
+	for name, owner := range map[string]string{"alice binder": "alice", "bob binder": "bob"} {
+		if _, err := collections.Create(ctx, name); err != nil {
+			t.Fatalf("creating %s failed with error %v", name, err)
+		}
+		if err := collections.SetOwner(ctx, name, owner); err != nil {
+			t.Fatalf("setting the owner of %s failed with error %v", name, err)
+		}
+	}
+	if err := collections.SetOwner(ctx, "carol binder", "carol"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown collection but got %v", err)
+	}
+
+	items := map[string]NewCollectionItem{
+		"alice binder": {ScryfallID: syncFixtureCardAID, Quantity: 4, Finish: "nonfoil", Condition: "NM"},
+		"bob binder":   {ScryfallID: syncFixtureCardBID, Quantity: 1, Finish: "nonfoil", Condition: "LP"},
+	}
+	for name, item := range items {
+		if _, err := collections.Add(ctx, name, item); err != nil {
+			t.Fatalf("adding to %s failed with error %v", name, err)
+		}
+	}
+
+	oracleA := pgtype.UUID{Bytes: uuid.MustParse("10000000-0000-4000-8000-000000000001"), Valid: true}
+	for _, want := range []NewWant{
+		{Owner: "bob", Quantity: 2, ScryfallOracleID: oracleA},
+		{Owner: "bob", Quantity: 1, ScryfallOracleID: oracleA},
+		{Owner: "alice", Quantity: 1, ScryfallID: syncFixtureCardBID, Finish: pgtype.Text{String: "nonfoil", Valid: true}},
+	} {
+		if _, err := wants.Add(ctx, want); err != nil {
+			t.Fatalf("adding want %+v failed with error %v", want, err)
+		}
+	}
+
+	invalid := []NewWant{
+		{Quantity: 1, ScryfallOracleID: oracleA},
+		{Owner: "bob", Quantity: 0, ScryfallOracleID: oracleA},
+		{Owner: "bob", Quantity: 1, ScryfallOracleID: oracleA, Finish: pgtype.Text{String: "shiny", Valid: true}},
+	}
+	for _, want := range invalid {
+		var invalidErr *InvalidItemError
+		if _, err := wants.Add(ctx, want); !errors.As(err, &invalidErr) {
+			t.Fatalf("expected InvalidItemError for %+v but got %v", want, err)
+		}
+	}
+	unknown := NewWant{Owner: "bob", Quantity: 1, ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}
+	if _, err := wants.Add(ctx, unknown); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown card but got %v", err)
+	}
+
+	bobWants, err := wants.List(ctx, "bob")
+	if err != nil {
+		t.Fatalf("listing wants failed with error %v", err)
+	}
+	if len(bobWants) != 1 || bobWants[0].Want.Quantity != 3 || bobWants[0].Card.Card.Name != "Card A" {
+		t.Fatalf("expected bob's wants merged into 3 Card A, got %+v", bobWants)
+	}
+
+	gives, gets, err := wants.Match(ctx, "alice", "bob", "USD", false)
+	if err != nil {
+		t.Fatalf("matching failed with error %v", err)
+	}
+	if gives.Copies != 3 || gives.Value != 600 || gets.Copies != 1 || gets.Value != 500 {
+		t.Fatalf("expected alice to give 3 copies for 6.00 and get 1 for 5.00, got %+v and %+v", gives, gets)
+	}
+
+	gives, _, err = wants.Match(ctx, "alice", "bob", "USD", true)
+	if err != nil {
+		t.Fatalf("matching failed with error %v", err)
+	}
+	if gives.Copies != 2 || gives.Value != 400 {
+		t.Fatalf("expected the balanced trade to give 2 copies for 4.00, got %+v", gives)
+	}
+
+	if _, _, err := wants.Match(ctx, "alice", "bob", "GBP", false); err == nil {
+		t.Fatalf("expected an error for an unsupported currency")
+	}
+
+	if err := wants.Remove(ctx, "bob", bobWants[0].Want.ID, 1); err != nil {
+		t.Fatalf("removing want failed with error %v", err)
+	}
+	if err := wants.Remove(ctx, "alice", bobWants[0].Want.ID, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound removing another owner's want but got %v", err)
+	}
+	if err := wants.Remove(ctx, "bob", bobWants[0].Want.ID, 0); err != nil {
+		t.Fatalf("removing want failed with error %v", err)
+	}
+	if bobWants, _ := wants.List(ctx, "bob"); len(bobWants) != 0 {
+		t.Fatalf("expected bob to want nothing, got %+v", bobWants)
+	}
+}
@@ -0,0 +1,101 @@
+// Package dbtest sets up migrated stores and seeds them, for the tests of the
+// packages built on internal/db.
+package dbtest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/migrations"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Now is when the seeded sets and cards are created and updated.
+var Now = pgtype.Timestamp{Time: time.Date(2025, 9, 5, 21, 36, 0, 0, time.UTC), Valid: true}
+
+// NewSqliteURL migrates a new SQLite db in the test's temp dir, returning its
+// db url.
+func NewSqliteURL(t testing.TB) string {
+	t.Helper()
+	dbURL := "sqlite://" + filepath.Join(t.TempDir(), "cards.db")
+
+	sqlDb, driver, err := db.OpenSQL(dbURL)
+	if err != nil {
+		t.Fatalf("opening db failed with error %v", err)
+	}
+	defer sqlDb.Close()
+
+	migrator, err := migrations.New(sqlDb, driver)
+	if err != nil {
+		t.Fatalf("creating migrator failed with error %v", err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrating failed with error %v", err)
+	}
+
+	return dbURL
+}
+
+// NewSqliteStore opens a store on a new migrated SQLite db, closed when the
+// test ends.
+func NewSqliteStore(t testing.TB) db.Store {
+	t.Helper()
+
+	store, closeStore, err := db.Open(context.Background(), NewSqliteURL(t), db.PoolConf{})
+	if err != nil {
+		t.Fatalf("opening store failed with error %v", err)
+	}
+	t.Cleanup(closeStore)
+
+	return store
+}
+
+func NewSet(code string, name string) sqlc.InsertSetsParams {
+	return sqlc.InsertSetsParams{
+		ScryfallID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Code:       code,
+		Name:       name,
+		CreatedAt:  Now,
+		UpdatedAt:  Now,
+	}
+}
+
+// NewCard returns an English printing with new ids, for tests to set the
+// fields they care about on.
+func NewCard(name string, collectorNumber string) sqlc.InsertCardsParams {
+	return sqlc.InsertCardsParams{
+		ScryfallID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Name:             name,
+		CollectorNumber:  collectorNumber,
+		LanguageCode:     "en",
+		ScryfallApiUri:   "api/" + collectorNumber,
+		ScryfallWebUri:   "web/" + collectorNumber,
+		ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		CreatedAt:        Now,
+		UpdatedAt:        Now,
+		Raw:              "{}",
+	}
+}
+
+// Seed stores the set and the cards in it.
+func Seed(t testing.TB, store db.Store, set sqlc.InsertSetsParams, cards ...sqlc.InsertCardsParams) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := store.InsertSets(ctx, []sqlc.InsertSetsParams{set}); err != nil {
+		t.Fatalf("inserting sets failed with error %v", err)
+	}
+
+	for i := range cards {
+		cards[i].SetID = set.ScryfallID
+	}
+	if _, err := store.InsertCards(ctx, cards); err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+}
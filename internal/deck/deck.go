@@ -0,0 +1,120 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Zone = string
+
+const (
+	ZoneMain       Zone = "main"
+	ZoneSideboard  Zone = "sideboard"
+	ZoneCommander  Zone = "commander"
+	ZoneCompanion  Zone = "companion"
+	ZoneMaybeboard Zone = "maybeboard"
+)
+
+// Zones lists the zones in the order decklists print them.
+var Zones = []Zone{ZoneCommander, ZoneCompanion, ZoneMain, ZoneSideboard, ZoneMaybeboard}
+
+// rules are the deck building rules of a format, keyed as in Scryfall's
+// legalities.
+type rules struct {
+	// deckSize is the minimum size of the main deck, or its exact size
+	// counting the commanders in commander formats
+	deckSize     int
+	maxSideboard int
+	maxCopies    int
+	commander    bool
+}
+
+var formats = map[string]rules{
+	"standard":  {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"pioneer":   {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"modern":    {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"legacy":    {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"vintage":   {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"pauper":    {deckSize: 60, maxSideboard: 15, maxCopies: 4},
+	"commander": {deckSize: 100, maxCopies: 1, commander: true},
+	"duel":      {deckSize: 100, maxCopies: 1, commander: true},
+}
+
+func Formats() []string {
+	return slices.Sorted(maps.Keys(formats))
+}
+
+type UnknownFormatError struct {
+	Format string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("unknown format %q, expected one of %s", e.Format, strings.Join(Formats(), ", "))
+}
+
+// Card is a deck entry along with the card fields the rules look at.
+type Card struct {
+	Zone          Zone
+	Quantity      int
+	Name          string
+	TypeLine      string
+	ColorIdentity string
+	// Raw is the Scryfall card object, read for its legalities and oracle
+	// text.
+	Raw string
+}
+
+type scryfallRules struct {
+	Legalities map[string]string `json:"legalities"`
+	OracleText string            `json:"oracle_text"`
+	Faces      []struct {
+		OracleText string `json:"oracle_text"`
+	} `json:"card_faces"`
+}
+
+func (c Card) scryfallRules() scryfallRules {
+	var parsed scryfallRules
+	// A card without a readable raw object has no legalities, so it's
+	// reported as not legal rather than failing the whole validation
+	_ = json.Unmarshal([]byte(c.Raw), &parsed)
+
+	return parsed
+}
+
+func (r scryfallRules) oracleText() string {
+	texts := []string{r.OracleText}
+	for _, face := range r.Faces {
+		texts = append(texts, face.OracleText)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+var copiesAllowedRegexp = regexp.MustCompile(`A deck can have (any number of|up to (\w+)) cards named`)
+
+var numberWords = map[string]int{"seven": 7, "nine": 9}
+
+// copiesAllowed reads the exceptions to the copies limit, as in Relentless
+// Rats or Seven Dwarves. It returns false if the card has none.
+func copiesAllowed(oracleText string) (int, bool) {
+	match := copiesAllowedRegexp.FindStringSubmatch(oracleText)
+	switch {
+	case match == nil:
+		return 0, false
+	case match[2] == "":
+		return math.MaxInt, true
+	}
+
+	if n, ok := numberWords[strings.ToLower(match[2])]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(match[2])
+
+	return n, err == nil
+}
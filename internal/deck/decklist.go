@@ -0,0 +1,122 @@
+package deck
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FinishNonfoil = "nonfoil"
+	FinishFoil    = "foil"
+	FinishEtched  = "etched"
+)
+
+// Line is a card line of a text decklist.
+type Line struct {
+	// Number is the 1-based line number in the decklist
+	Number   int
+	Text     string
+	Zone     Zone
+	Quantity int
+	Name     string
+	// SetCode and CollectorNumber are empty if the line doesn't name a
+	// printing, and CollectorNumber may be empty even if SetCode isn't
+	SetCode         string
+	CollectorNumber string
+	Finish          string
+}
+
+// Decklist is a parsed text decklist. Invalid holds the lines that are neither
+// cards, section headers nor comments.
+type Decklist struct {
+	Lines   []Line
+	Invalid []Line
+}
+
+// "4 Lightning Bolt", "4x Lightning Bolt (M10) 146 *F*" and MTGO's
+// "SB: 2 Duress"
+var linePattern = regexp.MustCompile(
+	`^(SB:\s*)?(\d+)x?\s+(.+?)(?:\s+[(\[]([A-Za-z0-9]{2,6})[)\]](?:\s+([^\s*]+))?)?(?:\s+\*([FE])\*)?$`,
+)
+
+var sectionZones = map[string]Zone{
+	"deck":        ZoneMain,
+	"main":        ZoneMain,
+	"mainboard":   ZoneMain,
+	"main deck":   ZoneMain,
+	"sideboard":   ZoneSideboard,
+	"commander":   ZoneCommander,
+	"commanders":  ZoneCommander,
+	"companion":   ZoneCompanion,
+	"maybeboard":  ZoneMaybeboard,
+	"considering": ZoneMaybeboard,
+}
+
+var finishMarkers = map[string]string{
+	"":  FinishNonfoil,
+	"F": FinishFoil,
+	"E": FinishEtched,
+}
+
+// ParseDecklist reads a decklist as exported by Arena, MTGO or Moxfield. Cards
+// go to the main deck until a section header such as "Sideboard" names
+// another zone. Lists without headers put the cards after the first blank line
+// in the sideboard, as MTGO does.
+func ParseDecklist(r io.Reader) (Decklist, error) {
+	var decklist Decklist
+
+	zone := ZoneMain
+	headers := false
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "":
+			if !headers && zone == ZoneMain && len(decklist.Lines) > 0 {
+				zone = ZoneSideboard
+			}
+			continue
+		case strings.HasPrefix(text, "//") || strings.HasPrefix(text, "#"):
+			continue
+		}
+
+		header := strings.ToLower(strings.TrimSuffix(text, ":"))
+		if section, ok := sectionZones[header]; ok {
+			zone = section
+			headers = true
+			continue
+		}
+		// Arena names the deck in an "About" section
+		if header == "about" || strings.HasPrefix(header, "name ") {
+			continue
+		}
+
+		line := Line{Number: number, Text: text, Zone: zone}
+		match := linePattern.FindStringSubmatch(text)
+		if match == nil {
+			decklist.Invalid = append(decklist.Invalid, line)
+			continue
+		}
+
+		line.Quantity, _ = strconv.Atoi(match[2])
+		if line.Quantity <= 0 {
+			decklist.Invalid = append(decklist.Invalid, line)
+			continue
+		}
+		if match[1] != "" {
+			line.Zone = ZoneSideboard
+		}
+		line.Name = match[3]
+		line.SetCode = strings.ToLower(match[4])
+		line.CollectorNumber = match[5]
+		line.Finish = finishMarkers[match[6]]
+
+		decklist.Lines = append(decklist.Lines, line)
+	}
+
+	return decklist, scanner.Err()
+}
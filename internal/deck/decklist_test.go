@@ -0,0 +1,87 @@
+package deck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_ParseDecklist(t *testing.T) {
+	tests := []struct {
+		name            string
+		decklist        string
+		expectedLines   []Line
+		expectedInvalid []Line
+	}{
+		{
+			name: "arena export with sections",
+			decklist: `About
+Name Burn
+
+Commander
+1 Zurgo Bellstriker (DTK) 169
+
+Deck
+4 Lightning Bolt (M10) 146
+2 Delver of Secrets (ISD) 51 *F*
+
+Sideboard
+3 Smash to Smithereens (ORI) 163`,
+			expectedLines: []Line{
+				{Number: 5, Text: "1 Zurgo Bellstriker (DTK) 169", Zone: ZoneCommander, Quantity: 1, Name: "Zurgo Bellstriker", SetCode: "dtk", CollectorNumber: "169", Finish: FinishNonfoil},
+				{Number: 8, Text: "4 Lightning Bolt (M10) 146", Zone: ZoneMain, Quantity: 4, Name: "Lightning Bolt", SetCode: "m10", CollectorNumber: "146", Finish: FinishNonfoil},
+				{Number: 9, Text: "2 Delver of Secrets (ISD) 51 *F*", Zone: ZoneMain, Quantity: 2, Name: "Delver of Secrets", SetCode: "isd", CollectorNumber: "51", Finish: FinishFoil},
+				{Number: 12, Text: "3 Smash to Smithereens (ORI) 163", Zone: ZoneSideboard, Quantity: 3, Name: "Smash to Smithereens", SetCode: "ori", CollectorNumber: "163", Finish: FinishNonfoil},
+			},
+		},
+		{
+			name: "mtgo list split by a blank line",
+			decklist: `4 Lightning Bolt
+4x Fire // Ice
+
+2 Duress`,
+			expectedLines: []Line{
+				{Number: 1, Text: "4 Lightning Bolt", Zone: ZoneMain, Quantity: 4, Name: "Lightning Bolt", Finish: FinishNonfoil},
+				{Number: 2, Text: "4x Fire // Ice", Zone: ZoneMain, Quantity: 4, Name: "Fire // Ice", Finish: FinishNonfoil},
+				{Number: 4, Text: "2 Duress", Zone: ZoneSideboard, Quantity: 2, Name: "Duress", Finish: FinishNonfoil},
+			},
+		},
+		{
+			name: "moxfield markers, comments and sideboard prefix",
+			decklist: `// Burn
+1 Sol Ring (C21) 263 *E*
+1 Lightning Bolt (M10)
+SB: 2 Relámpago
+SIDEBOARD:
+1 Pyroblast
+Lightning Bolt
+0 Shock`,
+			expectedLines: []Line{
+				{Number: 2, Text: "1 Sol Ring (C21) 263 *E*", Zone: ZoneMain, Quantity: 1, Name: "Sol Ring", SetCode: "c21", CollectorNumber: "263", Finish: FinishEtched},
+				{Number: 3, Text: "1 Lightning Bolt (M10)", Zone: ZoneMain, Quantity: 1, Name: "Lightning Bolt", SetCode: "m10", Finish: FinishNonfoil},
+				{Number: 4, Text: "SB: 2 Relámpago", Zone: ZoneSideboard, Quantity: 2, Name: "Relámpago", Finish: FinishNonfoil},
+				{Number: 6, Text: "1 Pyroblast", Zone: ZoneSideboard, Quantity: 1, Name: "Pyroblast", Finish: FinishNonfoil},
+			},
+			expectedInvalid: []Line{
+				{Number: 7, Text: "Lightning Bolt", Zone: ZoneSideboard},
+				{Number: 8, Text: "0 Shock", Zone: ZoneSideboard},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decklist, err := ParseDecklist(strings.NewReader(test.decklist))
+			if err != nil {
+				t.Fatalf("parsing failed with error %v", err)
+			}
+
+			if !reflect.DeepEqual(decklist.Lines, test.expectedLines) {
+				t.Fatalf("expected lines %+v but got %+v", test.expectedLines, decklist.Lines)
+			}
+			if !reflect.DeepEqual(decklist.Invalid, test.expectedInvalid) {
+				t.Fatalf("expected invalid lines %+v but got %+v", test.expectedInvalid, decklist.Invalid)
+			}
+		})
+	}
+}
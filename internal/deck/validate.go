@@ -0,0 +1,211 @@
+package deck
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Violation struct {
+	// Card is empty for violations of the deck as a whole
+	Card string
+	Msg  string
+}
+
+func (v Violation) String() string {
+	if v.Card == "" {
+		return v.Msg
+	}
+
+	return v.Card + ": " + v.Msg
+}
+
+type validation struct {
+	format     string
+	rules      rules
+	violations []Violation
+}
+
+func (v *validation) deckf(format string, args ...any) {
+	v.violations = append(v.violations, Violation{Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validation) cardf(card string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Card: card, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks cards against the deck building rules of format, returning
+// nothing if the deck is legal. The maybeboard is ignored, and so are the deck
+// building conditions of companions.
+func Validate(format string, cards []Card) ([]Violation, error) {
+	r, ok := formats[format]
+	if !ok {
+		return nil, &UnknownFormatError{Format: format}
+	}
+
+	v := &validation{format: format, rules: r}
+
+	zoneSizes := map[Zone]int{}
+	copies := map[string]int{}
+	byName := map[string]Card{}
+	names := []string{}
+	for _, card := range cards {
+		if card.Zone == ZoneMaybeboard {
+			continue
+		}
+
+		zoneSizes[card.Zone] += card.Quantity
+		if _, seen := byName[card.Name]; !seen {
+			byName[card.Name] = card
+			names = append(names, card.Name)
+		}
+		copies[card.Name] += card.Quantity
+	}
+
+	v.checkSizes(zoneSizes)
+
+	for _, name := range names {
+		v.checkCard(byName[name], copies[name])
+	}
+
+	if r.commander {
+		v.checkCommanders(cards)
+	}
+
+	return v.violations, nil
+}
+
+func (v *validation) checkSizes(zoneSizes map[Zone]int) {
+	sideboard := zoneSizes[ZoneSideboard]
+
+	if v.rules.commander {
+		if size := zoneSizes[ZoneMain] + zoneSizes[ZoneCommander]; size != v.rules.deckSize {
+			v.deckf("deck has %d cards counting the commander, %s requires exactly %d", size, v.format, v.rules.deckSize)
+		}
+	} else {
+		if zoneSizes[ZoneMain] < v.rules.deckSize {
+			v.deckf("main deck has %d cards, %s requires at least %d", zoneSizes[ZoneMain], v.format, v.rules.deckSize)
+		}
+		if zoneSizes[ZoneCommander] > 0 {
+			v.deckf("%s decks have no commander", v.format)
+		}
+
+		// Outside commander formats the companion starts in the sideboard
+		sideboard += zoneSizes[ZoneCompanion]
+	}
+
+	if sideboard > v.rules.maxSideboard {
+		v.deckf("sideboard has %d cards, %s allows at most %d", sideboard, v.format, v.rules.maxSideboard)
+	}
+
+	if zoneSizes[ZoneCompanion] > 1 {
+		v.deckf("deck has %d companions, at most 1 is allowed", zoneSizes[ZoneCompanion])
+	}
+}
+
+func isBasicLand(typeLine string) bool {
+	return strings.HasPrefix(typeLine, "Basic ") && strings.Contains(typeLine, "Land")
+}
+
+func (v *validation) checkCard(card Card, copies int) {
+	scryfall := card.scryfallRules()
+
+	legality := scryfall.Legalities[v.format]
+	switch legality {
+	case "legal", "restricted":
+	case "banned":
+		v.cardf(card.Name, "banned in %s", v.format)
+	default:
+		v.cardf(card.Name, "not legal in %s", v.format)
+	}
+
+	limit := v.rules.maxCopies
+	if n, ok := copiesAllowed(scryfall.oracleText()); ok {
+		limit = n
+	}
+	if isBasicLand(card.TypeLine) {
+		limit = math.MaxInt
+	}
+	if legality == "restricted" {
+		limit = 1
+	}
+
+	if copies > limit {
+		v.cardf(card.Name, "%d copies, %s allows at most %d", copies, v.format, limit)
+	}
+}
+
+func canBeCommander(card Card, oracleText string) bool {
+	legendaryCreature := strings.Contains(card.TypeLine, "Legendary") && strings.Contains(card.TypeLine, "Creature")
+	return legendaryCreature || strings.Contains(oracleText, "can be your commander")
+}
+
+func canPair(a Card, aText string, b Card, bText string) bool {
+	partners := func(text string) bool {
+		return strings.Contains(text, "Partner") || strings.Contains(text, "Friends forever")
+	}
+	background := func(card Card, text string, other Card) bool {
+		return strings.Contains(text, "Choose a Background") && strings.Contains(other.TypeLine, "Background")
+	}
+
+	return (partners(aText) && partners(bText)) || background(a, aText, b) || background(b, bText, a)
+}
+
+func formatIdentity(identity string) string {
+	if identity == "" {
+		return "colorless"
+	}
+
+	return identity
+}
+
+func (v *validation) checkCommanders(cards []Card) {
+	commanders := []Card{}
+	for _, card := range cards {
+		if card.Zone == ZoneCommander {
+			for range card.Quantity {
+				commanders = append(commanders, card)
+			}
+		}
+	}
+
+	switch len(commanders) {
+	case 0:
+		v.deckf("deck has no commander")
+		return
+	case 1:
+		text := commanders[0].scryfallRules().oracleText()
+		if !canBeCommander(commanders[0], text) {
+			v.cardf(commanders[0].Name, "can't be a commander")
+		}
+	case 2:
+		aText := commanders[0].scryfallRules().oracleText()
+		bText := commanders[1].scryfallRules().oracleText()
+		if !canPair(commanders[0], aText, commanders[1], bText) {
+			v.deckf("%s and %s can't be commanders together", commanders[0].Name, commanders[1].Name)
+		}
+	default:
+		v.deckf("deck has %d commanders, at most 2 are allowed", len(commanders))
+		return
+	}
+
+	identity := ""
+	for _, commander := range commanders {
+		identity += commander.ColorIdentity
+	}
+
+	reported := map[string]bool{}
+	for _, card := range cards {
+		if card.Zone == ZoneCommander || card.Zone == ZoneMaybeboard || reported[card.Name] {
+			continue
+		}
+
+		for _, color := range card.ColorIdentity {
+			if !strings.ContainsRune(identity, color) {
+				v.cardf(card.Name, "color identity %s is outside the commander's %s", card.ColorIdentity, formatIdentity(identity))
+				reported[card.Name] = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package deck
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+const (
+	legalEverywhere = `{"legalities": {"standard": "legal", "modern": "legal", "vintage": "legal", "commander": "legal"}}`
+	restricted      = `{"legalities": {"vintage": "restricted", "commander": "legal"}}`
+	bannedModern    = `{"legalities": {"standard": "not_legal", "modern": "banned", "commander": "legal"}}`
+	anyNumber       = `{"oracle_text": "A deck can have any number of cards named Relentless Rats.", "legalities": {"modern": "legal", "commander": "legal"}}`
+	partner         = `{"oracle_text": "Partner (You can have two commanders if both have partner.)", "legalities": {"commander": "legal"}}`
+)
+
+func card(zone Zone, quantity int, name string, typeLine string, identity string, raw string) Card {
+	return Card{Zone: zone, Quantity: quantity, Name: name, TypeLine: typeLine, ColorIdentity: identity, Raw: raw}
+}
+
+func forest(quantity int) Card {
+	return card(ZoneMain, quantity, "Forest", "Basic Land — Forest", "G", legalEverywhere)
+}
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		cards    []Card
+		expected []string
+	}{
+		{
+			name:   "legal constructed deck",
+			format: "modern",
+			cards: []Card{
+				card(ZoneMain, 4, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				card(ZoneMain, 30, "Relentless Rats", "Creature — Rat", "B", anyNumber),
+				forest(26),
+				card(ZoneSideboard, 15, "Relentless Rats", "Creature — Rat", "B", anyNumber),
+				card(ZoneMaybeboard, 9, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+			},
+			expected: []string{},
+		},
+		{
+			name:   "constructed counts, copies and legality",
+			format: "modern",
+			cards: []Card{
+				card(ZoneMain, 3, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				card(ZoneSideboard, 2, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				card(ZoneMain, 1, "Birthing Pod", "Artifact", "G", bannedModern),
+				card(ZoneMain, 1, "Black Lotus", "Artifact", "", restricted),
+				forest(50),
+				card(ZoneSideboard, 14, "Forest", "Basic Land — Forest", "G", legalEverywhere),
+				card(ZoneCompanion, 1, "Lurrus of the Dream-Den", "Legendary Creature — Cat Nightmare", "WB", legalEverywhere),
+			},
+			expected: []string{
+				"main deck has 55 cards, modern requires at least 60",
+				"sideboard has 17 cards, modern allows at most 15",
+				"Llanowar Elves: 5 copies, modern allows at most 4",
+				"Birthing Pod: banned in modern",
+				"Black Lotus: not legal in modern",
+			},
+		},
+		{
+			name:   "restricted card",
+			format: "vintage",
+			cards: []Card{
+				card(ZoneMain, 2, "Black Lotus", "Artifact", "", restricted),
+				forest(58),
+			},
+			expected: []string{"Black Lotus: 2 copies, vintage allows at most 1"},
+		},
+		{
+			name:   "legal commander deck with partners",
+			format: "commander",
+			cards: []Card{
+				card(ZoneCommander, 1, "Thrasios", "Legendary Creature — Merfolk Wizard", "GU", partner),
+				card(ZoneCommander, 1, "Tymna", "Legendary Creature — Human Cleric", "WB", partner),
+				card(ZoneMain, 1, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				forest(97),
+			},
+			expected: []string{},
+		},
+		{
+			name:   "commander singleton, size and color identity",
+			format: "commander",
+			cards: []Card{
+				card(ZoneCommander, 1, "Ezuri", "Legendary Creature — Elf Warrior", "G", legalEverywhere),
+				card(ZoneMain, 2, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				card(ZoneMain, 1, "Lightning Bolt", "Instant", "R", legalEverywhere),
+				forest(90),
+			},
+			expected: []string{
+				"deck has 94 cards counting the commander, commander requires exactly 100",
+				"Llanowar Elves: 2 copies, commander allows at most 1",
+				"Lightning Bolt: color identity R is outside the commander's G",
+			},
+		},
+		{
+			name:   "commanders that can't lead",
+			format: "commander",
+			cards: []Card{
+				card(ZoneCommander, 1, "Ezuri", "Legendary Creature — Elf Warrior", "G", legalEverywhere),
+				card(ZoneCommander, 1, "Llanowar Elves", "Creature — Elf Druid", "G", legalEverywhere),
+				forest(98),
+			},
+			expected: []string{"Ezuri and Llanowar Elves can't be commanders together"},
+		},
+		{
+			name:     "missing commander",
+			format:   "commander",
+			cards:    []Card{forest(100)},
+			expected: []string{"deck has no commander"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations, err := Validate(test.format, test.cards)
+			if err != nil {
+				t.Fatalf("validating failed with error %v", err)
+			}
+
+			got := []string{}
+			for _, violation := range violations {
+				got = append(got, violation.String())
+			}
+
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected violations %q but got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_ValidateUnknownFormat(t *testing.T) {
+	var formatErr *UnknownFormatError
+	if _, err := Validate("two-headed", nil); !errors.As(err, &formatErr) {
+		t.Fatalf("expected UnknownFormatError but got %v", err)
+	}
+}
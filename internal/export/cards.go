@@ -0,0 +1,193 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+
+	"FedeAbella/mtgdb/internal/db"
+)
+
+// Card is a printing as exported for analytics, one row per card. The ids
+// are written as UUID columns and the timestamps in UTC.
+type Card struct {
+	ScryfallID      uuid.UUID `json:"scryfall_id" parquet:"scryfall_id,uuid"`
+	OracleID        uuid.UUID `json:"oracle_id" parquet:"oracle_id,uuid"`
+	SetID           uuid.UUID `json:"set_id" parquet:"set_id,uuid"`
+	SetCode         string    `json:"set" parquet:"set,dict"`
+	SetName         string    `json:"set_name" parquet:"set_name,dict"`
+	CollectorNumber string    `json:"collector_number" parquet:"collector_number"`
+	LanguageCode    string    `json:"lang" parquet:"lang,dict"`
+	Name            string    `json:"name" parquet:"name"`
+	SpanishName     *string   `json:"spanish_name" parquet:"spanish_name,optional"`
+	Rarity          *string   `json:"rarity" parquet:"rarity,optional,dict"`
+	Colors          *string   `json:"colors" parquet:"colors,optional,dict"`
+	ColorIdentity   *string   `json:"color_identity" parquet:"color_identity,optional,dict"`
+	TypeLine        string    `json:"type_line" parquet:"type_line"`
+	ScryfallURI     string    `json:"scryfall_uri" parquet:"scryfall_uri"`
+	CreatedAt       time.Time `json:"created_at" parquet:"created_at,timestamp(microsecond)"`
+	UpdatedAt       time.Time `json:"updated_at" parquet:"updated_at,timestamp(microsecond)"`
+}
+
+func optional(text pgtype.Text) *string {
+	if !text.Valid {
+		return nil
+	}
+
+	return &text.String
+}
+
+func NewCard(card db.CardWithSet) Card {
+	return Card{
+		ScryfallID:      card.Card.ScryfallID.Bytes,
+		OracleID:        card.Card.ScryfallOracleID.Bytes,
+		SetID:           card.Card.SetID.Bytes,
+		SetCode:         card.SetCode,
+		SetName:         card.SetName,
+		CollectorNumber: card.Card.CollectorNumber,
+		LanguageCode:    card.Card.LanguageCode,
+		Name:            card.Card.Name,
+		SpanishName:     optional(card.Card.SpanishName),
+		Rarity:          optional(card.Card.Rarity),
+		Colors:          optional(card.Card.Colors),
+		ColorIdentity:   optional(card.Card.ColorIdentity),
+		TypeLine:        card.Card.TypeLine,
+		ScryfallURI:     card.Card.ScryfallWebUri,
+		CreatedAt:       card.Card.CreatedAt.Time.UTC(),
+		UpdatedAt:       card.Card.UpdatedAt.Time.UTC(),
+	}
+}
+
+type cardWriter interface {
+	Write(card Card) error
+	Close() error
+}
+
+var cardWriters = map[string]func(w io.Writer) cardWriter{
+	"jsonl":   newJSONLWriter,
+	"parquet": newParquetWriter,
+}
+
+func Formats() []string {
+	return slices.Sorted(maps.Keys(cardWriters))
+}
+
+type UnknownFormatError struct {
+	Format string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("unknown format %q, expected one of %s", e.Format, strings.Join(Formats(), ", "))
+}
+
+// Cards streams the cards matching filter to w in format, returning how many
+// were written.
+func Cards(ctx context.Context, store db.Store, w io.Writer, format string, filter db.CardFilter) (int, error) {
+	newWriter, ok := cardWriters[format]
+	if !ok {
+		return 0, &UnknownFormatError{Format: format}
+	}
+
+	writer := newWriter(w)
+	count := 0
+	err := store.GetAllCardsWithSets(ctx, filter, func(card db.CardWithSet) error {
+		count++
+		return writer.Write(NewCard(card))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, writer.Close()
+}
+
+// CardsFile writes the cards matching filter in format to a new file at path,
+// replacing any file there only once it is complete.
+func CardsFile(ctx context.Context, store db.Store, path string, format string, filter db.CardFilter) (int, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	count, err := Cards(ctx, store, tmp, format, filter)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return count, os.Rename(tmp.Name(), path)
+}
+
+type jsonlWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) cardWriter {
+	buffer := bufio.NewWriter(w)
+	return &jsonlWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+func (w *jsonlWriter) Write(card Card) error {
+	return w.encoder.Encode(card)
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.buffer.Flush()
+}
+
+// parquetBatch is how many cards are buffered before handing them to the
+// parquet writer, which cuts them into row groups on its own.
+const parquetBatch = 1024
+
+type parquetWriter struct {
+	writer *parquet.GenericWriter[Card]
+	batch  []Card
+}
+
+func newParquetWriter(w io.Writer) cardWriter {
+	return &parquetWriter{
+		writer: parquet.NewGenericWriter[Card](w, parquet.Compression(&parquet.Zstd)),
+		batch:  make([]Card, 0, parquetBatch),
+	}
+}
+
+func (w *parquetWriter) Write(card Card) error {
+	if w.batch = append(w.batch, card); len(w.batch) < parquetBatch {
+		return nil
+	}
+
+	return w.flush()
+}
+
+func (w *parquetWriter) flush() error {
+	_, err := w.writer.Write(w.batch)
+	w.batch = w.batch[:0]
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	return w.writer.Close()
+}
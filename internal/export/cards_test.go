@@ -0,0 +1,174 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+func newCardsTestStore(t *testing.T) db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store := newTestStore(t)
+	later := pgtype.Timestamp{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	cards := []sqlc.InsertCardsParams{{
+		ScryfallID:       pgtype.UUID{Bytes: uuid.MustParse("00000000-0000-4000-8000-000000000002"), Valid: true},
+		SetID:            testSetID,
+		Name:             "Shock",
+		CollectorNumber:  "2",
+		Colors:           pgtype.Text{String: "R", Valid: true},
+		LanguageCode:     "en",
+		Rarity:           pgtype.Text{String: "common", Valid: true},
+		TypeLine:         "Instant",
+		ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		CreatedAt:        later,
+		UpdatedAt:        later,
+	}}
+	if _, err := store.InsertCards(ctx, cards); err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+
+	return store
+}
+
+func readJSONL(t *testing.T, data []byte) []Card {
+	t.Helper()
+
+	cards := []Card{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var card Card
+		if err := json.Unmarshal(scanner.Bytes(), &card); err != nil {
+			t.Fatalf("decoding line %q failed with error %v", scanner.Text(), err)
+		}
+		cards = append(cards, card)
+	}
+
+	return cards
+}
+
+func names(cards []Card) []string {
+	got := []string{}
+	for _, card := range cards {
+		got = append(got, card.Name)
+	}
+
+	return got
+}
+
+func Test_Cards(t *testing.T) {
+	ctx := context.Background()
+	store := newCardsTestStore(t)
+
+	tests := []struct {
+		name     string
+		filter   db.CardFilter
+		expected []string
+	}{
+		{name: "all", expected: []string{"Lightning Bolt", "Shock"}},
+		{name: "set", filter: db.CardFilter{SetCode: "m10"}, expected: []string{}},
+		{name: "language", filter: db.CardFilter{LanguageCode: "en"}, expected: []string{"Shock"}},
+		{
+			name:     "updated since",
+			filter:   db.CardFilter{UpdatedSince: pgtype.Timestamp{Time: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Valid: true}},
+			expected: []string{"Shock"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			count, err := Cards(ctx, store, &buffer, "jsonl", test.filter)
+			if err != nil {
+				t.Fatalf("exporting failed with error %v", err)
+			}
+
+			got := names(readJSONL(t, buffer.Bytes()))
+			if count != len(test.expected) || !reflect.DeepEqual(got, test.expected) {
+				t.Fatalf("expected %v but got %d cards %v", test.expected, count, got)
+			}
+		})
+	}
+
+	var jsonl bytes.Buffer
+	if _, err := Cards(ctx, store, &jsonl, "jsonl", db.CardFilter{}); err != nil {
+		t.Fatalf("exporting jsonl failed with error %v", err)
+	}
+	var parquetFile bytes.Buffer
+	if _, err := Cards(ctx, store, &parquetFile, "parquet", db.CardFilter{}); err != nil {
+		t.Fatalf("exporting parquet failed with error %v", err)
+	}
+
+	fromParquet, err := parquet.Read[Card](bytes.NewReader(parquetFile.Bytes()), int64(parquetFile.Len()))
+	if err != nil {
+		t.Fatalf("reading parquet failed with error %v", err)
+	}
+	fromJSONL := readJSONL(t, jsonl.Bytes())
+	if !reflect.DeepEqual(fromParquet, fromJSONL) {
+		t.Fatalf("expected the parquet and jsonl exports to match, got %+v and %+v", fromParquet, fromJSONL)
+	}
+	if bolt := fromJSONL[0]; bolt.ScryfallID != testCardID.Bytes || bolt.Rarity != nil || *bolt.SpanishName != "Relámpago" {
+		t.Fatalf("unexpected Lightning Bolt row %+v", bolt)
+	}
+
+	var unknownErr *UnknownFormatError
+	if _, err := Cards(ctx, store, &jsonl, "csv", db.CardFilter{}); !errors.As(err, &unknownErr) {
+		t.Fatalf("expected UnknownFormatError but got %v", err)
+	}
+}
+
+// failingStore fails streaming cards after the first one.
+type failingStore struct {
+	db.Store
+}
+
+func (s failingStore) GetAllCardsWithSets(ctx context.Context, filter db.CardFilter, fn func(db.CardWithSet) error) error {
+	return s.Store.GetAllCardsWithSets(ctx, filter, func(card db.CardWithSet) error {
+		if err := fn(card); err != nil {
+			return err
+		}
+		return errFailingStore
+	})
+}
+
+var errFailingStore = errors.New("connection lost")
+
+func Test_CardsFile(t *testing.T) {
+	ctx := context.Background()
+	store := newCardsTestStore(t)
+	path := filepath.Join(t.TempDir(), "cards.jsonl")
+
+	count, err := CardsFile(ctx, store, path, "jsonl", db.CardFilter{})
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 cards exported, got %d with error %v", count, err)
+	}
+	exported, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export failed with error %v", err)
+	}
+
+	if _, err := CardsFile(ctx, failingStore{store}, path, "jsonl", db.CardFilter{}); !errors.Is(err, errFailingStore) {
+		t.Fatalf("expected the export to fail with %v, got %v", errFailingStore, err)
+	}
+	if after, err := os.ReadFile(path); err != nil || !bytes.Equal(after, exported) {
+		t.Fatalf("expected a failed export to keep the previous file, got %q with error %v", after, err)
+	}
+	if matches, _ := filepath.Glob(path + ".*.tmp"); len(matches) != 0 {
+		t.Fatalf("expected failed exports to leave no temp files, got %v", matches)
+	}
+}
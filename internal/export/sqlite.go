@@ -0,0 +1,464 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+)
+
+// FormatVersion is bumped whenever the schema of the exported file changes,
+// so the browser reading it can tell which one it has.
+const FormatVersion = "1"
+
+// Tables are the optional tables an export can include besides the sets and
+// cards, each with its items.
+var Tables = []string{"collections", "decks"}
+
+type UnknownTableError struct {
+	Table string
+}
+
+func (e *UnknownTableError) Error() string {
+	return fmt.Sprintf("unknown table %q, expected one of %s", e.Table, strings.Join(Tables, ", "))
+}
+
+var (
+	errStoreWithoutCollections = errors.New("the db backend doesn't support collections")
+	errStoreWithoutDecks       = errors.New("the db backend doesn't support decks")
+)
+
+type SQLiteOptions struct {
+	// Tables are the optional tables included
+	Tables []string
+	// Raw includes the raw Scryfall JSON of each card, which makes up most of
+	// the db's size
+	Raw bool
+}
+
+type SQLiteSummary struct {
+	Sets  int
+	Cards int
+}
+
+// The card names are indexed in an external content FTS5 table, folding
+// accents so Spanish names match typed without them.
+const sqliteExportSchema = `
+CREATE TABLE metadata (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+CREATE TABLE sets (
+    scryfall_id TEXT PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE cards (
+    scryfall_id TEXT PRIMARY KEY,
+    set_id TEXT NOT NULL REFERENCES sets(scryfall_id),
+    name TEXT NOT NULL,
+    collector_number TEXT NOT NULL,
+    color_identity TEXT,
+    colors TEXT,
+    language_code TEXT NOT NULL,
+    spanish_name TEXT,
+    rarity TEXT,
+    type_line TEXT NOT NULL,
+    scryfall_api_uri TEXT NOT NULL,
+    scryfall_web_uri TEXT NOT NULL,
+    scryfall_oracle_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    raw TEXT
+);`
+
+const sqliteExportIndexes = `
+CREATE INDEX cards_set_id_idx ON cards (set_id, collector_number);
+CREATE INDEX cards_scryfall_oracle_id_idx ON cards (scryfall_oracle_id);
+CREATE INDEX cards_name_idx ON cards (name);
+
+CREATE VIRTUAL TABLE card_names USING fts5(
+    name,
+    spanish_name,
+    content = 'cards',
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+INSERT INTO card_names (card_names) VALUES ('rebuild');`
+
+const sqliteExportCollections = `
+CREATE TABLE collections (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    owner TEXT,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE collection_items (
+    id INTEGER PRIMARY KEY,
+    collection_id INTEGER NOT NULL REFERENCES collections(id),
+    scryfall_id TEXT NOT NULL REFERENCES cards(scryfall_id),
+    quantity INTEGER NOT NULL,
+    finish TEXT NOT NULL,
+    condition TEXT NOT NULL,
+    language_code TEXT NOT NULL,
+    acquired_price_cents INTEGER,
+    acquired_currency TEXT,
+    acquired_on TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX collection_items_collection_id_idx ON collection_items (collection_id);`
+
+const sqliteExportDecks = `
+CREATE TABLE decks (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    format TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE TABLE deck_cards (
+    id INTEGER PRIMARY KEY,
+    deck_id INTEGER NOT NULL REFERENCES decks(id),
+    zone TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    scryfall_oracle_id TEXT NOT NULL,
+    scryfall_id TEXT REFERENCES cards(scryfall_id),
+    finish TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+CREATE INDEX deck_cards_deck_id_idx ON deck_cards (deck_id);`
+
+func timestamp(t pgtype.Timestamp) string {
+	return t.Time.UTC().Format(time.RFC3339)
+}
+
+func date(d pgtype.Date) any {
+	if !d.Valid {
+		return nil
+	}
+
+	return d.Time.Format(time.DateOnly)
+}
+
+// SQLite writes the sets and cards of the store, and the optional tables, to
+// a new SQLite db at path, replacing any file there only once it is complete.
+func SQLite(ctx context.Context, store db.Store, path string, options SQLiteOptions) (SQLiteSummary, error) {
+	tables := []string{}
+	for _, table := range options.Tables {
+		if !slices.Contains(Tables, table) {
+			return SQLiteSummary{}, &UnknownTableError{Table: table}
+		}
+		if !slices.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+	options.Tables = tables
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		log.Println(err)
+		return SQLiteSummary{}, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	sqlDb, err := sql.Open(db.DriverSqlite, "file:"+tmp.Name())
+	if err != nil {
+		log.Println(err)
+		return SQLiteSummary{}, err
+	}
+	defer sqlDb.Close()
+
+	var summary SQLiteSummary
+	err = withTx(ctx, sqlDb, func(tx *sql.Tx) error {
+		if summary, err = writeCards(ctx, tx, store, options); err != nil {
+			return err
+		}
+
+		for _, table := range options.Tables {
+			switch table {
+			case "collections":
+				err = writeCollections(ctx, tx, store)
+			case "decks":
+				err = writeDecks(ctx, tx, store)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeMetadata(ctx, tx, store, options)
+	})
+	if err != nil {
+		log.Println(err)
+		return SQLiteSummary{}, err
+	}
+
+	if err := sqlDb.Close(); err != nil {
+		log.Println(err)
+		return SQLiteSummary{}, err
+	}
+
+	return summary, os.Rename(tmp.Name(), path)
+}
+
+func withTx(ctx context.Context, sqlDb *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := sqlDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func writeCards(ctx context.Context, tx *sql.Tx, store db.Store, options SQLiteOptions) (SQLiteSummary, error) {
+	if _, err := tx.ExecContext(ctx, sqliteExportSchema); err != nil {
+		return SQLiteSummary{}, err
+	}
+
+	sets, err := store.GetAllSets(ctx)
+	if err != nil {
+		return SQLiteSummary{}, err
+	}
+
+	for _, set := range sets {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO sets VALUES (?, ?, ?, ?, ?)",
+			set.ScryfallID,
+			set.Code,
+			set.Name,
+			timestamp(set.CreatedAt),
+			timestamp(set.UpdatedAt),
+		)
+		if err != nil {
+			return SQLiteSummary{}, err
+		}
+	}
+
+	insertCard, err := tx.PrepareContext(ctx, "INSERT INTO cards VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return SQLiteSummary{}, err
+	}
+	defer insertCard.Close()
+
+	summary := SQLiteSummary{Sets: len(sets)}
+	err = store.GetAllCardsWithSets(ctx, db.CardFilter{}, func(card db.CardWithSet) error {
+		raw := pgtype.Text{String: card.Card.Raw, Valid: options.Raw}
+
+		_, err := insertCard.ExecContext(
+			ctx,
+			card.Card.ScryfallID,
+			card.Card.SetID,
+			card.Card.Name,
+			card.Card.CollectorNumber,
+			card.Card.ColorIdentity,
+			card.Card.Colors,
+			card.Card.LanguageCode,
+			card.Card.SpanishName,
+			card.Card.Rarity,
+			card.Card.TypeLine,
+			card.Card.ScryfallApiUri,
+			card.Card.ScryfallWebUri,
+			card.Card.ScryfallOracleID,
+			timestamp(card.Card.CreatedAt),
+			timestamp(card.Card.UpdatedAt),
+			raw,
+		)
+		summary.Cards++
+		return err
+	})
+	if err != nil {
+		return SQLiteSummary{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, sqliteExportIndexes); err != nil {
+		return SQLiteSummary{}, err
+	}
+
+	return summary, nil
+}
+
+func writeCollections(ctx context.Context, tx *sql.Tx, store db.Store) error {
+	collectionStore, ok := store.(db.CollectionStore)
+	if !ok {
+		return errStoreWithoutCollections
+	}
+
+	if _, err := tx.ExecContext(ctx, sqliteExportCollections); err != nil {
+		return err
+	}
+
+	collections, err := collectionStore.GetAllCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO collections VALUES (?, ?, ?, ?)",
+			collection.ID,
+			collection.Name,
+			collection.Owner,
+			timestamp(collection.CreatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		items, err := collectionStore.GetCollectionItems(ctx, collection.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range items {
+			item := row.CollectionItem
+			_, err := tx.ExecContext(
+				ctx,
+				"INSERT INTO collection_items VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				item.ID,
+				item.CollectionID,
+				item.ScryfallID,
+				item.Quantity,
+				item.Finish,
+				item.Condition,
+				item.LanguageCode,
+				item.AcquiredPriceCents,
+				item.AcquiredCurrency,
+				date(item.AcquiredOn),
+				timestamp(item.CreatedAt),
+				timestamp(item.UpdatedAt),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeDecks(ctx context.Context, tx *sql.Tx, store db.Store) error {
+	deckStore, ok := store.(db.DeckStore)
+	if !ok {
+		return errStoreWithoutDecks
+	}
+
+	if _, err := tx.ExecContext(ctx, sqliteExportDecks); err != nil {
+		return err
+	}
+
+	decks, err := deckStore.GetAllDecks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, deck := range decks {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO decks VALUES (?, ?, ?, ?, ?)",
+			deck.ID,
+			deck.Name,
+			deck.Format,
+			timestamp(deck.CreatedAt),
+			timestamp(deck.UpdatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		cards, err := deckStore.GetDeckCards(ctx, deck.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, card := range cards {
+			_, err := tx.ExecContext(
+				ctx,
+				"INSERT INTO deck_cards VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+				card.ID,
+				card.DeckID,
+				card.Zone,
+				card.Quantity,
+				card.ScryfallOracleID,
+				card.ScryfallID,
+				card.Finish,
+				timestamp(card.CreatedAt),
+				timestamp(card.UpdatedAt),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeMetadata records the export along with the last sync run of the store,
+// if it keeps them, and its card count and latest card update time.
+func writeMetadata(ctx context.Context, tx *sql.Tx, store db.Store, options SQLiteOptions) error {
+	version, err := store.GetCardsVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	metadata := [][2]string{
+		{"format_version", FormatVersion},
+		{"exported_at", time.Now().UTC().Format(time.RFC3339)},
+		{"card_count", fmt.Sprint(version.CardCount)},
+		{"cards_last_updated_at", version.LastUpdatedAt},
+		{"tables", strings.Join(append([]string{"sets", "cards"}, options.Tables...), ",")},
+		{"raw", fmt.Sprint(options.Raw)},
+	}
+
+	if runs, ok := store.(db.SyncRunStore); ok {
+		run, err := runs.GetLastSyncRun(ctx)
+		switch {
+		case errors.Is(err, db.ErrNotFound):
+			// Nothing was synced yet
+		case err != nil:
+			return err
+		default:
+			metadata = append(
+				metadata,
+				[2]string{"sync_run_id", fmt.Sprint(run.ID)},
+				[2]string{"sync_run_started_at", run.StartedAt.Time.UTC().Format(time.RFC3339)},
+				[2]string{"sync_run_finished_at", run.FinishedAt.Time.UTC().Format(time.RFC3339)},
+			)
+		}
+	}
+
+	for _, entry := range metadata {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO metadata VALUES (?, ?)", entry[0], entry[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/dbtest"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var (
+	testSetID  = pgtype.UUID{Bytes: uuid.MustParse("20000000-0000-4000-8000-000000000001"), Valid: true}
+	testCardID = pgtype.UUID{Bytes: uuid.MustParse("00000000-0000-4000-8000-000000000001"), Valid: true}
+)
+
+func newTestStore(t *testing.T) db.Store {
+	t.Helper()
+	ctx := context.Background()
+	store := db.NewMemoryStore()
+	now := pgtype.Timestamp{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}
+
+	sets := []sqlc.InsertSetsParams{
+		{ScryfallID: testSetID, Code: "dmu", Name: "Dominaria United", CreatedAt: now, UpdatedAt: now},
+	}
+	if _, err := store.InsertSets(ctx, sets); err != nil {
+		t.Fatalf("inserting sets failed with error %v", err)
+	}
+
+	cards := []sqlc.InsertCardsParams{{
+		ScryfallID:       testCardID,
+		SetID:            testSetID,
+		Name:             "Lightning Bolt",
+		CollectorNumber:  "1",
+		Colors:           pgtype.Text{String: "R", Valid: true},
+		LanguageCode:     "es",
+		SpanishName:      pgtype.Text{String: "Relámpago", Valid: true},
+		TypeLine:         "Instant",
+		ScryfallOracleID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Raw:              `{"name": "Lightning Bolt"}`,
+	}}
+	if _, err := store.InsertCards(ctx, cards); err != nil {
+		t.Fatalf("inserting cards failed with error %v", err)
+	}
+
+	return store
+}
+
+func readMetadata(t *testing.T, sqlDb *sql.DB) map[string]string {
+	t.Helper()
+
+	rows, err := sqlDb.QueryContext(context.Background(), "SELECT key, value FROM metadata")
+	if err != nil {
+		t.Fatalf("reading metadata failed with error %v", err)
+	}
+	defer rows.Close()
+
+	metadata := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			t.Fatalf("reading metadata failed with error %v", err)
+		}
+		metadata[key] = value
+	}
+
+	return metadata
+}
+
+func Test_SQLite(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	path := filepath.Join(t.TempDir(), "cards.db")
+
+	summary, err := SQLite(ctx, store, path, SQLiteOptions{})
+	if err != nil {
+		t.Fatalf("exporting failed with error %v", err)
+	}
+	if summary.Sets != 1 || summary.Cards != 1 {
+		t.Fatalf("expected 1 set and 1 card exported, got %+v", summary)
+	}
+
+	sqlDb, err := sql.Open(db.DriverSqlite, "file:"+path)
+	if err != nil {
+		t.Fatalf("opening export failed with error %v", err)
+	}
+	defer sqlDb.Close()
+
+	var name, setCode string
+	var raw sql.NullString
+	err = sqlDb.QueryRowContext(
+		ctx,
+		`SELECT cards.name, sets.code, cards.raw
+		FROM card_names
+		JOIN cards ON cards.rowid = card_names.rowid
+		JOIN sets ON sets.scryfall_id = cards.set_id
+		WHERE card_names MATCH 'relampago'`,
+	).Scan(&name, &setCode, &raw)
+	if err != nil {
+		t.Fatalf("searching the export failed with error %v", err)
+	}
+	if name != "Lightning Bolt" || setCode != "dmu" || raw.Valid {
+		t.Fatalf("expected Lightning Bolt from dmu without raw json, got %s from %s with %v", name, setCode, raw)
+	}
+
+	metadata := readMetadata(t, sqlDb)
+	if metadata["format_version"] != FormatVersion || metadata["card_count"] != "1" || metadata["tables"] != "sets,cards" {
+		t.Fatalf("unexpected metadata %v", metadata)
+	}
+
+	var unknownErr *UnknownTableError
+	if _, err := SQLite(ctx, store, path, SQLiteOptions{Tables: []string{"wants"}}); !errors.As(err, &unknownErr) {
+		t.Fatalf("expected UnknownTableError but got %v", err)
+	}
+	if _, err := SQLite(ctx, store, path, SQLiteOptions{Tables: []string{"collections"}}); err == nil {
+		t.Fatalf("expected an error exporting collections from a store without them")
+	}
+	if matches, _ := filepath.Glob(path + ".*.tmp"); len(matches) != 0 {
+		t.Fatalf("expected failed exports to leave no temp files, got %v", matches)
+	}
+
+	sqliteStore := dbtest.NewSqliteStore(t)
+	dbtest.Seed(t, sqliteStore, dbtest.NewSet("dmu", "Dominaria United"), dbtest.NewCard("Shock", "1"))
+	options := SQLiteOptions{Tables: []string{"collections", "decks", "collections"}}
+	if _, err := SQLite(ctx, sqliteStore, path, options); err != nil {
+		t.Fatalf("exporting repeated tables failed with error %v", err)
+	}
+	if matches, _ := filepath.Glob(path + ".*.tmp"); len(matches) != 0 {
+		t.Fatalf("expected failed exports to leave no temp files, got %v", matches)
+	}
+}
+
+func Test_SQLiteSyncRun(t *testing.T) {
+	ctx := context.Background()
+	store := dbtest.NewSqliteStore(t)
+	dbtest.Seed(t, store, dbtest.NewSet("dmu", "Dominaria United"), dbtest.NewCard("Shock", "1"))
+	path := filepath.Join(t.TempDir(), "cards.db")
+
+	exportMetadata := func() map[string]string {
+		if _, err := SQLite(ctx, store, path, SQLiteOptions{}); err != nil {
+			t.Fatalf("exporting failed with error %v", err)
+		}
+
+		sqlDb, err := sql.Open(db.DriverSqlite, "file:"+path)
+		if err != nil {
+			t.Fatalf("opening export failed with error %v", err)
+		}
+		defer sqlDb.Close()
+
+		return readMetadata(t, sqlDb)
+	}
+
+	if metadata := exportMetadata(); metadata["sync_run_id"] != "" {
+		t.Fatalf("expected no sync run before syncing, got %v", metadata)
+	}
+
+	runs := store.(db.SyncRunStore)
+	for i := range 2 {
+		startedAt := time.Date(2026, 1, 2, 3, i, 0, 0, time.UTC)
+		_, err := runs.InsertSyncRun(ctx, sqlc.InsertSyncRunParams{
+			StartedAt:  pgtype.Timestamp{Time: startedAt, Valid: true},
+			FinishedAt: pgtype.Timestamp{Time: startedAt.Add(30 * time.Second), Valid: true},
+		})
+		if err != nil {
+			t.Fatalf("inserting sync run failed with error %v", err)
+		}
+	}
+
+	metadata := exportMetadata()
+	if metadata["sync_run_id"] != "2" ||
+		metadata["sync_run_started_at"] != "2026-01-02T03:01:00Z" ||
+		metadata["sync_run_finished_at"] != "2026-01-02T03:01:30Z" {
+		t.Fatalf("expected the last sync run in the metadata, got %v", metadata)
+	}
+}
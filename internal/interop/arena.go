@@ -0,0 +1,78 @@
+package interop
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+// arenaSections are the zones Arena imports, in the order it lists them.
+// Collections have no zone and go in the deck.
+var arenaSections = []struct {
+	header string
+	zones  []deck.Zone
+}{
+	{header: "Commander", zones: []deck.Zone{deck.ZoneCommander}},
+	{header: "Companion", zones: []deck.Zone{deck.ZoneCompanion}},
+	{header: "Deck", zones: []deck.Zone{deck.ZoneMain, ""}},
+	{header: "Sideboard", zones: []deck.Zone{deck.ZoneSideboard}},
+}
+
+// WriteArena renders the entries as an Arena decklist, one section per zone.
+// Arena has no finishes, so copies of a printing in different finishes add up
+// to a single line, and it has no maybeboard.
+func WriteArena(w io.Writer, entries []Entry) error {
+	first := true
+	for _, section := range arenaSections {
+		type printing struct {
+			name, setCode, collectorNumber string
+		}
+
+		lines := []printing{}
+		quantities := map[printing]int{}
+		for _, entry := range entries {
+			if !slices.Contains(section.zones, entry.Zone) {
+				continue
+			}
+
+			key := printing{name: entry.Name, setCode: entry.SetCode, collectorNumber: entry.CollectorNumber}
+			if _, seen := quantities[key]; !seen {
+				lines = append(lines, key)
+			}
+			quantities[key] += entry.Quantity
+		}
+
+		if len(lines) == 0 {
+			continue
+		}
+
+		if !first {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		if _, err := fmt.Fprintln(w, section.header); err != nil {
+			return err
+		}
+		for _, line := range lines {
+			_, err := fmt.Fprintf(
+				w,
+				"%d %s (%s) %s\n",
+				quantities[line],
+				line.name,
+				strings.ToUpper(line.setCode),
+				line.collectorNumber,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
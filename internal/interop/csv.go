@@ -0,0 +1,111 @@
+package interop
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+var moxfieldHeader = []string{
+	"Count",
+	"Tradelist Count",
+	"Name",
+	"Edition",
+	"Condition",
+	"Language",
+	"Foil",
+	"Tags",
+	"Last Modified",
+	"Collector Number",
+	"Alter",
+	"Proxy",
+	"Purchase Price",
+}
+
+// WriteMoxfield renders the entries in the CSV layout of Moxfield's collection
+// import, which Archidekt also reads. Deck zones go in the tags, and cards
+// without a condition are near mint.
+func WriteMoxfield(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(moxfieldHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		condition := conditionNames["NM"]
+		if entry.Condition != "" {
+			condition = conditionNames[entry.Condition]
+		}
+
+		foil := ""
+		if entry.Finish != deck.FinishNonfoil {
+			foil = entry.Finish
+		}
+
+		err := writer.Write([]string{
+			strconv.Itoa(entry.Quantity),
+			"0",
+			entry.Name,
+			entry.SetCode,
+			condition,
+			languageName(entry.LanguageCode),
+			foil,
+			entry.Zone,
+			"",
+			entry.CollectorNumber,
+			"False",
+			"False",
+			"",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+var csvHeader = []string{
+	"quantity",
+	"name",
+	"set_code",
+	"set_name",
+	"collector_number",
+	"language",
+	"finish",
+	"condition",
+	"zone",
+	"scryfall_id",
+}
+
+// WriteCSV renders the entries as plain printing rows.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err := writer.Write([]string{
+			strconv.Itoa(entry.Quantity),
+			entry.Name,
+			entry.SetCode,
+			entry.SetName,
+			entry.CollectorNumber,
+			entry.LanguageCode,
+			entry.Finish,
+			entry.Condition,
+			entry.Zone,
+			entry.ScryfallID.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,122 @@
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Entry is a number of copies of a printing, the row every exporter renders.
+type Entry struct {
+	// Zone is empty for collections
+	Zone            deck.Zone
+	Quantity        int
+	Name            string
+	SetCode         string
+	SetName         string
+	CollectorNumber string
+	LanguageCode    string
+	Finish          string
+	// Condition is empty for decks
+	Condition  string
+	ScryfallID uuid.UUID
+	// MTGOID is the printing's catalog id on MTGO, 0 if it has none
+	MTGOID int
+}
+
+// mtgoIDs are the fields of a card's Scryfall JSON with its MTGO catalog ids.
+type mtgoIDs struct {
+	MTGOID     int `json:"mtgo_id"`
+	MTGOFoilID int `json:"mtgo_foil_id"`
+}
+
+func mtgoID(raw string, finish string) int {
+	var ids mtgoIDs
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		return 0
+	}
+
+	if finish != deck.FinishNonfoil && ids.MTGOFoilID != 0 {
+		return ids.MTGOFoilID
+	}
+
+	return ids.MTGOID
+}
+
+func newEntry(card sqlc.Card, setCode string, setName string, quantity int, finish string) Entry {
+	return Entry{
+		Quantity:        quantity,
+		Name:            card.Name,
+		SetCode:         setCode,
+		SetName:         setName,
+		CollectorNumber: card.CollectorNumber,
+		LanguageCode:    card.LanguageCode,
+		Finish:          finish,
+		ScryfallID:      uuid.UUID(card.ScryfallID.Bytes),
+		MTGOID:          mtgoID(card.Raw, finish),
+	}
+}
+
+// DeckEntries converts the entries of a deck, using the first printing of the
+// cards the deck names without one.
+func DeckEntries(entries []db.DeckEntry) []Entry {
+	converted := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		e := newEntry(entry.Card.Card, entry.Card.SetCode, entry.Card.SetName, int(entry.DeckCard.Quantity), entry.DeckCard.Finish)
+		e.Zone = entry.DeckCard.Zone
+		converted = append(converted, e)
+	}
+
+	return converted
+}
+
+func CollectionEntries(items []sqlc.GetCollectionItemsWithCardsRow) []Entry {
+	converted := make([]Entry, 0, len(items))
+	for _, item := range items {
+		e := newEntry(item.Card, item.SetCode, item.SetName, int(item.CollectionItem.Quantity), item.CollectionItem.Finish)
+		e.LanguageCode = item.CollectionItem.LanguageCode
+		e.Condition = item.CollectionItem.Condition
+		converted = append(converted, e)
+	}
+
+	return converted
+}
+
+type Exporter func(w io.Writer, entries []Entry) error
+
+var exporters = map[string]Exporter{
+	"arena":    WriteArena,
+	"mtgo":     WriteMTGO,
+	"moxfield": WriteMoxfield,
+	"csv":      WriteCSV,
+}
+
+func ExportFormats() []string {
+	return slices.Sorted(maps.Keys(exporters))
+}
+
+type UnknownExportFormatError struct {
+	Format string
+}
+
+func (e *UnknownExportFormatError) Error() string {
+	return fmt.Sprintf("unknown export format %q, expected one of %s", e.Format, strings.Join(ExportFormats(), ", "))
+}
+
+func Export(w io.Writer, format string, entries []Entry) error {
+	exporter, ok := exporters[format]
+	if !ok {
+		return &UnknownExportFormatError{Format: format}
+	}
+
+	return exporter(w, entries)
+}
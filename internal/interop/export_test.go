@@ -0,0 +1,188 @@
+package interop
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/deck"
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+var testEntries = []Entry{
+	{
+		Zone:            deck.ZoneCommander,
+		Quantity:        1,
+		Name:            "Zurgo Bellstriker",
+		SetCode:         "dtk",
+		SetName:         "Dragons of Tarkir",
+		CollectorNumber: "169",
+		LanguageCode:    "en",
+		Finish:          deck.FinishNonfoil,
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		MTGOID:          55000,
+	},
+	{
+		Zone:            deck.ZoneMain,
+		Quantity:        3,
+		Name:            "Lightning Bolt",
+		SetCode:         "m10",
+		SetName:         "Magic 2010",
+		CollectorNumber: "146",
+		LanguageCode:    "es",
+		Finish:          deck.FinishNonfoil,
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000002"),
+		MTGOID:          31000,
+	},
+	{
+		Zone:            deck.ZoneMain,
+		Quantity:        1,
+		Name:            "Lightning Bolt",
+		SetCode:         "m10",
+		SetName:         "Magic 2010",
+		CollectorNumber: "146",
+		LanguageCode:    "es",
+		Finish:          deck.FinishFoil,
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000002"),
+		MTGOID:          31001,
+	},
+	{
+		Zone:            deck.ZoneSideboard,
+		Quantity:        2,
+		Name:            "Fire // Ice",
+		SetCode:         "mh2",
+		SetName:         "Modern Horizons 2",
+		CollectorNumber: "290",
+		LanguageCode:    "en",
+		Finish:          deck.FinishEtched,
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000003"),
+	},
+	{
+		Zone:            deck.ZoneMaybeboard,
+		Quantity:        1,
+		Name:            "Shock",
+		SetCode:         "m19",
+		SetName:         "Core Set 2019",
+		CollectorNumber: "156",
+		LanguageCode:    "en",
+		Finish:          deck.FinishNonfoil,
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000004"),
+	},
+}
+
+func Test_Export(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "arena",
+			expected: `Commander
+1 Zurgo Bellstriker (DTK) 169
+
+Deck
+4 Lightning Bolt (M10) 146
+
+Sideboard
+2 Fire // Ice (MH2) 290
+`,
+		},
+		{
+			format: "mtgo",
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<Deck xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <NetDeckID>0</NetDeckID>
+  <PreconstructedDeckID>0</PreconstructedDeckID>
+  <Cards CatID="55000" Quantity="1" Sideboard="true" Name="Zurgo Bellstriker" Annotation="0"></Cards>
+  <Cards CatID="31000" Quantity="3" Sideboard="false" Name="Lightning Bolt" Annotation="0"></Cards>
+  <Cards CatID="31001" Quantity="1" Sideboard="false" Name="Lightning Bolt" Annotation="0"></Cards>
+  <Cards Quantity="2" Sideboard="true" Name="Fire // Ice" Annotation="0"></Cards>
+</Deck>
+`,
+		},
+		{
+			format: "moxfield",
+			expected: `Count,Tradelist Count,Name,Edition,Condition,Language,Foil,Tags,Last Modified,Collector Number,Alter,Proxy,Purchase Price
+1,0,Zurgo Bellstriker,dtk,Near Mint,English,,commander,,169,False,False,
+3,0,Lightning Bolt,m10,Near Mint,Spanish,,main,,146,False,False,
+1,0,Lightning Bolt,m10,Near Mint,Spanish,foil,main,,146,False,False,
+2,0,Fire // Ice,mh2,Near Mint,English,etched,sideboard,,290,False,False,
+1,0,Shock,m19,Near Mint,English,,maybeboard,,156,False,False,
+`,
+		},
+		{
+			format: "csv",
+			expected: `quantity,name,set_code,set_name,collector_number,language,finish,condition,zone,scryfall_id
+1,Zurgo Bellstriker,dtk,Dragons of Tarkir,169,en,nonfoil,,commander,00000000-0000-4000-8000-000000000001
+3,Lightning Bolt,m10,Magic 2010,146,es,nonfoil,,main,00000000-0000-4000-8000-000000000002
+1,Lightning Bolt,m10,Magic 2010,146,es,foil,,main,00000000-0000-4000-8000-000000000002
+2,Fire // Ice,mh2,Modern Horizons 2,290,en,etched,,sideboard,00000000-0000-4000-8000-000000000003
+1,Shock,m19,Core Set 2019,156,en,nonfoil,,maybeboard,00000000-0000-4000-8000-000000000004
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Export(&out, test.format, testEntries); err != nil {
+				t.Fatalf("exporting failed with error %v", err)
+			}
+
+			if out.String() != test.expected {
+				t.Fatalf("expected\n%s\nbut got\n%s", test.expected, out.String())
+			}
+		})
+	}
+}
+
+func Test_ExportUnknownFormat(t *testing.T) {
+	var formatErr *UnknownExportFormatError
+	if err := Export(&bytes.Buffer{}, "cockatrice", testEntries); !errors.As(err, &formatErr) {
+		t.Fatalf("expected UnknownExportFormatError but got %v", err)
+	}
+}
+
+func Test_CollectionEntries(t *testing.T) {
+	items := []sqlc.GetCollectionItemsWithCardsRow{{
+		CollectionItem: sqlc.CollectionItem{Quantity: 2, Finish: deck.FinishFoil, Condition: "LP", LanguageCode: "es"},
+		Card: sqlc.Card{
+			ScryfallID:      pgtype.UUID{Bytes: uuid.MustParse("00000000-0000-4000-8000-000000000002"), Valid: true},
+			Name:            "Lightning Bolt",
+			CollectorNumber: "146",
+			LanguageCode:    "en",
+			Raw:             `{"mtgo_id": 31000, "mtgo_foil_id": 31001}`,
+		},
+		SetCode: "m10",
+		SetName: "Magic 2010",
+	}}
+
+	entries := CollectionEntries(items)
+	expected := Entry{
+		Quantity:        2,
+		Name:            "Lightning Bolt",
+		SetCode:         "m10",
+		SetName:         "Magic 2010",
+		CollectorNumber: "146",
+		LanguageCode:    "es",
+		Finish:          deck.FinishFoil,
+		Condition:       "LP",
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000002"),
+		MTGOID:          31001,
+	}
+	if len(entries) != 1 || entries[0] != expected {
+		t.Fatalf("expected %+v but got %+v", expected, entries)
+	}
+
+	deckEntries := DeckEntries([]db.DeckEntry{{
+		DeckCard: sqlc.DeckCard{Zone: deck.ZoneSideboard, Quantity: 1, Finish: deck.FinishNonfoil},
+		Card:     db.CardWithSet{Card: items[0].Card, SetCode: "m10"},
+	}})
+	if len(deckEntries) != 1 || deckEntries[0].Zone != deck.ZoneSideboard || deckEntries[0].MTGOID != 31000 {
+		t.Fatalf("expected a nonfoil sideboard entry, got %+v", deckEntries)
+	}
+}
@@ -0,0 +1,63 @@
+package interop
+
+import (
+	"context"
+
+	"FedeAbella/mtgdb/internal/db"
+)
+
+// RowMatch is a vendor row matched against the printings. Rows that only
+// resolved to a card by name are left unmatched, since a collection needs the
+// exact printing.
+type RowMatch struct {
+	Row        Row
+	Resolution db.Resolution
+}
+
+func (m RowMatch) Matched() bool {
+	return m.Resolution.Resolved() && m.Resolution.Method != db.MatchName
+}
+
+// ImportCollection matches the rows of a vendor export and, unless dryRun,
+// adds the matched ones to the collection in a single transaction. The
+// matches are returned in the order of the rows, unmatched ones included.
+func ImportCollection(
+	ctx context.Context,
+	collections *db.Collections,
+	collectionName string,
+	export VendorExport,
+	dryRun bool,
+) ([]RowMatch, error) {
+	resolver := &db.Resolver{Store: collections.Store}
+
+	matches := make([]RowMatch, 0, len(export.Rows))
+	items := []db.NewCollectionItem{}
+	for _, row := range export.Rows {
+		resolution, err := resolver.ResolveRef(ctx, row.Ref)
+		if err != nil {
+			return nil, err
+		}
+
+		match := RowMatch{Row: row, Resolution: resolution}
+		matches = append(matches, match)
+		if !match.Matched() {
+			continue
+		}
+
+		items = append(items, db.NewCollectionItem{
+			ScryfallID:         resolution.Card.Card.ScryfallID,
+			Quantity:           int32(row.Quantity),
+			Finish:             row.Finish,
+			Condition:          row.Condition,
+			LanguageCode:       row.Ref.LanguageCode,
+			AcquiredPriceCents: row.PriceCents,
+			AcquiredCurrency:   row.Currency,
+		})
+	}
+
+	if dryRun {
+		return matches, nil
+	}
+
+	return matches, collections.AddAll(ctx, collectionName, items)
+}
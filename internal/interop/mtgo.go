@@ -0,0 +1,61 @@
+package interop
+
+import (
+	"encoding/xml"
+	"io"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+type mtgoDeck struct {
+	XMLName              xml.Name   `xml:"Deck"`
+	XSD                  string     `xml:"xmlns:xsd,attr"`
+	XSI                  string     `xml:"xmlns:xsi,attr"`
+	NetDeckID            int        `xml:"NetDeckID"`
+	PreconstructedDeckID int        `xml:"PreconstructedDeckID"`
+	Cards                []mtgoCard `xml:"Cards"`
+}
+
+type mtgoCard struct {
+	CatID      int    `xml:"CatID,attr,omitempty"`
+	Quantity   int    `xml:"Quantity,attr"`
+	Sideboard  bool   `xml:"Sideboard,attr"`
+	Name       string `xml:"Name,attr"`
+	Annotation int    `xml:"Annotation,attr"`
+}
+
+// WriteMTGO renders the entries as an MTGO .dek file. Printings not on MTGO
+// are left for MTGO to match by name. Commanders and companions go in the
+// sideboard as MTGO expects, and the maybeboard is left out.
+func WriteMTGO(w io.Writer, entries []Entry) error {
+	dek := mtgoDeck{
+		XSD:   "http://www.w3.org/2001/XMLSchema",
+		XSI:   "http://www.w3.org/2001/XMLSchema-instance",
+		Cards: []mtgoCard{},
+	}
+	for _, entry := range entries {
+		if entry.Zone == deck.ZoneMaybeboard {
+			continue
+		}
+
+		dek.Cards = append(dek.Cards, mtgoCard{
+			CatID:     entry.MTGOID,
+			Quantity:  entry.Quantity,
+			Sideboard: entry.Zone != deck.ZoneMain && entry.Zone != "",
+			Name:      entry.Name,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(dek); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,40 @@
+package interop
+
+// languageNames are the names vendors use for Scryfall's language codes.
+var languageNames = map[string]string{
+	"en":  "English",
+	"es":  "Spanish",
+	"fr":  "French",
+	"de":  "German",
+	"it":  "Italian",
+	"pt":  "Portuguese",
+	"ja":  "Japanese",
+	"ko":  "Korean",
+	"ru":  "Russian",
+	"zhs": "Chinese Simplified",
+	"zht": "Chinese Traditional",
+	"he":  "Hebrew",
+	"la":  "Latin",
+	"grc": "Ancient Greek",
+	"ar":  "Arabic",
+	"sa":  "Sanskrit",
+	"ph":  "Phyrexian",
+}
+
+// conditionNames are the names vendors use for our condition codes.
+var conditionNames = map[string]string{
+	"M":   "Mint",
+	"NM":  "Near Mint",
+	"LP":  "Lightly Played",
+	"MP":  "Moderately Played",
+	"HP":  "Heavily Played",
+	"DMG": "Damaged",
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+
+	return code
+}
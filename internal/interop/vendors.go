@@ -0,0 +1,484 @@
+package interop
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+// Row is a row of a vendor's collection export, in our codes.
+type Row struct {
+	// Number is the 1-based row in the CSV, the header being row 1
+	Number     int
+	Quantity   int
+	Ref        db.CardRef
+	Finish     string
+	Condition  string
+	PriceCents pgtype.Int8
+	Currency   pgtype.Text
+}
+
+type InvalidRow struct {
+	Number int
+	Reason string
+}
+
+// VendorExport is a parsed collection export. Invalid holds the rows that
+// couldn't be read, such as those with an unknown condition.
+type VendorExport struct {
+	Rows    []Row
+	Invalid []InvalidRow
+}
+
+// columns maps the header of a CSV to column indexes.
+type columns map[string]int
+
+// get returns the value of the first of names the CSV has, empty if it has
+// none of them.
+func (c columns) get(record []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := c[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+	}
+
+	return ""
+}
+
+func (c columns) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := c[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+type vendor struct {
+	// required lists the columns a row can't be read without, each as the
+	// names vendors have used for it
+	required [][]string
+	row      func(c columns, record []string) (Row, error)
+}
+
+var vendors = map[string]vendor{
+	"deckbox": {
+		required: [][]string{{"Count"}, {"Name"}},
+		row:      deckboxRow,
+	},
+	"tcgplayer": {
+		required: [][]string{{"Quantity"}, {"Name", "Simple Name"}},
+		row:      tcgplayerRow,
+	},
+	"cardmarket": {
+		required: [][]string{{"Amount", "Quantity"}, {"Name"}},
+		row:      cardmarketRow,
+	},
+	"manabox": {
+		required: [][]string{{"Quantity"}, {"Name"}},
+		row:      manaboxRow,
+	},
+}
+
+func Vendors() []string {
+	return slices.Sorted(maps.Keys(vendors))
+}
+
+type UnknownVendorError struct {
+	Vendor string
+}
+
+func (e *UnknownVendorError) Error() string {
+	return fmt.Sprintf("unknown vendor %q, expected one of %s", e.Vendor, strings.Join(Vendors(), ", "))
+}
+
+type MissingColumnError struct {
+	Vendor string
+	Column string
+}
+
+func (e *MissingColumnError) Error() string {
+	return fmt.Sprintf("%s export has no %q column", e.Vendor, e.Column)
+}
+
+// ReadCollection reads a collection exported as CSV by vendorName. Both comma
+// and semicolon separated files are read, as Cardmarket uses either.
+func ReadCollection(r io.Reader, vendorName string) (VendorExport, error) {
+	v, ok := vendors[vendorName]
+	if !ok {
+		return VendorExport{}, &UnknownVendorError{Vendor: vendorName}
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return VendorExport{}, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return VendorExport{}, &MissingColumnError{Vendor: vendorName, Column: v.required[0][0]}
+	}
+	if err != nil {
+		return VendorExport{}, err
+	}
+
+	c := columns{}
+	for i, name := range header {
+		c[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, names := range v.required {
+		if !c.has(names...) {
+			return VendorExport{}, &MissingColumnError{Vendor: vendorName, Column: names[0]}
+		}
+	}
+
+	var export VendorExport
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return VendorExport{}, err
+		}
+
+		row, err := v.row(c, record)
+		if err != nil {
+			export.Invalid = append(export.Invalid, InvalidRow{Number: number, Reason: err.Error()})
+			continue
+		}
+
+		row.Number = number
+		export.Rows = append(export.Rows, row)
+	}
+
+	return export, nil
+}
+
+func parseQuantity(value string) (int, error) {
+	quantity, err := strconv.Atoi(value)
+	if err != nil || quantity <= 0 {
+		return 0, fmt.Errorf("invalid quantity %q", value)
+	}
+
+	return quantity, nil
+}
+
+// parsePrice reads amounts such as "1.50", "$1.50" or "1,50 €" as cents, in
+// currency or in fallback if currency is empty.
+func parsePrice(value string, currency string, fallback string) (pgtype.Int8, pgtype.Text, error) {
+	value = strings.Trim(value, " $€")
+	if value == "" {
+		return pgtype.Int8{}, pgtype.Text{}, nil
+	}
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return pgtype.Int8{}, pgtype.Text{}, fmt.Errorf("invalid price %q", value)
+	}
+
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = fallback
+	}
+	if !slices.Contains(db.Currencies, currency) {
+		return pgtype.Int8{}, pgtype.Text{}, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	return pgtype.Int8{Int64: int64(math.Round(amount * 100)), Valid: true}, pgtype.Text{String: currency, Valid: true}, nil
+}
+
+// languageAliases are language names vendors use besides languageNames.
+var languageAliases = map[string]string{
+	"simplified chinese":    "zhs",
+	"traditional chinese":   "zht",
+	"chinese (simplified)":  "zhs",
+	"chinese (traditional)": "zht",
+	"chinese":               "zhs",
+	"portuguese (brazil)":   "pt",
+}
+
+// languageCode maps a language code or name to Scryfall's code, empty if value
+// is empty so the printing's language applies.
+func languageCode(value string) (string, error) {
+	lower := strings.ToLower(value)
+	if lower == "" {
+		return "", nil
+	}
+	if _, ok := languageNames[lower]; ok {
+		return lower, nil
+	}
+	for code, name := range languageNames {
+		if strings.ToLower(name) == lower {
+			return code, nil
+		}
+	}
+	if code, ok := languageAliases[lower]; ok {
+		return code, nil
+	}
+
+	return "", fmt.Errorf("unknown language %q", value)
+}
+
+func conditionCode(conditions map[string]string, value string) (string, error) {
+	if value == "" {
+		return "NM", nil
+	}
+
+	condition, ok := conditions[strings.ToLower(value)]
+	if !ok {
+		return "", fmt.Errorf("unknown condition %q", value)
+	}
+
+	return condition, nil
+}
+
+var deckboxConditions = map[string]string{
+	"mint":                  "M",
+	"near mint":             "NM",
+	"good (lightly played)": "LP",
+	"played":                "MP",
+	"heavily played":        "HP",
+	"poor":                  "DMG",
+}
+
+func deckboxRow(c columns, record []string) (Row, error) {
+	row := Row{
+		Ref: db.CardRef{
+			Name:            c.get(record, "Name"),
+			SetCode:         c.get(record, "Edition Code"),
+			SetName:         c.get(record, "Edition"),
+			CollectorNumber: c.get(record, "Card Number"),
+		},
+		Finish: deck.FinishNonfoil,
+	}
+
+	var err error
+	if row.Quantity, err = parseQuantity(c.get(record, "Count")); err != nil {
+		return row, err
+	}
+	if row.Ref.LanguageCode, err = languageCode(c.get(record, "Language")); err != nil {
+		return row, err
+	}
+	if row.Condition, err = conditionCode(deckboxConditions, c.get(record, "Condition")); err != nil {
+		return row, err
+	}
+	switch strings.ToLower(c.get(record, "Foil")) {
+	case "foil":
+		row.Finish = deck.FinishFoil
+	case "etched":
+		row.Finish = deck.FinishEtched
+	}
+	if row.PriceCents, row.Currency, err = parsePrice(c.get(record, "My Price"), "", "USD"); err != nil {
+		return row, err
+	}
+
+	return row, nil
+}
+
+var tcgplayerConditions = map[string]string{
+	"near mint":         "NM",
+	"lightly played":    "LP",
+	"moderately played": "MP",
+	"heavily played":    "HP",
+	"damaged":           "DMG",
+}
+
+func tcgplayerRow(c columns, record []string) (Row, error) {
+	row := Row{
+		Ref: db.CardRef{
+			// The plain name lacks suffixes such as "(Extended Art)"
+			Name:            c.get(record, "Simple Name", "Name"),
+			SetCode:         c.get(record, "Set Code"),
+			SetName:         c.get(record, "Set"),
+			CollectorNumber: c.get(record, "Card Number"),
+		},
+		Finish: deck.FinishNonfoil,
+	}
+
+	var err error
+	if row.Quantity, err = parseQuantity(c.get(record, "Quantity")); err != nil {
+		return row, err
+	}
+	if row.Ref.LanguageCode, err = languageCode(c.get(record, "Language")); err != nil {
+		return row, err
+	}
+
+	// Conditions carry the printing too, as in "Near Mint Foil"
+	condition := c.get(record, "Condition")
+	for _, finish := range []string{deck.FinishFoil, deck.FinishEtched} {
+		if trimmed, ok := strings.CutSuffix(strings.ToLower(condition), " "+finish); ok {
+			condition = trimmed
+			row.Finish = finish
+		}
+	}
+	if row.Condition, err = conditionCode(tcgplayerConditions, condition); err != nil {
+		return row, err
+	}
+
+	printing := strings.ToLower(c.get(record, "Printing"))
+	switch {
+	case strings.Contains(printing, "etched"):
+		row.Finish = deck.FinishEtched
+	case strings.Contains(printing, "foil"):
+		row.Finish = deck.FinishFoil
+	}
+
+	return row, nil
+}
+
+var cardmarketConditions = map[string]string{
+	"mt": "M",
+	"nm": "NM",
+	"ex": "LP",
+	"gd": "MP",
+	"lp": "MP",
+	"pl": "HP",
+	"po": "DMG",
+}
+
+// cardmarketLanguages are Cardmarket's language ids.
+var cardmarketLanguages = map[string]string{
+	"1":  "en",
+	"2":  "fr",
+	"3":  "de",
+	"4":  "es",
+	"5":  "it",
+	"6":  "zhs",
+	"7":  "ja",
+	"8":  "pt",
+	"9":  "ru",
+	"10": "ko",
+	"11": "zht",
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "x", "true", "yes", "foil":
+		return true
+	}
+
+	return false
+}
+
+func cardmarketRow(c columns, record []string) (Row, error) {
+	row := Row{
+		Ref: db.CardRef{
+			Name: c.get(record, "Name"),
+			// Cardmarket's expansion codes are often not Scryfall's, so the
+			// expansion name backs them up
+			SetCode:         c.get(record, "Set Code", "Exp."),
+			SetName:         c.get(record, "Exp. Name", "Set", "Expansion"),
+			CollectorNumber: c.get(record, "Collector Number", "Number"),
+		},
+		Finish: deck.FinishNonfoil,
+	}
+
+	var err error
+	if row.Quantity, err = parseQuantity(c.get(record, "Amount", "Quantity")); err != nil {
+		return row, err
+	}
+
+	language := c.get(record, "Language")
+	if code, ok := cardmarketLanguages[language]; ok {
+		row.Ref.LanguageCode = code
+	} else if row.Ref.LanguageCode, err = languageCode(language); err != nil {
+		return row, err
+	}
+
+	if row.Condition, err = conditionCode(cardmarketConditions, c.get(record, "Condition")); err != nil {
+		return row, err
+	}
+	if isTrue(c.get(record, "isFoil", "Foil")) {
+		row.Finish = deck.FinishFoil
+	}
+	row.PriceCents, row.Currency, err = parsePrice(c.get(record, "Price"), c.get(record, "Currency Code"), "EUR")
+	if err != nil {
+		return row, err
+	}
+
+	return row, nil
+}
+
+var manaboxConditions = map[string]string{
+	"mint":              "M",
+	"near_mint":         "NM",
+	"excellent":         "LP",
+	"good":              "MP",
+	"lightly_played":    "LP",
+	"moderately_played": "MP",
+	"played":            "HP",
+	"heavily_played":    "HP",
+	"poor":              "DMG",
+	"damaged":           "DMG",
+}
+
+func manaboxRow(c columns, record []string) (Row, error) {
+	row := Row{
+		Ref: db.CardRef{
+			Name:            c.get(record, "Name"),
+			SetCode:         c.get(record, "Set code"),
+			SetName:         c.get(record, "Set name"),
+			CollectorNumber: c.get(record, "Collector number"),
+		},
+		Finish: deck.FinishNonfoil,
+	}
+
+	var err error
+	if row.Quantity, err = parseQuantity(c.get(record, "Quantity")); err != nil {
+		return row, err
+	}
+
+	if value := c.get(record, "Scryfall ID"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return row, fmt.Errorf("invalid scryfall id %q", value)
+		}
+		row.Ref.ScryfallID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	if row.Ref.LanguageCode, err = languageCode(c.get(record, "Language")); err != nil {
+		return row, err
+	}
+	if row.Condition, err = conditionCode(manaboxConditions, c.get(record, "Condition")); err != nil {
+		return row, err
+	}
+	switch finish := strings.ToLower(c.get(record, "Foil")); finish {
+	case deck.FinishFoil, deck.FinishEtched:
+		row.Finish = finish
+	}
+	row.PriceCents, row.Currency, err = parsePrice(
+		c.get(record, "Purchase price"),
+		c.get(record, "Purchase price currency"),
+		"USD",
+	)
+	if err != nil {
+		return row, err
+	}
+
+	return row, nil
+}
@@ -0,0 +1,193 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/dbtest"
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+const testBoltID = "00000000-0000-4000-8000-000000000146"
+
+func usd(cents int64) (pgtype.Int8, pgtype.Text) {
+	return pgtype.Int8{Int64: cents, Valid: true}, pgtype.Text{String: "USD", Valid: true}
+}
+
+func Test_ReadCollection(t *testing.T) {
+	price, dollars := usd(150)
+	euroPrice := pgtype.Int8{Int64: 120, Valid: true}
+	euros := pgtype.Text{String: "EUR", Valid: true}
+
+	tests := []struct {
+		vendor          string
+		csv             string
+		expectedRows    []Row
+		expectedInvalid []InvalidRow
+	}{
+		{
+			vendor: "deckbox",
+			csv: "\ufeffCount,Tradelist Count,Name,Edition,Card Number,Condition,Language,Foil,Signed,My Price\n" +
+				"2,0,Lightning Bolt,Magic 2010,146,Good (Lightly Played),Spanish,foil,,$1.50\n" +
+				"1,0,Shock,Core Set 2019,156,Like New,English,,,\n",
+			expectedRows: []Row{{
+				Number:     2,
+				Quantity:   2,
+				Ref:        db.CardRef{Name: "Lightning Bolt", SetName: "Magic 2010", CollectorNumber: "146", LanguageCode: "es"},
+				Finish:     deck.FinishFoil,
+				Condition:  "LP",
+				PriceCents: price,
+				Currency:   dollars,
+			}},
+			expectedInvalid: []InvalidRow{{Number: 3, Reason: `unknown condition "Like New"`}},
+		},
+		{
+			vendor: "tcgplayer",
+			csv: "Quantity,Name,Simple Name,Set,Card Number,Set Code,Printing,Condition,Language,Rarity\n" +
+				"3,Lightning Bolt (Borderless),Lightning Bolt,Magic 2010,146,M10,Normal,Near Mint Foil,English,Common\n" +
+				"x,Shock,Shock,Core Set 2019,156,M19,Normal,Near Mint,English,Common\n",
+			expectedRows: []Row{{
+				Number:    2,
+				Quantity:  3,
+				Ref:       db.CardRef{Name: "Lightning Bolt", SetCode: "M10", SetName: "Magic 2010", CollectorNumber: "146", LanguageCode: "en"},
+				Finish:    deck.FinishFoil,
+				Condition: "NM",
+			}},
+			expectedInvalid: []InvalidRow{{Number: 3, Reason: `invalid quantity "x"`}},
+		},
+		{
+			vendor: "cardmarket",
+			csv: "idProduct;Name;Exp.;Exp. Name;Language;Condition;isFoil;Amount;Price\n" +
+				"1234;Lightning Bolt;M10;Magic 2010;4;EX;1;1;1,20\n",
+			expectedRows: []Row{{
+				Number:     2,
+				Quantity:   1,
+				Ref:        db.CardRef{Name: "Lightning Bolt", SetCode: "M10", SetName: "Magic 2010", LanguageCode: "es"},
+				Finish:     deck.FinishFoil,
+				Condition:  "LP",
+				PriceCents: euroPrice,
+				Currency:   euros,
+			}},
+		},
+		{
+			vendor: "manabox",
+			csv: "Name,Set code,Set name,Collector number,Foil,Rarity,Quantity,ManaBox ID,Scryfall ID,Purchase price,Misprint,Altered,Condition,Language,Purchase price currency\n" +
+				"Lightning Bolt,m10,Magic 2010,146,etched,common,4,1,00000000-0000-4000-8000-000000000146,1.50,false,false,near_mint,en,USD\n" +
+				"Shock,m19,Core Set 2019,156,normal,common,1,2,,0.10,false,false,near_mint,en,GBP\n",
+			expectedRows: []Row{{
+				Number:   2,
+				Quantity: 4,
+				Ref: db.CardRef{
+					ScryfallID:      pgtype.UUID{Bytes: uuid.MustParse(testBoltID), Valid: true},
+					Name:            "Lightning Bolt",
+					SetCode:         "m10",
+					SetName:         "Magic 2010",
+					CollectorNumber: "146",
+					LanguageCode:    "en",
+				},
+				Finish:     deck.FinishEtched,
+				Condition:  "NM",
+				PriceCents: price,
+				Currency:   dollars,
+			}},
+			expectedInvalid: []InvalidRow{{Number: 3, Reason: `unsupported currency "GBP"`}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.vendor, func(t *testing.T) {
+			export, err := ReadCollection(strings.NewReader(test.csv), test.vendor)
+			if err != nil {
+				t.Fatalf("reading failed with error %v", err)
+			}
+
+			if !reflect.DeepEqual(export.Rows, test.expectedRows) {
+				t.Fatalf("expected rows %+v but got %+v", test.expectedRows, export.Rows)
+			}
+			if !reflect.DeepEqual(export.Invalid, test.expectedInvalid) {
+				t.Fatalf("expected invalid rows %+v but got %+v", test.expectedInvalid, export.Invalid)
+			}
+		})
+	}
+}
+
+func Test_ReadCollectionErrors(t *testing.T) {
+	var vendorErr *UnknownVendorError
+	if _, err := ReadCollection(strings.NewReader(""), "echomtg"); !errors.As(err, &vendorErr) {
+		t.Fatalf("expected UnknownVendorError but got %v", err)
+	}
+
+	var columnErr *MissingColumnError
+	if _, err := ReadCollection(strings.NewReader("Name,Set\nShock,M19\n"), "deckbox"); !errors.As(err, &columnErr) {
+		t.Fatalf("expected MissingColumnError but got %v", err)
+	}
+}
+
+func newTestCollections(t *testing.T) *db.Collections {
+	t.Helper()
+	store := dbtest.NewSqliteStore(t)
+
+	card := dbtest.NewCard("Lightning Bolt", "146")
+	card.ScryfallID = pgtype.UUID{Bytes: uuid.MustParse(testBoltID), Valid: true}
+	dbtest.Seed(t, store, dbtest.NewSet("m10", "Magic 2010"), card)
+
+	collections := &db.Collections{Store: store.(db.CollectionStore)}
+	if _, err := collections.Create(context.Background(), "binder"); err != nil {
+		t.Fatalf("creating collection failed with error %v", err)
+	}
+
+	return collections
+}
+
+func Test_ImportCollection(t *testing.T) {
+	ctx := context.Background()
+	collections := newTestCollections(t)
+
+	// Matched by scryfall id, by set name, by set code and number, by name only
+	// and not at all
+	boltID := pgtype.UUID{Bytes: uuid.MustParse(testBoltID), Valid: true}
+	export := VendorExport{Rows: []Row{
+		{Number: 1, Quantity: 1, Ref: db.CardRef{ScryfallID: boltID, Name: "Bolt"}, Finish: deck.FinishNonfoil, Condition: "NM"},
+		{Number: 2, Quantity: 2, Ref: db.CardRef{Name: "Lightning Bolt", SetName: "Magic 2010", LanguageCode: "es"}, Finish: deck.FinishFoil, Condition: "LP"},
+		{Number: 3, Quantity: 1, Ref: db.CardRef{Name: "Lightning Bolt", SetCode: "M10", CollectorNumber: "146"}, Finish: deck.FinishNonfoil, Condition: "NM"},
+		{Number: 4, Quantity: 1, Ref: db.CardRef{Name: "Lightning Bolt", SetCode: "2XM"}, Finish: deck.FinishNonfoil, Condition: "NM"},
+		{Number: 5, Quantity: 1, Ref: db.CardRef{Name: "Counterspell"}, Finish: deck.FinishNonfoil, Condition: "NM"},
+	}}
+
+	matches, err := ImportCollection(ctx, collections, "binder", export, true)
+	if err != nil {
+		t.Fatalf("dry run failed with error %v", err)
+	}
+	got := []string{}
+	for _, match := range matches {
+		got = append(got, match.Resolution.Method)
+	}
+	expected := []string{db.MatchScryfallID, db.MatchSet, db.MatchPrinting, db.MatchName, ""}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected matches %q but got %q", expected, got)
+	}
+	if matches[3].Matched() || matches[4].Matched() {
+		t.Fatalf("expected rows without a printing to be unmatched")
+	}
+	if items, _ := collections.List(ctx, "binder"); len(items) != 0 {
+		t.Fatalf("expected a dry run to add nothing, got %+v", items)
+	}
+
+	if _, err := ImportCollection(ctx, collections, "binder", export, false); err != nil {
+		t.Fatalf("import failed with error %v", err)
+	}
+	items, err := collections.List(ctx, "binder")
+	if err != nil {
+		t.Fatalf("listing collection failed with error %v", err)
+	}
+	if len(items) != 3 || items[1].CollectionItem.LanguageCode != "es" || items[2].CollectionItem.LanguageCode != "en" {
+		t.Fatalf("expected two English lots and a Spanish one, got %+v", items)
+	}
+}
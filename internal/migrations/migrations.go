@@ -0,0 +1,130 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+
+	"github.com/pressly/goose/v3"
+
+	mtgsql "FedeAbella/mtgdb/sql"
+)
+
+type OutdatedSchemaError struct {
+	Current int64
+	Target  int64
+}
+
+func (e *OutdatedSchemaError) Error() string {
+	return fmt.Sprintf(
+		"db schema is at version %d but version %d is required, run migrate up first",
+		e.Current,
+		e.Target,
+	)
+}
+
+type dialect struct {
+	goose goose.Dialect
+	dir   string
+}
+
+// Dialects are keyed by database/sql driver name.
+var dialects = map[string]dialect{
+	"pgx":    {goose: goose.DialectPostgres, dir: mtgsql.MigrationsDir},
+	"sqlite": {goose: goose.DialectSQLite3, dir: mtgsql.SqliteMigrationsDir},
+}
+
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	dialect, ok := dialects[driver]
+	if !ok {
+		err := fmt.Errorf("no migrations for driver %q", driver)
+		log.Println(err)
+		return nil, err
+	}
+
+	fsys, err := fs.Sub(mtgsql.Migrations, dialect.dir)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(dialect.goose, db, fsys)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	for _, result := range results {
+		log.Printf("applied migration %s in %s", result.Source.Path, result.Duration)
+	}
+	log.Printf("%d migrations applied", len(results))
+
+	return nil
+}
+
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	log.Printf("rolled back migration %s in %s", result.Source.Path, result.Duration)
+
+	return nil
+}
+
+func (m *Migrator) Reset(ctx context.Context) error {
+	results, err := m.provider.DownTo(ctx, 0)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	for _, result := range results {
+		log.Printf("rolled back migration %s in %s", result.Source.Path, result.Duration)
+	}
+	log.Printf("%d migrations rolled back", len(results))
+
+	return nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if current < target {
+		return &OutdatedSchemaError{Current: current, Target: target}
+	}
+
+	return nil
+}
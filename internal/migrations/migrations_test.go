@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+func Test_EmbeddedMigrations(t *testing.T) {
+	tests := []struct {
+		driver string
+		dsn    string
+		dir    string
+	}{
+		// The pool is opened lazily, so listing sources never connects
+		{driver: "pgx", dsn: "postgres://localhost:1/mtgdb", dir: filepath.Join("..", "..", "sql", "schema")},
+		{driver: "sqlite", dsn: ":memory:", dir: filepath.Join("..", "..", "sql", "sqlite", "schema")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			sqlDb, err := sql.Open(test.driver, test.dsn)
+			if err != nil {
+				t.Fatalf("opening db handle failed with error %v", err)
+			}
+			defer sqlDb.Close()
+
+			migrator, err := New(sqlDb, test.driver)
+			if err != nil {
+				t.Fatalf("creating migrator failed with error %v", err)
+			}
+
+			files, err := filepath.Glob(filepath.Join(test.dir, "*.sql"))
+			if err != nil {
+				t.Fatalf("listing schema files failed with error %v", err)
+			}
+
+			sources := migrator.provider.ListSources()
+			if len(sources) != len(files) {
+				t.Fatalf("expected %d embedded migrations but got %d", len(files), len(sources))
+			}
+
+			for i, source := range sources {
+				if source.Version != int64(i+1) {
+					t.Fatalf("expected migration %s to have version %d but got %d", source.Path, i+1, source.Version)
+				}
+				if _, err := os.Stat(filepath.Join(test.dir, source.Path)); err != nil {
+					t.Fatalf("embedded migration %s not found in %s: %v", source.Path, test.dir, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_SqliteMigrationsUpAndDown(t *testing.T) {
+	ctx := context.Background()
+
+	sqlDb, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cards.db"))
+	if err != nil {
+		t.Fatalf("opening db handle failed with error %v", err)
+	}
+	defer sqlDb.Close()
+
+	migrator, err := New(sqlDb, "sqlite")
+	if err != nil {
+		t.Fatalf("creating migrator failed with error %v", err)
+	}
+
+	if err := migrator.CheckCurrent(ctx); err == nil {
+		t.Fatalf("expected an outdated schema before migrating")
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating up failed with error %v", err)
+	}
+
+	if err := migrator.CheckCurrent(ctx); err != nil {
+		t.Fatalf("expected a current schema after migrating but got %v", err)
+	}
+
+	if err := migrator.Reset(ctx); err != nil {
+		t.Fatalf("resetting failed with error %v", err)
+	}
+}
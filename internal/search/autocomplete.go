@@ -0,0 +1,77 @@
+package search
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// IndexedName is a card name to complete, in English or Spanish.
+type IndexedName struct {
+	Name     string
+	Language string
+	OracleID uuid.UUID
+}
+
+type indexEntry struct {
+	key string
+	IndexedName
+}
+
+// NameIndex completes card name prefixes, ignoring case and accents. It is a
+// slice sorted by normalized name, so a lookup is a binary search to the
+// first match followed by a scan of the next n entries.
+type NameIndex struct {
+	entries []indexEntry
+}
+
+func NewNameIndex(names []IndexedName) *NameIndex {
+	entries := make([]indexEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, indexEntry{key: Normalize(name.Name), IndexedName: name})
+	}
+
+	slices.SortFunc(entries, func(a, b indexEntry) int {
+		return cmp.Or(
+			strings.Compare(a.key, b.key),
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Language, b.Language),
+			slices.Compare(a.OracleID[:], b.OracleID[:]),
+		)
+	})
+	entries = slices.CompactFunc(entries, func(a, b indexEntry) bool {
+		return a.IndexedName == b.IndexedName
+	})
+
+	return &NameIndex{entries: entries}
+}
+
+func (idx *NameIndex) Len() int {
+	return len(idx.entries)
+}
+
+// Complete returns up to n names starting with prefix, an exact match first
+// and the rest alphabetically.
+func (idx *NameIndex) Complete(prefix string, n int) []IndexedName {
+	key := Normalize(strings.TrimSpace(prefix))
+	if key == "" {
+		return []IndexedName{}
+	}
+
+	start := sort.Search(len(idx.entries), func(i int) bool {
+		return idx.entries[i].key >= key
+	})
+
+	names := []IndexedName{}
+	for _, entry := range idx.entries[start:] {
+		if len(names) == n || !strings.HasPrefix(entry.key, key) {
+			break
+		}
+		names = append(names, entry.IndexedName)
+	}
+
+	return names
+}
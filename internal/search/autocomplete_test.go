@@ -0,0 +1,51 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_NameIndexComplete(t *testing.T) {
+	bolt := uuid.New()
+	names := []IndexedName{
+		{Name: "Lightning Helix", Language: "en", OracleID: uuid.New()},
+		{Name: "Lightning Bolt", Language: "en", OracleID: bolt},
+		{Name: "Lightning Bolt", Language: "en", OracleID: bolt},
+		{Name: "Relámpago", Language: "es", OracleID: bolt},
+		{Name: "Light", Language: "en", OracleID: uuid.New()},
+		{Name: "Counterspell", Language: "en", OracleID: uuid.New()},
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		n        int
+		expected []string
+	}{
+		{name: "exact match first", prefix: "light", n: 10, expected: []string{"Light", "Lightning Bolt", "Lightning Helix"}},
+		{name: "capped at n", prefix: "LIGHTNING", n: 1, expected: []string{"Lightning Bolt"}},
+		{name: "spanish without accents", prefix: "relam", n: 10, expected: []string{"Relámpago"}},
+		{name: "no match", prefix: "zz", n: 10, expected: []string{}},
+		{name: "blank prefix", prefix: "  ", n: 10, expected: []string{}},
+	}
+
+	idx := NewNameIndex(names)
+	if idx.Len() != 5 {
+		t.Fatalf("expected duplicates to be dropped, got %d names", idx.Len())
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, name := range idx.Complete(test.prefix, test.n) {
+				got = append(got, name.Name)
+			}
+
+			if !slices.Equal(got, test.expected) {
+				t.Fatalf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// WordSimilarityThreshold matches the pg_trgm default for the <% operator.
+const WordSimilarityThreshold = 0.6
+
+// Normalize lowercases s and strips its accents, as unaccent does, so
+// "Jacé" and "jace" compare equal.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// trigramList splits s in words the way pg_trgm does, padding each with two
+// spaces before and one after, and returns their trigrams in order.
+func trigramList(s string) []string {
+	list := []string{}
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			list = append(list, string(padded[i:i+3]))
+		}
+	}
+
+	return list
+}
+
+func trigrams(s string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, trigram := range trigramList(s) {
+		set[trigram] = struct{}{}
+	}
+
+	return set
+}
+
+func shared(a, b map[string]struct{}) int {
+	count := 0
+	for trigram := range a {
+		if _, ok := b[trigram]; ok {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Similarity is the share of trigrams a and b have in common, as pg_trgm's
+// similarity.
+func Similarity(a, b string) float32 {
+	aTrigrams, bTrigrams := trigrams(a), trigrams(b)
+	common := shared(aTrigrams, bTrigrams)
+	total := len(aTrigrams) + len(bTrigrams) - common
+	if total == 0 {
+		return 0
+	}
+
+	return float32(common) / float32(total)
+}
+
+// WordSimilarity is the greatest similarity between the query's trigrams and
+// those of a contiguous extent of target's, so a query matching part of a
+// longer name still ranks high. It follows pg_trgm's word_similarity, trying
+// every extent that starts and ends on a trigram of the query.
+func WordSimilarity(query, target string) float32 {
+	queryTrigrams := trigrams(query)
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+
+	// Target trigrams are numbered by their first appearance, and found tells
+	// which of them are in the query
+	ids := map[string]int{}
+	found := []bool{}
+	targetIDs := []int{}
+	for _, trigram := range trigramList(target) {
+		id, ok := ids[trigram]
+		if !ok {
+			id = len(found)
+			ids[trigram] = id
+			_, inQuery := queryTrigrams[trigram]
+			found = append(found, inQuery)
+		}
+		targetIDs = append(targetIDs, id)
+	}
+
+	return maxExtentSimilarity(targetIDs, found, len(queryTrigrams))
+}
+
+func extentSimilarity(common int, queryCount int, extentCount int) float32 {
+	return float32(common) / float32(queryCount+extentCount-common)
+}
+
+// maxExtentSimilarity ports pg_trgm's iterate_word_similarity. Each trigram of
+// the query found in the target ends an extent, whose start is moved forward
+// while that raises its similarity.
+func maxExtentSimilarity(targetIDs []int, found []bool, queryCount int) float32 {
+	// The last position of each trigram in the current extent, -1 if none
+	lastPos := make([]int, len(found))
+	for i := range lastPos {
+		lastPos[i] = -1
+	}
+
+	var best float32
+	extentCount, common, lower := 0, 0, -1
+	for upper, id := range targetIDs {
+		if lower >= 0 || found[id] {
+			if lastPos[id] < 0 {
+				extentCount++
+				if found[id] {
+					common++
+				}
+			}
+			lastPos[id] = upper
+		}
+
+		if !found[id] {
+			continue
+		}
+
+		if lower == -1 {
+			lower = upper
+			extentCount = 1
+		}
+
+		current := extentSimilarity(common, queryCount, extentCount)
+
+		prevLower := lower
+		tmpCount, tmpCommon := extentCount, common
+		for tmpLower := lower; tmpLower <= upper; tmpLower++ {
+			if similarity := extentSimilarity(tmpCommon, queryCount, tmpCount); similarity > current {
+				current = similarity
+				extentCount, common, lower = tmpCount, tmpCommon, tmpLower
+			}
+
+			// Dropping the trigram at tmpLower leaves the extent without it
+			// only if it doesn't appear again later on
+			if tmpID := targetIDs[tmpLower]; lastPos[tmpID] == tmpLower {
+				tmpCount--
+				if found[tmpID] {
+					tmpCommon--
+				}
+			}
+		}
+
+		best = max(best, current)
+
+		for tmpLower := prevLower; tmpLower < lower; tmpLower++ {
+			if tmpID := targetIDs[tmpLower]; lastPos[tmpID] == tmpLower {
+				lastPos[tmpID] = -1
+			}
+		}
+	}
+
+	return best
+}
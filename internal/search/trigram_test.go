@@ -0,0 +1,122 @@
+package search
+
+import "testing"
+
+func Test_Normalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "Jace, el Escultor de Mentes", expected: "jace, el escultor de mentes"},
+		{input: "Ñamo Caótico", expected: "namo caotico"},
+		{input: "Lim-Dûl's Vault", expected: "lim-dul's vault"},
+		{input: "", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := Normalize(test.input); got != test.expected {
+				t.Fatalf("expected %q but got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_Similarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected float32
+	}{
+		// Values from the pg_trgm docs and psql
+		{name: "identical", a: "word", b: "word", expected: 1},
+		{name: "disjoint", a: "word", b: "xyz", expected: 0},
+		{name: "partial", a: "word", b: "two words", expected: 4.0 / 11.0},
+		{name: "empty", a: "", b: "", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Similarity(test.a, test.b); got != test.expected {
+				t.Fatalf("expected similarity %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_WordSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		target  string
+		atLeast float32
+		below   float32
+	}{
+		{name: "word in longer text", query: "word", target: "two words", atLeast: 0.8, below: 0.81},
+		{
+			name:    "spanish name without accents",
+			query:   Normalize("escultor mentes"),
+			target:  Normalize("Jace, el Escultor de Mentes"),
+			atLeast: WordSimilarityThreshold,
+			below:   1,
+		},
+		{
+			name:    "misspelled",
+			query:   Normalize("lightnig bolt"),
+			target:  Normalize("Lightning Bolt"),
+			atLeast: WordSimilarityThreshold,
+			below:   1,
+		},
+		{
+			name:    "english name for a spanish query",
+			query:   Normalize("jace mente"),
+			target:  Normalize("Jace, the Mind Sculptor"),
+			atLeast: 0,
+			below:   WordSimilarityThreshold,
+		},
+		{name: "empty query", query: "", target: "anything", atLeast: 0, below: 0.01},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := WordSimilarity(test.query, test.target)
+			if got < test.atLeast || got >= test.below {
+				t.Fatalf(
+					"expected word similarity of %q in %q in [%v, %v) but got %v",
+					test.query,
+					test.target,
+					test.atLeast,
+					test.below,
+					got,
+				)
+			}
+		})
+	}
+}
+
+func Test_WordSimilarityExtents(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		target   string
+		expected float32
+	}{
+		// From the pg_trgm docs, the best extent being "  w", " wo", "wor", "ord"
+		{name: "documented", query: "word", target: "two words", expected: 0.8},
+		// Traced through pg_trgm's iterate_word_similarity: the query's
+		// trigrams are all in the target, but not in a short extent
+		{name: "scattered trigrams", query: "jace", target: "jack lace", expected: 3.0 / 5.0},
+		{name: "words far apart", query: "jace mente", target: "jace, el escultor de mentes", expected: 5.0 / 11.0},
+		{name: "repeated trigrams", query: "abab", target: "ab abab", expected: 1},
+		{name: "no common trigrams", query: "word", target: "xyz", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := WordSimilarity(test.query, test.target); got != test.expected {
+				t.Fatalf("expected word similarity of %q in %q to be %v but got %v", test.query, test.target, test.expected, got)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ type CardPrinting struct {
 	Name             string
 	NameSPA          string
 	Rarity           string
+	Raw              string
+	RawHash          string
 	ScryfallAPIURI   string
 	ScryfallId       uuid.UUID
 	ScryfallOracleId uuid.UUID
@@ -25,7 +27,7 @@ type CardPrinting struct {
 	TypeLine         string
 }
 
-func (c *CardPrinting) Equals(dbCard *sqlc.Card) bool {
+func (c *CardPrinting) Equals(dbCard *sqlc.GetAllCardsRow) bool {
 	return c.CollectorNumber == dbCard.CollectorNumber &&
 		c.ColorIdentity == dbCard.ColorIdentity.String &&
 		c.Colors == dbCard.Colors.String &&
@@ -38,7 +40,8 @@ func (c *CardPrinting) Equals(dbCard *sqlc.Card) bool {
 		c.ScryfallOracleId == dbCard.ScryfallOracleID.Bytes &&
 		c.ScryfallWebURI == dbCard.ScryfallWebUri &&
 		c.SetScryfallId == dbCard.SetID.Bytes &&
-		c.TypeLine == dbCard.TypeLine
+		c.TypeLine == dbCard.TypeLine &&
+		c.RawHash == dbCard.RawHash
 }
 
 func (c *CardPrinting) ToDbInsertCard(now time.Time) sqlc.InsertCardsParams {
@@ -85,6 +88,8 @@ func (c *CardPrinting) ToDbInsertCard(now time.Time) sqlc.InsertCardsParams {
 			Time:  now,
 			Valid: true,
 		},
+		Raw:     c.Raw,
+		RawHash: c.RawHash,
 	}
 }
 
@@ -128,5 +133,7 @@ func (c *CardPrinting) ToDbUpdateCard(now time.Time) sqlc.UpdateCardParams {
 			Time:  now,
 			Valid: true,
 		},
+		Raw:     c.Raw,
+		RawHash: c.RawHash,
 	}
 }
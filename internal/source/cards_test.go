@@ -14,7 +14,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 	tests := []struct {
 		name          string
 		Printing      CardPrinting
-		SqlcCard      sqlc.Card
+		SqlcCard      sqlc.GetAllCardsRow
 		ExpectedEqual bool
 	}{
 		{
@@ -34,7 +34,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -89,7 +89,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "108",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -144,7 +144,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -199,7 +199,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -254,7 +254,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -309,7 +309,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -364,7 +364,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -419,7 +419,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -474,7 +474,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -529,7 +529,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -584,7 +584,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -639,7 +639,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -694,7 +694,7 @@ func Test_EqualsSqlcCard(t *testing.T) {
 				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
 				TypeLine:         "Sorcery",
 			},
-			SqlcCard: sqlc.Card{
+			SqlcCard: sqlc.GetAllCardsRow{
 				CollectorNumber: "107",
 				ColorIdentity: pgtype.Text{
 					String: "BGRUW",
@@ -732,6 +732,64 @@ func Test_EqualsSqlcCard(t *testing.T) {
 			},
 			ExpectedEqual: false,
 		},
+		{
+			name: "different raw json",
+			Printing: CardPrinting{
+				CollectorNumber:  "107",
+				ColorIdentity:    "BGRUW",
+				Colors:           "BGRUW",
+				Language:         Spanish,
+				Name:             "Last Stand",
+				NameSPA:          "Última Resistencia",
+				Rarity:           Rare,
+				Raw:              `{"name":"Last Stand","reprint":true}`,
+				RawHash:          "8c0b3c3d1d2b9f1e",
+				ScryfallAPIURI:   "https://api.scryfall.com/cards/47fee476-25b6-40bb-afa9-d755c9a021a5",
+				ScryfallId:       uuid.MustParse("47fee476-25b6-40bb-afa9-d755c9a021a5"),
+				ScryfallOracleId: uuid.MustParse("4d2a465e-9ebd-4002-b6cd-e0eab08bad54"),
+				ScryfallWebURI:   "https://scryfall.com/card/apc/107/es/ultima-resistencia-(last-stand)?utm_source=api",
+				SetScryfallId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
+				TypeLine:         "Sorcery",
+			},
+			SqlcCard: sqlc.GetAllCardsRow{
+				CollectorNumber: "107",
+				ColorIdentity: pgtype.Text{
+					String: "BGRUW",
+					Valid:  true,
+				},
+				Colors: pgtype.Text{
+					String: "BGRUW",
+					Valid:  true,
+				},
+				LanguageCode: Spanish,
+				Name:         "Last Stand",
+				SpanishName: pgtype.Text{
+					String: "Última Resistencia",
+					Valid:  true,
+				},
+				Rarity: pgtype.Text{
+					String: Rare,
+					Valid:  true,
+				},
+				RawHash:        "0f4e2a6c9b7d5e3a",
+				ScryfallApiUri: "https://api.scryfall.com/cards/47fee476-25b6-40bb-afa9-d755c9a021a5",
+				ScryfallID: pgtype.UUID{
+					Bytes: uuid.MustParse("47fee476-25b6-40bb-afa9-d755c9a021a5"),
+					Valid: true,
+				},
+				ScryfallOracleID: pgtype.UUID{
+					Bytes: uuid.MustParse("4d2a465e-9ebd-4002-b6cd-e0eab08bad54"),
+					Valid: true,
+				},
+				ScryfallWebUri: "https://scryfall.com/card/apc/107/es/ultima-resistencia-(last-stand)?utm_source=api",
+				SetID: pgtype.UUID{
+					Bytes: uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
+					Valid: true,
+				},
+				TypeLine: "Sorcery",
+			},
+			ExpectedEqual: false,
+		},
 	}
 
 	for _, test := range tests {
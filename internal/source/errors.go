@@ -0,0 +1,56 @@
+package source
+
+import "fmt"
+
+type MissingFileError struct {
+	Path string
+	Err  error
+}
+
+func (e *MissingFileError) Error() string {
+	return fmt.Sprintf("scryfall file %s not found: %v", e.Path, e.Err)
+}
+
+func (e *MissingFileError) Unwrap() error {
+	return e.Err
+}
+
+type MalformedJSONError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *MalformedJSONError) Error() string {
+	return fmt.Sprintf("malformed json at byte offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *MalformedJSONError) Unwrap() error {
+	return e.Err
+}
+
+type TruncatedArrayError struct {
+	Offset int64
+}
+
+func (e *TruncatedArrayError) Error() string {
+	return fmt.Sprintf("json array truncated at byte offset %d", e.Offset)
+}
+
+// SchemaError reports a field whose value does not fit the schema we decode
+// Scryfall objects into, usually a sign that the upstream format changed.
+// Unknown fields are only an error in strict syncs, wrapping ErrUnknownField,
+// as cards are otherwise decoded into a subset of their fields and kept whole
+// in their raw JSON.
+type SchemaError struct {
+	Field  string
+	Record int
+	Err    error
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("unexpected schema for field %q in record %d: %v", e.Field, e.Record, e.Err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,127 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrUnknownField = errors.New("field not in the scryfall card schema")
+
+// knownScryfallFields are the top-level fields of Scryfall card objects, as
+// documented in https://scryfall.com/docs/api/cards. Strict syncs fail on any
+// other field, so schema changes upstream are noticed before they are needed.
+var knownScryfallFields = map[string]bool{
+	// Core
+	"arena_id":            true,
+	"cardmarket_id":       true,
+	"id":                  true,
+	"lang":                true,
+	"mtgo_foil_id":        true,
+	"mtgo_id":             true,
+	"multiverse_ids":      true,
+	"layout":              true,
+	"object":              true,
+	"oracle_id":           true,
+	"prints_search_uri":   true,
+	"rulings_uri":         true,
+	"scryfall_uri":        true,
+	"tcgplayer_etched_id": true,
+	"tcgplayer_id":        true,
+	"uri":                 true,
+
+	// Gameplay
+	"all_parts":       true,
+	"card_faces":      true,
+	"cmc":             true,
+	"color_identity":  true,
+	"color_indicator": true,
+	"colors":          true,
+	"defense":         true,
+	"edhrec_rank":     true,
+	"game_changer":    true,
+	"hand_modifier":   true,
+	"keywords":        true,
+	"legalities":      true,
+	"life_modifier":   true,
+	"loyalty":         true,
+	"mana_cost":       true,
+	"name":            true,
+	"oracle_text":     true,
+	"penny_rank":      true,
+	"power":           true,
+	"produced_mana":   true,
+	"reserved":        true,
+	"toughness":       true,
+	"type_line":       true,
+
+	// Print
+	"artist":            true,
+	"artist_ids":        true,
+	"attraction_lights": true,
+	"booster":           true,
+	"border_color":      true,
+	"card_back_id":      true,
+	"collector_number":  true,
+	"content_warning":   true,
+	"digital":           true,
+	"finishes":          true,
+	"flavor_name":       true,
+	"flavor_text":       true,
+	"foil":              true,
+	"frame":             true,
+	"frame_effects":     true,
+	"full_art":          true,
+	"games":             true,
+	"highres_image":     true,
+	"illustration_id":   true,
+	"image_status":      true,
+	"image_uris":        true,
+	"nonfoil":           true,
+	"oversized":         true,
+	"preview":           true,
+	"prices":            true,
+	"printed_name":      true,
+	"printed_text":      true,
+	"printed_type_line": true,
+	"promo":             true,
+	"promo_types":       true,
+	"purchase_uris":     true,
+	"rarity":            true,
+	"related_uris":      true,
+	"released_at":       true,
+	"reprint":           true,
+	"scryfall_set_uri":  true,
+	"security_stamp":    true,
+	"set":               true,
+	"set_id":            true,
+	"set_name":          true,
+	"set_search_uri":    true,
+	"set_type":          true,
+	"set_uri":           true,
+	"story_spotlight":   true,
+	"textless":          true,
+	"variation":         true,
+	"variation_of":      true,
+	"watermark":         true,
+}
+
+// checkKnownFields fails on the top-level fields of rawCard outside the known
+// schema, reporting the first one by name so reruns report the same field.
+func checkKnownFields(rawCard json.RawMessage, record int) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(rawCard, &fields); err != nil {
+		return &SchemaError{Record: record, Err: err}
+	}
+
+	unknown := ""
+	for field := range fields {
+		if !knownScryfallFields[field] && (unknown == "" || field < unknown) {
+			unknown = field
+		}
+	}
+	if unknown != "" {
+		return &SchemaError{Field: unknown, Record: record, Err: ErrUnknownField}
+	}
+
+	return nil
+}
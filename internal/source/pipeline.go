@@ -0,0 +1,193 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type rawScryfallCard struct {
+	record int
+	raw    json.RawMessage
+}
+
+type unpackedScryfallCard struct {
+	record        int
+	set           Set
+	printing      CardPrinting
+	quarantined   QuarantinedCard
+	isQuarantined bool
+}
+
+type decodeResult struct {
+	sets        map[uuid.UUID]Set
+	cards       map[uuid.UUID]CardPrinting
+	quarantined []QuarantinedCard
+	records     int
+	workers     int
+}
+
+// decodeScryfallCards runs one reader splitting the bulk file into raw
+// objects, a pool of workers unmarshalling, filtering and unpacking them, and
+// a merge stage. Duplicate ids are resolved by record order, so the output
+// matches decoding the file sequentially. Strict decoding also fails on fields
+// outside the known Scryfall schema.
+func decodeScryfallCards(ctx context.Context, f io.Reader, workers int, strict bool) (decodeResult, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	rawCards := make(chan rawScryfallCard, workers*4)
+	unpacked := make(chan unpackedScryfallCard, workers*4)
+	records := 0
+
+	go func() {
+		defer close(rawCards)
+
+		decoder := jsonDecoder[json.RawMessage]{}
+		err := decoder.decodeEach(f, func(record int, raw json.RawMessage) error {
+			records = record + 1
+			select {
+			case rawCards <- rawScryfallCard{record: record, raw: raw}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			fail(err)
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rawCard := range rawCards {
+				result, kept, err := unpackRawScryfallCard(rawCard, strict)
+				if err != nil {
+					fail(err)
+					return
+				}
+				if !kept {
+					continue
+				}
+
+				select {
+				case unpacked <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(unpacked)
+	}()
+
+	result := mergeUnpackedScryfallCards(unpacked)
+	if firstErr != nil {
+		return decodeResult{}, firstErr
+	}
+
+	// The parent context may have been cancelled without any stage failing
+	if err := ctx.Err(); err != nil {
+		return decodeResult{}, err
+	}
+
+	result.records = records
+	result.workers = workers
+	return result, nil
+}
+
+func unpackRawScryfallCard(rawCard rawScryfallCard, strict bool) (unpackedScryfallCard, bool, error) {
+	if strict {
+		if err := checkKnownFields(rawCard.raw, rawCard.record); err != nil {
+			return unpackedScryfallCard{}, false, err
+		}
+	}
+
+	sfCard := ScryfallCard{}
+	if err := unmarshalScryfallCard(rawCard.raw, &sfCard, rawCard.record); err != nil {
+		return unpackedScryfallCard{}, false, err
+	}
+
+	if !sfCard.isKept() {
+		return unpackedScryfallCard{}, false, nil
+	}
+
+	if quarantined, invalid := sfCard.quarantine(); invalid {
+		return unpackedScryfallCard{
+			record:        rawCard.record,
+			quarantined:   quarantined,
+			isQuarantined: true,
+		}, true, nil
+	}
+
+	set, printing := sfCard.unpack()
+	return unpackedScryfallCard{
+		record:   rawCard.record,
+		set:      set,
+		printing: printing,
+	}, true, nil
+}
+
+func mergeUnpackedScryfallCards(unpacked <-chan unpackedScryfallCard) decodeResult {
+	setMap := make(map[uuid.UUID]Set)
+	printMap := make(map[uuid.UUID]CardPrinting)
+	setRecords := make(map[uuid.UUID]int)
+	printRecords := make(map[uuid.UUID]int)
+	quarantined := make([]unpackedScryfallCard, 0)
+
+	for result := range unpacked {
+		if result.isQuarantined {
+			quarantined = append(quarantined, result)
+			continue
+		}
+
+		if record, seen := setRecords[result.set.ScryfallId]; !seen || record < result.record {
+			setMap[result.set.ScryfallId] = result.set
+			setRecords[result.set.ScryfallId] = result.record
+		}
+
+		if record, seen := printRecords[result.printing.ScryfallId]; !seen || record < result.record {
+			printMap[result.printing.ScryfallId] = result.printing
+			printRecords[result.printing.ScryfallId] = result.record
+		}
+	}
+
+	sort.Slice(quarantined, func(i, j int) bool {
+		return quarantined[i].record < quarantined[j].record
+	})
+
+	quarantinedCards := make([]QuarantinedCard, 0, len(quarantined))
+	for _, result := range quarantined {
+		quarantinedCards = append(quarantinedCards, result.quarantined)
+	}
+
+	return decodeResult{
+		sets:        setMap,
+		cards:       printMap,
+		quarantined: quarantinedCards,
+	}
+}
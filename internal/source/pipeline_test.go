@@ -0,0 +1,169 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func scryfallFixture(count int) string {
+	rawCards := make([]string, 0, count+3)
+	for i := range count {
+		lang := English
+		if i%3 == 0 {
+			lang = Spanish
+		}
+		rawCards = append(rawCards, fmt.Sprintf(
+			`{"id": "00000000-0000-4000-8000-%012d", "oracle_id": "10000000-0000-4000-8000-%012d", "name": "Card %d", "printed_name": "Carta %d", "lang": %q, "games": ["paper"], "set_id": "20000000-0000-4000-8000-%012d", "set": "s%d", "set_name": "Set %d", "collector_number": "%d", "rarity": "common", "colors": ["G"]}`,
+			i, i%50, i, i, lang, i%7, i%7, i%7, i,
+		))
+	}
+
+	rawCards = append(
+		rawCards,
+		// Not kept, so it is never validated
+		`{"id": "30000000-0000-4000-8000-000000000001", "name": "", "lang": "ja", "games": ["paper"]}`,
+		// Quarantined for an unknown rarity
+		`{"id": "30000000-0000-4000-8000-000000000002", "oracle_id": "30000000-0000-4000-8000-000000000003", "name": "Bad Card", "lang": "en", "games": ["paper"], "set_id": "30000000-0000-4000-8000-000000000004", "rarity": "ultra"}`,
+		// Duplicate of the first card, which must win over the original
+		`{"id": "00000000-0000-4000-8000-000000000000", "oracle_id": "10000000-0000-4000-8000-000000000000", "name": "Card 0 reprinted", "lang": "en", "games": ["paper"], "set_id": "20000000-0000-4000-8000-000000000000", "set": "s0", "set_name": "Set 0 renamed", "collector_number": "0", "rarity": "common"}`,
+	)
+
+	return "[" + strings.Join(rawCards, ",\n") + "]"
+}
+
+func Test_DecodeScryfallCardsMatchesSequential(t *testing.T) {
+	input := scryfallFixture(500)
+
+	rawCards, err := (&jsonDecoder[json.RawMessage]{}).decodeArray(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("decoding fixture failed with error %v", err)
+	}
+
+	sfCards := make([]ScryfallCard, 0, len(rawCards))
+	expectedQuarantined := make([]QuarantinedCard, 0)
+	for i, rawCard := range rawCards {
+		sfCard := ScryfallCard{}
+		if err := unmarshalScryfallCard(rawCard, &sfCard, i); err != nil {
+			t.Fatalf("unmarshalling fixture record %d failed with error %v", i, err)
+		}
+		if quarantined, invalid := sfCard.quarantine(); sfCard.isKept() && invalid {
+			expectedQuarantined = append(expectedQuarantined, quarantined)
+			continue
+		}
+		sfCards = append(sfCards, sfCard)
+	}
+	expectedSets, expectedCards := scryfallToSetsCards(sfCards)
+
+	for _, workers := range []int{1, 2, 8} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			result, err := decodeScryfallCards(context.Background(), bytes.NewBufferString(input), workers, false)
+			if err != nil {
+				t.Fatalf("decoding with %d workers failed with error %v", workers, err)
+			}
+
+			if result.records != len(rawCards) {
+				t.Fatalf("expected %d records read but got %d", len(rawCards), result.records)
+			}
+			if !reflect.DeepEqual(result.sets, expectedSets) {
+				t.Fatalf("expected set map %#v but got %#v", expectedSets, result.sets)
+			}
+			if !reflect.DeepEqual(result.cards, expectedCards) {
+				t.Fatalf("expected card map %#v but got %#v", expectedCards, result.cards)
+			}
+			if !reflect.DeepEqual(result.quarantined, expectedQuarantined) {
+				t.Fatalf("expected quarantined cards %#v but got %#v", expectedQuarantined, result.quarantined)
+			}
+		})
+	}
+}
+
+func Test_DecodeScryfallCardsErrors(t *testing.T) {
+	fixture := scryfallFixture(100)
+	tests := []struct {
+		name     string
+		Input    string
+		Expected any
+	}{
+		{
+			name:     "schema error in a late record",
+			Input:    strings.TrimSuffix(fixture, "]") + `, {"name": "Broken", "cmc": "five"}]`,
+			Expected: new(*SchemaError),
+		},
+		{
+			name:     "truncated array",
+			Input:    strings.TrimSuffix(fixture, "]"),
+			Expected: new(*TruncatedArrayError),
+		},
+		{
+			name:     "truncated mid record",
+			Input:    fixture[:len(fixture)/2],
+			Expected: new(*TruncatedArrayError),
+		},
+		{
+			name:     "malformed record",
+			Input:    strings.TrimSuffix(fixture, "]") + `, {"name": "Broken",, "cmc": 5}]`,
+			Expected: new(*MalformedJSONError),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeScryfallCards(context.Background(), bytes.NewBufferString(test.Input), 4, false)
+			if !errors.As(err, test.Expected) {
+				t.Fatalf("test %s expected a %T error but got %#v", test.name, test.Expected, err)
+			}
+		})
+	}
+}
+
+func Test_DecodeScryfallCardsStrict(t *testing.T) {
+	fixture := scryfallFixture(100)
+	withUnknown := strings.TrimSuffix(fixture, "]") + `, {"id": "30000000-0000-4000-8000-000000000005", "name": "New", "lang": "en", "sparkle": true, "art_series": false}]`
+
+	tests := []struct {
+		name          string
+		Input         string
+		Strict        bool
+		ExpectedField string
+	}{
+		{name: "known fields are accepted", Input: fixture, Strict: true},
+		{name: "unknown fields are ignored when not strict", Input: withUnknown},
+		{name: "unknown fields fail when strict", Input: withUnknown, Strict: true, ExpectedField: "art_series"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeScryfallCards(context.Background(), bytes.NewBufferString(test.Input), 4, test.Strict)
+			if test.ExpectedField == "" {
+				if err != nil {
+					t.Fatalf("test %s expected no error but got %v", test.name, err)
+				}
+				return
+			}
+
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || !errors.Is(err, ErrUnknownField) {
+				t.Fatalf("test %s expected an unknown field error but got %#v", test.name, err)
+			}
+			if schemaErr.Field != test.ExpectedField || schemaErr.Record != 103 {
+				t.Fatalf("test %s expected field %q in record 103 but got %q in record %d", test.name, test.ExpectedField, schemaErr.Field, schemaErr.Record)
+			}
+		})
+	}
+}
+
+func Test_DecodeScryfallCardsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := decodeScryfallCards(ctx, bytes.NewBufferString(scryfallFixture(100)), 4, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("decoding with a cancelled context should have failed with %v but got %#v", context.Canceled, err)
+	}
+}
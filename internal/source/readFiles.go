@@ -1,10 +1,15 @@
 package source
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,65 +20,159 @@ const SCRYFALL_ALL_CARDS_PATH = "./src/all-cards.json"
 type jsonDecoder[T any] struct {
 }
 
+// inputReader counts the bytes read and tells if the input ran out, so a
+// syntax error at its very end can be told apart as a truncation.
+type inputReader struct {
+	r    io.Reader
+	read int64
+	eof  bool
+}
+
+func (r *inputReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if errors.Is(err, io.EOF) {
+		r.eof = true
+	}
+
+	return n, err
+}
+
+func (r *inputReader) endsAt(offset int64) bool {
+	return r.eof && offset >= r.read
+}
+
 func (d *jsonDecoder[T]) decodeArray(f io.Reader) ([]T, error) {
 	arr := make([]T, 0)
-	decoder := json.NewDecoder(f)
+	err := d.decodeEach(f, func(_ int, elem T) error {
+		arr = append(arr, elem)
+		return nil
+	})
+	if err != nil {
+		return []T{}, err
+	}
+
+	return arr, nil
+}
+
+// decodeEach streams the elements of a json array to fn in order, so callers
+// don't need to hold the whole array in memory.
+func (d *jsonDecoder[T]) decodeEach(f io.Reader, fn func(int, T) error) error {
+	input := &inputReader{r: f}
+	decoder := json.NewDecoder(input)
 
 	// Remove the opening array token
-	if _, err := decoder.Token(); err != nil {
+	token, err := decoder.Token()
+	if err != nil {
 		log.Println(err)
-		return []T{}, err
+		return classifyDecodeError(decoder, input, err, 0)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		err = &MalformedJSONError{
+			Offset: decoder.InputOffset(),
+			Err:    fmt.Errorf("expected start of array, found %v", token),
+		}
+		log.Println(err)
+		return err
 	}
 
 	// Extract each structured element of the array
+	record := 0
 	for decoder.More() {
-		card := new(T)
-		if err := decoder.Decode(card); err != nil {
+		elem := new(T)
+		if err := decoder.Decode(elem); err != nil {
 			log.Println(err)
-			return []T{}, err
+			return classifyDecodeError(decoder, input, err, record)
 		}
 
-		arr = append(arr, *card)
+		if err := fn(record, *elem); err != nil {
+			return err
+		}
+		record++
 	}
 
 	// Remove the closing array token, ensuring the array is complete
 	if _, err := decoder.Token(); err != nil {
 		log.Println(err)
-		return []T{}, err
+		return classifyDecodeError(decoder, input, err, record)
 	}
 
-	return arr, nil
+	return nil
+}
+
+func classifyDecodeError(decoder *json.Decoder, input *inputReader, err error, record int) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &TruncatedArrayError{Offset: decoder.InputOffset()}
+	// The decoder reports running out of input between tokens as a syntax error
+	case errors.As(err, &syntaxErr) && input.endsAt(syntaxErr.Offset):
+		return &TruncatedArrayError{Offset: syntaxErr.Offset}
+	case errors.As(err, &syntaxErr):
+		return &MalformedJSONError{Offset: syntaxErr.Offset, Err: err}
+	case errors.As(err, &typeErr):
+		return &SchemaError{Field: typeErr.Field, Record: record, Err: err}
+	}
+
+	return err
 }
 
-func GetScryfallData() (map[uuid.UUID]Set, map[uuid.UUID]CardPrinting, error) {
-	file, err := os.Open(SCRYFALL_ALL_CARDS_PATH)
+func GetScryfallData(
+	ctx context.Context,
+	path string,
+	workers int,
+	strict bool,
+) (map[uuid.UUID]Set, map[uuid.UUID]CardPrinting, []QuarantinedCard, error) {
+	file, err := os.Open(filepath.Clean(path))
 	if err != nil {
 		log.Println(err)
+		if errors.Is(err, fs.ErrNotExist) {
+			err = &MissingFileError{Path: path, Err: err}
+		}
+		return map[uuid.UUID]Set{}, map[uuid.UUID]CardPrinting{}, []QuarantinedCard{}, err
 	}
 
 	defer file.Close()
 
 	readStart := time.Now()
-	decoder := jsonDecoder[ScryfallCard]{}
-	sfCards, err := decoder.decodeArray(file)
+	result, err := decodeScryfallCards(ctx, file, workers, strict)
 	if err != nil {
 		log.Println(err)
-		return map[uuid.UUID]Set{}, map[uuid.UUID]CardPrinting{}, err
+		return map[uuid.UUID]Set{}, map[uuid.UUID]CardPrinting{}, []QuarantinedCard{}, err
 	}
 
 	log.Printf(
-		"Read scryfall file, found %d objects in %.3f seconds",
-		len(sfCards),
+		"Read scryfall file with %d workers, found %d objects in %.3f seconds",
+		result.workers,
+		result.records,
 		time.Since(readStart).Seconds(),
 	)
 
-	sets, cards := scryfallToSetsCards(sfCards)
+	if len(result.quarantined) > 0 {
+		log.Printf("Quarantined %d invalid Scryfall cards", len(result.quarantined))
+	}
 
 	log.Printf(
 		"Unpacked Scryfall data into %d sets and %d printings",
-		len(sets),
-		len(cards),
+		len(result.sets),
+		len(result.cards),
 	)
 
-	return sets, cards, nil
+	return result.sets, result.cards, result.quarantined, nil
+}
+
+func unmarshalScryfallCard(rawCard json.RawMessage, sfCard *ScryfallCard, record int) error {
+	if err := json.Unmarshal(rawCard, sfCard); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &SchemaError{Field: typeErr.Field, Record: record, Err: err}
+		}
+		return err
+	}
+
+	sfCard.Raw = rawCard
+	return nil
 }
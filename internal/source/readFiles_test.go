@@ -2,6 +2,9 @@ package source
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -325,3 +328,88 @@ func Test_DecodeScryfallArray(t *testing.T) {
 		})
 	}
 }
+
+func Test_DecodeScryfallArrayErrorTypes(t *testing.T) {
+	tests := []struct {
+		name          string
+		Input         string
+		ExpectedError any
+	}{
+		{
+			name:          "empty input",
+			Input:         "",
+			ExpectedError: &TruncatedArrayError{},
+		},
+		{
+			name:          "not an array",
+			Input:         `{"id": "00017e6d-bf93-4dcf-8751-f50aba77e2d2"}`,
+			ExpectedError: &MalformedJSONError{},
+		},
+		{
+			name:          "extra comma before closing array bracket",
+			Input:         `[{"name": "Shardless Agent"},]`,
+			ExpectedError: &MalformedJSONError{},
+		},
+		{
+			name:          "missing closing array bracket",
+			Input:         `[{"name": "Shardless Agent"}`,
+			ExpectedError: &TruncatedArrayError{},
+		},
+		{
+			name:          "object cut in half",
+			Input:         `[{"name": "Shardless Agent", "lang": "e`,
+			ExpectedError: &TruncatedArrayError{},
+		},
+		{
+			name:          "wrong type for field",
+			Input:         `[{"name": "Shardless Agent", "cmc": "three"}]`,
+			ExpectedError: &SchemaError{},
+		},
+	}
+
+	decoder := jsonDecoder[ScryfallCard]{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decoder.decodeArray(bytes.NewBufferString(test.Input))
+			if err == nil {
+				t.Fatalf("test %s: decoding json array %s should have failed but did not", test.name, test.Input)
+			}
+
+			var matches bool
+			switch test.ExpectedError.(type) {
+			case *TruncatedArrayError:
+				var target *TruncatedArrayError
+				matches = errors.As(err, &target)
+			case *MalformedJSONError:
+				var target *MalformedJSONError
+				matches = errors.As(err, &target)
+			case *SchemaError:
+				var target *SchemaError
+				matches = errors.As(err, &target)
+			}
+
+			if !matches {
+				t.Fatalf(
+					"test %s: decoding json array %s should have failed with %T but got %#v instead",
+					test.name,
+					test.Input,
+					test.ExpectedError,
+					err,
+				)
+			}
+		})
+	}
+}
+
+func Test_GetScryfallDataMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all-cards.json")
+
+	_, _, _, err := GetScryfallData(context.Background(), path, 1, false)
+	var missingErr *MissingFileError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("reading missing file %s should have failed with %T but got %#v instead", path, missingErr, err)
+	}
+	if missingErr.Path != path {
+		t.Fatalf("expected missing file error for path %s but got %s", path, missingErr.Path)
+	}
+}
@@ -1,6 +1,10 @@
 package source
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"slices"
 	"sort"
@@ -56,9 +60,13 @@ type ScryfallCard struct {
 	SetCode          string             `json:"set"`
 	SetName          string             `json:"set_name"`
 	TypeLine         string             `json:"type_line"`
+
+	Raw json.RawMessage `json:"-"`
 }
 
 func (sfCard *ScryfallCard) unpack() (Set, CardPrinting) {
+	raw, rawHash := sfCard.getRaw()
+
 	return Set{
 			Code:       sfCard.SetCode,
 			Name:       sfCard.SetName,
@@ -71,6 +79,8 @@ func (sfCard *ScryfallCard) unpack() (Set, CardPrinting) {
 			Name:             sfCard.Name,
 			NameSPA:          sfCard.getSpanishName(),
 			Rarity:           sfCard.Rarity,
+			Raw:              raw,
+			RawHash:          rawHash,
 			ScryfallAPIURI:   sfCard.ScryfallAPIURI,
 			ScryfallId:       sfCard.ScryfallId,
 			ScryfallOracleId: sfCard.ScryfallOracleId,
@@ -116,6 +126,29 @@ func (sfCard *ScryfallCard) getSpanishName() string {
 	return fmt.Sprintf("%s // %s", sfCard.Faces[0].PrintedName, sfCard.Faces[1].PrintedName)
 }
 
+// The raw object is compacted so formatting changes in the bulk file don't
+// register as card changes, and hashed so comparing against the db doesn't
+// need the stored JSON.
+func (sfCard *ScryfallCard) getRaw() (string, string) {
+	if len(sfCard.Raw) == 0 {
+		return "", ""
+	}
+
+	compacted := new(bytes.Buffer)
+	if err := json.Compact(compacted, sfCard.Raw); err != nil {
+		compacted.Reset()
+		compacted.Write(sfCard.Raw)
+	}
+
+	hash := sha256.Sum256(compacted.Bytes())
+	return compacted.String(), hex.EncodeToString(hash[:])
+}
+
+func (sfCard *ScryfallCard) isKept() bool {
+	return slices.Contains(sfCard.Games, GamePaper) &&
+		(sfCard.LanguageCode == English || sfCard.LanguageCode == Spanish)
+}
+
 func scryfallToSetsCards(
 	sfCards []ScryfallCard,
 ) (map[uuid.UUID]Set, map[uuid.UUID]CardPrinting) {
@@ -123,11 +156,7 @@ func scryfallToSetsCards(
 	printMap := make(map[uuid.UUID]CardPrinting)
 
 	for _, sfCard := range sfCards {
-		if !slices.Contains(sfCard.Games, GamePaper) {
-			continue
-		}
-
-		if sfCard.LanguageCode != English && sfCard.LanguageCode != Spanish {
+		if !sfCard.isKept() {
 			continue
 		}
 
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -674,3 +675,50 @@ func Test_ToSetsCards(t *testing.T) {
 		t.Fatalf("expected card map %#v but got %#v", expectedCardMap, extractedCardMap)
 	}
 }
+
+func Test_GetRaw(t *testing.T) {
+	tests := []struct {
+		name        string
+		Input       ScryfallCard
+		ExpectedRaw string
+	}{
+		{
+			name:        "no raw json",
+			Input:       ScryfallCard{},
+			ExpectedRaw: "",
+		},
+		{
+			name: "indented raw json",
+			Input: ScryfallCard{
+				Raw: json.RawMessage(`{
+					"name": "Cromat",
+					"colors": ["B", "G"]
+				}`),
+			},
+			ExpectedRaw: `{"name":"Cromat","colors":["B","G"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, rawHash := test.Input.getRaw()
+			if raw != test.ExpectedRaw {
+				t.Fatalf("test %s expected raw json %s but got %s", test.name, test.ExpectedRaw, raw)
+			}
+			if raw == "" && rawHash != "" {
+				t.Fatalf("test %s expected no raw hash but got %s", test.name, rawHash)
+			}
+			if raw != "" && len(rawHash) != 64 {
+				t.Fatalf("test %s expected a sha256 hex hash but got %s", test.name, rawHash)
+			}
+		})
+	}
+
+	first := ScryfallCard{Raw: json.RawMessage(`{"name": "Cromat"}`)}
+	second := ScryfallCard{Raw: json.RawMessage(`{"name":"Cromat"}`)}
+	_, firstHash := first.getRaw()
+	_, secondHash := second.getRaw()
+	if firstHash != secondHash {
+		t.Fatalf("expected equal hashes for differently formatted raw json but got %s and %s", firstHash, secondHash)
+	}
+}
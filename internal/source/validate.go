@@ -0,0 +1,77 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/sqlc"
+)
+
+// Rarities are the rarities of Scryfall cards, in Scryfall's order from common
+// to bonus.
+var Rarities = []Rarity{Common, Uncommon, Rare, Special, Mythic, Bonus}
+
+type QuarantinedCard struct {
+	Raw        json.RawMessage
+	Reason     string
+	ScryfallId uuid.UUID
+}
+
+func (q *QuarantinedCard) ToDbInsertQuarantinedCard(now time.Time) sqlc.InsertQuarantinedCardsParams {
+	return sqlc.InsertQuarantinedCardsParams{
+		ScryfallID: pgtype.UUID{
+			Bytes: q.ScryfallId,
+			Valid: q.ScryfallId != uuid.Nil,
+		},
+		Reason: q.Reason,
+		Raw:    string(q.Raw),
+		CreatedAt: pgtype.Timestamp{
+			Time:  now,
+			Valid: true,
+		},
+	}
+}
+
+func (sfCard *ScryfallCard) validate() []string {
+	reasons := make([]string, 0)
+
+	if sfCard.ScryfallId == uuid.Nil {
+		reasons = append(reasons, "missing scryfall id")
+	}
+	if sfCard.ScryfallOracleId == uuid.Nil {
+		reasons = append(reasons, "missing oracle id")
+	}
+	if sfCard.ScryfallSetId == uuid.Nil {
+		reasons = append(reasons, "missing set id")
+	}
+	if strings.TrimSpace(sfCard.Name) == "" {
+		reasons = append(reasons, "empty name")
+	}
+	if len(sfCard.Faces) == 1 {
+		reasons = append(reasons, "card faces has a single element")
+	}
+	if !slices.Contains(Rarities, sfCard.Rarity) {
+		reasons = append(reasons, fmt.Sprintf("unknown rarity %q", sfCard.Rarity))
+	}
+
+	return reasons
+}
+
+func (sfCard *ScryfallCard) quarantine() (QuarantinedCard, bool) {
+	reasons := sfCard.validate()
+	if len(reasons) == 0 {
+		return QuarantinedCard{}, false
+	}
+
+	return QuarantinedCard{
+		Raw:        sfCard.Raw,
+		Reason:     strings.Join(reasons, "; "),
+		ScryfallId: sfCard.ScryfallId,
+	}, true
+}
@@ -0,0 +1,120 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func Test_ValidateScryfallCards(t *testing.T) {
+	validCard := ScryfallCard{
+		CollectorNumber:  "94",
+		LanguageCode:     English,
+		Name:             "Cromat",
+		Rarity:           Rare,
+		ScryfallId:       uuid.MustParse("7d9e0a23-d2a8-40a6-9076-ed6fb539141b"),
+		ScryfallOracleId: uuid.MustParse("376601b6-fe51-4e2d-8ec6-98f965d649a3"),
+		ScryfallSetId:    uuid.MustParse("e4e00913-d08d-4899-86ea-5cf631e09ce0"),
+		Games:            []string{GamePaper},
+		Raw:              json.RawMessage(`{"name": "Cromat"}`),
+	}
+
+	tests := []struct {
+		name           string
+		Input          ScryfallCard
+		ExpectedValid  bool
+		ExpectedReason string
+	}{
+		{
+			name:          "valid card",
+			Input:         validCard,
+			ExpectedValid: true,
+		},
+		{
+			name: "empty name",
+			Input: func() ScryfallCard {
+				card := validCard
+				card.Name = " "
+				return card
+			}(),
+			ExpectedReason: "empty name",
+		},
+		{
+			name: "nil oracle id",
+			Input: func() ScryfallCard {
+				card := validCard
+				card.ScryfallOracleId = uuid.Nil
+				return card
+			}(),
+			ExpectedReason: "missing oracle id",
+		},
+		{
+			name: "single face",
+			Input: func() ScryfallCard {
+				card := validCard
+				card.Faces = []ScryfallCardFace{{Colors: []Color{Blue}}}
+				return card
+			}(),
+			ExpectedReason: "card faces has a single element",
+		},
+		{
+			name: "unknown rarity and empty name",
+			Input: func() ScryfallCard {
+				card := validCard
+				card.Name = ""
+				card.Rarity = "legendary"
+				return card
+			}(),
+			ExpectedReason: `empty name; unknown rarity "legendary"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quarantined, invalid := test.Input.quarantine()
+			if test.ExpectedValid {
+				if invalid {
+					t.Fatalf("test %s expected card %#v to be valid but got quarantine %#v", test.name, test.Input, quarantined)
+				}
+				return
+			}
+
+			if !invalid {
+				t.Fatalf("test %s expected card %#v to be quarantined but it was kept", test.name, test.Input)
+			}
+
+			expected := QuarantinedCard{
+				Raw:        test.Input.Raw,
+				Reason:     test.ExpectedReason,
+				ScryfallId: test.Input.ScryfallId,
+			}
+			if !reflect.DeepEqual(quarantined, expected) {
+				t.Fatalf("test %s expected quarantined card %#v but got %#v", test.name, expected, quarantined)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalScryfallCard(t *testing.T) {
+	rawCard := json.RawMessage(`{"name": "Cromat", "lang": "en"}`)
+	sfCard := ScryfallCard{}
+	if err := unmarshalScryfallCard(rawCard, &sfCard, 0); err != nil {
+		t.Fatalf("unmarshalling %s failed with error %v", rawCard, err)
+	}
+	if sfCard.Name != "Cromat" || string(sfCard.Raw) != string(rawCard) {
+		t.Fatalf("expected card Cromat holding its raw json %s but got %#v", rawCard, sfCard)
+	}
+
+	rawCard = json.RawMessage(`{"name": "Last Stand", "cmc": "five"}`)
+	err := unmarshalScryfallCard(rawCard, &ScryfallCard{}, 1)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("unmarshalling %s should have failed with %T but got %#v", rawCard, schemaErr, err)
+	}
+	if schemaErr.Field != "cmc" || schemaErr.Record != 1 {
+		t.Fatalf("expected schema error on field cmc of record 1 but got %#v", schemaErr)
+	}
+}
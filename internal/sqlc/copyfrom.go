@@ -44,6 +44,8 @@ func (r iteratorForInsertCards) Values() ([]interface{}, error) {
 		r.rows[0].ScryfallOracleID,
 		r.rows[0].CreatedAt,
 		r.rows[0].UpdatedAt,
+		r.rows[0].Raw,
+		r.rows[0].RawHash,
 	}, nil
 }
 
@@ -52,7 +54,42 @@ func (r iteratorForInsertCards) Err() error {
 }
 
 func (q *Queries) InsertCards(ctx context.Context, arg []InsertCardsParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"cards"}, []string{"scryfall_id", "set_id", "name", "collector_number", "color_identity", "colors", "language_code", "spanish_name", "rarity", "type_line", "scryfall_api_uri", "scryfall_web_uri", "scryfall_oracle_id", "created_at", "updated_at"}, &iteratorForInsertCards{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"cards_staging"}, []string{"scryfall_id", "set_id", "name", "collector_number", "color_identity", "colors", "language_code", "spanish_name", "rarity", "type_line", "scryfall_api_uri", "scryfall_web_uri", "scryfall_oracle_id", "created_at", "updated_at", "raw", "raw_hash"}, &iteratorForInsertCards{rows: arg})
+}
+
+// iteratorForInsertQuarantinedCards implements pgx.CopyFromSource.
+type iteratorForInsertQuarantinedCards struct {
+	rows                 []InsertQuarantinedCardsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForInsertQuarantinedCards) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForInsertQuarantinedCards) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].ScryfallID,
+		r.rows[0].Reason,
+		r.rows[0].Raw,
+		r.rows[0].CreatedAt,
+	}, nil
+}
+
+func (r iteratorForInsertQuarantinedCards) Err() error {
+	return nil
+}
+
+func (q *Queries) InsertQuarantinedCards(ctx context.Context, arg []InsertQuarantinedCardsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"quarantined_cards"}, []string{"scryfall_id", "reason", "raw", "created_at"}, &iteratorForInsertQuarantinedCards{rows: arg})
 }
 
 // iteratorForInsertSets implements pgx.CopyFromSource.
@@ -88,5 +125,5 @@ func (r iteratorForInsertSets) Err() error {
 }
 
 func (q *Queries) InsertSets(ctx context.Context, arg []InsertSetsParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"sets"}, []string{"scryfall_id", "code", "name", "created_at", "updated_at"}, &iteratorForInsertSets{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"sets_staging"}, []string{"scryfall_id", "code", "name", "created_at", "updated_at"}, &iteratorForInsertSets{rows: arg})
 }
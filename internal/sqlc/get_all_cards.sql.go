@@ -7,25 +7,61 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const getAllCards = `-- name: GetAllCards :many
 SELECT
-    scryfall_id, set_id, name, collector_number, color_identity, colors, language_code, spanish_name, rarity, type_line, scryfall_api_uri, scryfall_web_uri, scryfall_oracle_id, created_at, updated_at
+    scryfall_id,
+    set_id,
+    name,
+    collector_number,
+    color_identity,
+    colors,
+    language_code,
+    spanish_name,
+    rarity,
+    type_line,
+    scryfall_api_uri,
+    scryfall_web_uri,
+    scryfall_oracle_id,
+    created_at,
+    updated_at,
+    raw_hash
 FROM
     cards c
 ORDER BY name ASC
 `
 
-func (q *Queries) GetAllCards(ctx context.Context) ([]Card, error) {
+type GetAllCardsRow struct {
+	ScryfallID       pgtype.UUID
+	SetID            pgtype.UUID
+	Name             string
+	CollectorNumber  string
+	ColorIdentity    pgtype.Text
+	Colors           pgtype.Text
+	LanguageCode     string
+	SpanishName      pgtype.Text
+	Rarity           pgtype.Text
+	TypeLine         string
+	ScryfallApiUri   string
+	ScryfallWebUri   string
+	ScryfallOracleID pgtype.UUID
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+	RawHash          string
+}
+
+func (q *Queries) GetAllCards(ctx context.Context) ([]GetAllCardsRow, error) {
 	rows, err := q.db.Query(ctx, getAllCards)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Card
+	var items []GetAllCardsRow
 	for rows.Next() {
-		var i Card
+		var i GetAllCardsRow
 		if err := rows.Scan(
 			&i.ScryfallID,
 			&i.SetID,
@@ -42,6 +78,7 @@ func (q *Queries) GetAllCards(ctx context.Context) ([]Card, error) {
 			&i.ScryfallOracleID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RawHash,
 		); err != nil {
 			return nil, err
 		}
@@ -25,4 +25,6 @@ type InsertCardsParams struct {
 	ScryfallOracleID pgtype.UUID
 	CreatedAt        pgtype.Timestamp
 	UpdatedAt        pgtype.Timestamp
+	Raw              string
+	RawHash          string
 }
@@ -24,6 +24,88 @@ type Card struct {
 	ScryfallOracleID pgtype.UUID
 	CreatedAt        pgtype.Timestamp
 	UpdatedAt        pgtype.Timestamp
+	Raw              string
+	RawHash          string
+}
+
+type CardPrice struct {
+	ScryfallID     pgtype.UUID
+	PriceDate      pgtype.Date
+	UsdCents       pgtype.Int8
+	UsdFoilCents   pgtype.Int8
+	UsdEtchedCents pgtype.Int8
+	EurCents       pgtype.Int8
+	EurFoilCents   pgtype.Int8
+}
+
+type CardsStaging struct {
+	ScryfallID       pgtype.UUID
+	SetID            pgtype.UUID
+	Name             string
+	CollectorNumber  string
+	ColorIdentity    pgtype.Text
+	Colors           pgtype.Text
+	LanguageCode     string
+	SpanishName      pgtype.Text
+	Rarity           pgtype.Text
+	TypeLine         string
+	ScryfallApiUri   string
+	ScryfallWebUri   string
+	ScryfallOracleID pgtype.UUID
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+	Raw              string
+	RawHash          string
+}
+
+type Collection struct {
+	ID        int64
+	Name      string
+	CreatedAt pgtype.Timestamp
+	Owner     pgtype.Text
+}
+
+type CollectionItem struct {
+	ID                 int64
+	CollectionID       int64
+	ScryfallID         pgtype.UUID
+	Quantity           int32
+	Finish             string
+	Condition          string
+	LanguageCode       string
+	AcquiredPriceCents pgtype.Int8
+	AcquiredCurrency   pgtype.Text
+	AcquiredOn         pgtype.Date
+	CreatedAt          pgtype.Timestamp
+	UpdatedAt          pgtype.Timestamp
+}
+
+type Deck struct {
+	ID        int64
+	Name      string
+	Format    string
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+type DeckCard struct {
+	ID               int64
+	DeckID           int64
+	Zone             string
+	Quantity         int32
+	ScryfallOracleID pgtype.UUID
+	ScryfallID       pgtype.UUID
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+	Finish           string
+}
+
+type QuarantinedCard struct {
+	ID         int64
+	ScryfallID pgtype.UUID
+	Reason     string
+	Raw        string
+	CreatedAt  pgtype.Timestamp
 }
 
 type Set struct {
@@ -33,3 +115,34 @@ type Set struct {
 	CreatedAt  pgtype.Timestamp
 	UpdatedAt  pgtype.Timestamp
 }
+
+type SetsStaging struct {
+	ScryfallID pgtype.UUID
+	Code       string
+	Name       string
+	CreatedAt  pgtype.Timestamp
+	UpdatedAt  pgtype.Timestamp
+}
+
+type SyncRun struct {
+	ID               int64
+	StartedAt        pgtype.Timestamp
+	FinishedAt       pgtype.Timestamp
+	SetsInserted     int32
+	SetsUpdated      int32
+	CardsInserted    int32
+	CardsUpdated     int32
+	CardsQuarantined int32
+	PricesRecorded   int32
+}
+
+type Want struct {
+	ID               int64
+	Owner            string
+	Quantity         int32
+	ScryfallOracleID pgtype.UUID
+	ScryfallID       pgtype.UUID
+	Finish           pgtype.Text
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+}
@@ -1,7 +1,7 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
 //   sqlc v1.29.0
-// source: get_all_cards_with_sets.sql
+// source: search_cards.sql
 
 package sqlc
 
@@ -11,18 +11,41 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const getAllCardsWithSets = `-- name: GetAllCardsWithSets :many
+const searchCards = `-- name: SearchCards :many
 SELECT
-    c.scryfall_id, c.set_id, c.name, c.collector_number, c.color_identity, c.colors, c.language_code, c.spanish_name, c.rarity, c.type_line, c.scryfall_api_uri, c.scryfall_web_uri, c.scryfall_oracle_id, c.created_at, c.updated_at,
+    c.scryfall_id, c.set_id, c.name, c.collector_number, c.color_identity, c.colors, c.language_code, c.spanish_name, c.rarity, c.type_line, c.scryfall_api_uri, c.scryfall_web_uri, c.scryfall_oracle_id, c.created_at, c.updated_at, c.raw, c.raw_hash,
     s.code set_code,
-    s.name set_name
+    s.name set_name,
+    GREATEST(
+        word_similarity(search_normalize($1), search_normalize(c.name)),
+        COALESCE(word_similarity(search_normalize($1), search_normalize(c.spanish_name)), 0)
+    )::REAL rank
 FROM
     cards c
 INNER JOIN sets s ON c.set_id = s.scryfall_id
-ORDER BY set_code, set_name ASC
+WHERE
+    search_normalize($1) <% search_normalize(c.name)
+    OR search_normalize($1) <% search_normalize(c.spanish_name)
+ORDER BY
+    rank DESC,
+    GREATEST(
+        similarity(search_normalize($1), search_normalize(c.name)),
+        COALESCE(similarity(search_normalize($1), search_normalize(c.spanish_name)), 0)
+    ) DESC,
+    c.name ASC,
+    s.code ASC,
+    c.collector_number ASC,
+    c.language_code ASC
+LIMIT $3 OFFSET $2
 `
 
-type GetAllCardsWithSetsRow struct {
+type SearchCardsParams struct {
+	Query        string
+	ResultOffset int32
+	MaxResults   int32
+}
+
+type SearchCardsRow struct {
 	ScryfallID       pgtype.UUID
 	SetID            pgtype.UUID
 	Name             string
@@ -38,19 +61,22 @@ type GetAllCardsWithSetsRow struct {
 	ScryfallOracleID pgtype.UUID
 	CreatedAt        pgtype.Timestamp
 	UpdatedAt        pgtype.Timestamp
+	Raw              string
+	RawHash          string
 	SetCode          string
 	SetName          string
+	Rank             float32
 }
 
-func (q *Queries) GetAllCardsWithSets(ctx context.Context) ([]GetAllCardsWithSetsRow, error) {
-	rows, err := q.db.Query(ctx, getAllCardsWithSets)
+func (q *Queries) SearchCards(ctx context.Context, arg SearchCardsParams) ([]SearchCardsRow, error) {
+	rows, err := q.db.Query(ctx, searchCards, arg.Query, arg.ResultOffset, arg.MaxResults)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAllCardsWithSetsRow
+	var items []SearchCardsRow
 	for rows.Next() {
-		var i GetAllCardsWithSetsRow
+		var i SearchCardsRow
 		if err := rows.Scan(
 			&i.ScryfallID,
 			&i.SetID,
@@ -67,8 +93,11 @@ func (q *Queries) GetAllCardsWithSets(ctx context.Context) ([]GetAllCardsWithSet
 			&i.ScryfallOracleID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Raw,
+			&i.RawHash,
 			&i.SetCode,
 			&i.SetName,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -25,7 +25,9 @@ SET set_id = $2,
     scryfall_api_uri = $11,
     scryfall_web_uri = $12,
     scryfall_oracle_id = $13,
-    updated_at = $14
+    updated_at = $14,
+    raw = $15,
+    raw_hash = $16
 WHERE scryfall_id = $1
 `
 
@@ -44,6 +46,8 @@ type UpdateCardParams struct {
 	ScryfallWebUri   string
 	ScryfallOracleID pgtype.UUID
 	UpdatedAt        pgtype.Timestamp
+	Raw              string
+	RawHash          string
 }
 
 func (q *Queries) UpdateCard(ctx context.Context, arg UpdateCardParams) error {
@@ -62,6 +66,8 @@ func (q *Queries) UpdateCard(ctx context.Context, arg UpdateCardParams) error {
 		arg.ScryfallWebUri,
 		arg.ScryfallOracleID,
 		arg.UpdatedAt,
+		arg.Raw,
+		arg.RawHash,
 	)
 	return err
 }
@@ -0,0 +1,191 @@
+package trade
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Want is a number of copies of a card someone is after, in any printing
+// unless ScryfallID is set and in any finish unless Finish is.
+type Want struct {
+	ID         int64
+	OracleID   uuid.UUID
+	ScryfallID uuid.UUID
+	Finish     string
+	Quantity   int
+}
+
+func (w Want) byPrinting() bool {
+	return w.ScryfallID != uuid.Nil
+}
+
+func (w Want) matches(item Item) bool {
+	if w.byPrinting() && item.ScryfallID != w.ScryfallID {
+		return false
+	}
+	if w.Finish != "" && item.Finish != w.Finish {
+		return false
+	}
+
+	return item.OracleID == w.OracleID
+}
+
+// Item is a lot of copies someone owns and could give away.
+type Item struct {
+	ID              int64
+	OracleID        uuid.UUID
+	ScryfallID      uuid.UUID
+	Name            string
+	SetCode         string
+	CollectorNumber string
+	LanguageCode    string
+	Finish          string
+	Condition       string
+	Quantity        int
+	// PriceCents is the price of a copy in the trade's currency, invalid if
+	// it has none
+	PriceCents pgtype.Int8
+}
+
+// Offer is a number of copies of an item given for a want.
+type Offer struct {
+	WantID   int64
+	Item     Item
+	Quantity int
+}
+
+// Side is everything one owner gives the other.
+type Side struct {
+	From   string
+	To     string
+	Offers []Offer
+	Copies int
+	// Value is the value in cents of the priced copies, Unpriced counts the
+	// rest
+	Value    int64
+	Unpriced int
+}
+
+func NewSide(from string, to string, offers []Offer) Side {
+	side := Side{From: from, To: to, Offers: offers}
+	for _, offer := range offers {
+		side.Copies += offer.Quantity
+		if offer.Item.PriceCents.Valid {
+			side.Value += offer.Item.PriceCents.Int64 * int64(offer.Quantity)
+		} else {
+			side.Unpriced += offer.Quantity
+		}
+	}
+
+	return side
+}
+
+// byPrice orders items cheapest first, the unpriced ones last.
+func byPrice(a, b Item) int {
+	switch {
+	case a.PriceCents.Valid && !b.PriceCents.Valid:
+		return -1
+	case !a.PriceCents.Valid && b.PriceCents.Valid:
+		return 1
+	}
+
+	return cmp.Or(cmp.Compare(a.PriceCents.Int64, b.PriceCents.Int64), cmp.Compare(a.ID, b.ID))
+}
+
+// Match fills the wants with copies of the items, each copy given at most
+// once. Wants for a specific printing go first, as fewer items can fill them,
+// and every want takes the cheapest copies that do.
+func Match(wants []Want, items []Item) []Offer {
+	wants = slices.Clone(wants)
+	slices.SortStableFunc(wants, func(a, b Want) int {
+		switch {
+		case a.byPrinting() && !b.byPrinting():
+			return -1
+		case !a.byPrinting() && b.byPrinting():
+			return 1
+		}
+		return 0
+	})
+
+	items = slices.Clone(items)
+	slices.SortFunc(items, byPrice)
+	left := make([]int, len(items))
+	for i, item := range items {
+		left[i] = item.Quantity
+	}
+
+	offers := []Offer{}
+	for _, want := range wants {
+		wanted := want.Quantity
+		for i, item := range items {
+			if wanted == 0 {
+				break
+			}
+			if left[i] == 0 || !want.matches(item) {
+				continue
+			}
+
+			quantity := min(wanted, left[i])
+			offers = append(offers, Offer{WantID: want.ID, Item: item, Quantity: quantity})
+			left[i] -= quantity
+			wanted -= quantity
+		}
+	}
+
+	return offers
+}
+
+// Balance trims the sides so that neither gives more value than it gets.
+// Unpriced copies are left out of both, as they can't be weighed, and the
+// side worth more keeps its most valuable copies that still fit.
+func Balance(a Side, b Side) (Side, Side) {
+	a, b = priced(a), priced(b)
+	if a.Value > b.Value {
+		return trim(a, b.Value), b
+	}
+
+	return a, trim(b, a.Value)
+}
+
+func priced(side Side) Side {
+	offers := slices.DeleteFunc(slices.Clone(side.Offers), func(offer Offer) bool {
+		return !offer.Item.PriceCents.Valid
+	})
+
+	return NewSide(side.From, side.To, offers)
+}
+
+func trim(side Side, budget int64) Side {
+	order := make([]int, len(side.Offers))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(side.Offers[j].Item.PriceCents.Int64, side.Offers[i].Item.PriceCents.Int64)
+	})
+
+	kept := make([]int, len(side.Offers))
+	for _, i := range order {
+		price := side.Offers[i].Item.PriceCents.Int64
+		quantity := side.Offers[i].Quantity
+		if price > 0 {
+			quantity = min(quantity, int(budget/price))
+		}
+
+		kept[i] = quantity
+		budget -= price * int64(quantity)
+	}
+
+	offers := []Offer{}
+	for i, offer := range side.Offers {
+		if kept[i] > 0 {
+			offer.Quantity = kept[i]
+			offers = append(offers, offer)
+		}
+	}
+
+	return NewSide(side.From, side.To, offers)
+}
@@ -0,0 +1,112 @@
+package trade
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	boltOracle  = uuid.MustParse("10000000-0000-4000-8000-000000000001")
+	shockOracle = uuid.MustParse("10000000-0000-4000-8000-000000000002")
+	boltM10     = uuid.MustParse("00000000-0000-4000-8000-000000000001")
+	boltA25     = uuid.MustParse("00000000-0000-4000-8000-000000000002")
+	shockM19    = uuid.MustParse("00000000-0000-4000-8000-000000000003")
+)
+
+func price(cents int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: cents, Valid: true}
+}
+
+var testItems = []Item{
+	{ID: 1, OracleID: boltOracle, ScryfallID: boltM10, Name: "Lightning Bolt", Finish: "nonfoil", Quantity: 2, PriceCents: price(300)},
+	{ID: 2, OracleID: boltOracle, ScryfallID: boltA25, Name: "Lightning Bolt", Finish: "nonfoil", Quantity: 1, PriceCents: price(100)},
+	{ID: 3, OracleID: boltOracle, ScryfallID: boltA25, Name: "Lightning Bolt", Finish: "foil", Quantity: 1, PriceCents: price(900)},
+	{ID: 4, OracleID: shockOracle, ScryfallID: shockM19, Name: "Shock", Finish: "nonfoil", Quantity: 4},
+}
+
+func offered(offers []Offer) map[int64]int {
+	got := map[int64]int{}
+	for _, offer := range offers {
+		got[offer.Item.ID] += offer.Quantity
+	}
+
+	return got
+}
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		wants    []Want
+		expected map[int64]int
+	}{
+		{
+			name:     "any printing takes the cheapest copies",
+			wants:    []Want{{ID: 1, OracleID: boltOracle, Quantity: 2}},
+			expected: map[int64]int{2: 1, 1: 1},
+		},
+		{
+			name: "printing wants go first",
+			wants: []Want{
+				{ID: 1, OracleID: boltOracle, Quantity: 3},
+				{ID: 2, OracleID: boltOracle, ScryfallID: boltM10, Quantity: 2},
+			},
+			expected: map[int64]int{1: 2, 2: 1, 3: 1},
+		},
+		{
+			name:     "finish",
+			wants:    []Want{{ID: 1, OracleID: boltOracle, Finish: "foil", Quantity: 4}},
+			expected: map[int64]int{3: 1},
+		},
+		{
+			name:     "unpriced copies are still offered",
+			wants:    []Want{{ID: 1, OracleID: shockOracle, Quantity: 2}},
+			expected: map[int64]int{4: 2},
+		},
+		{
+			name:     "nothing wanted",
+			wants:    []Want{{ID: 1, OracleID: uuid.New(), Quantity: 1}},
+			expected: map[int64]int{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := offered(Match(test.wants, testItems))
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Fatalf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func Test_Balance(t *testing.T) {
+	gives := NewSide("alice", "bob", Match([]Want{
+		{ID: 1, OracleID: boltOracle, Quantity: 4},
+		{ID: 2, OracleID: shockOracle, Quantity: 1},
+	}, testItems))
+	if gives.Copies != 5 || gives.Value != 1600 || gives.Unpriced != 1 {
+		t.Fatalf("expected 5 copies worth 1600 with 1 unpriced, got %+v", gives)
+	}
+
+	gets := NewSide("bob", "alice", []Offer{{WantID: 3, Item: Item{ID: 5, PriceCents: price(1000)}, Quantity: 1}})
+
+	balancedGives, balancedGets := Balance(gives, gets)
+	if expected := map[int64]int{3: 1, 2: 1}; !reflect.DeepEqual(offered(balancedGives.Offers), expected) {
+		t.Fatalf("expected alice to keep %v but got %v", expected, offered(balancedGives.Offers))
+	}
+	if balancedGives.Value != 1000 || balancedGives.Unpriced != 0 {
+		t.Fatalf("expected alice to give 1000 without unpriced copies, got %+v", balancedGives)
+	}
+	if !reflect.DeepEqual(balancedGets, gets) {
+		t.Fatalf("expected bob's side untouched, got %+v", balancedGets)
+	}
+
+	// The cheaper side gives nothing, so the other can't give anything priced
+	empty := NewSide("bob", "alice", nil)
+	if balancedGives, _ := Balance(gives, empty); balancedGives.Copies != 0 {
+		t.Fatalf("expected nothing given for nothing, got %+v", balancedGives)
+	}
+}
@@ -0,0 +1,164 @@
+package valuation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// FormatCents renders an amount in cents as a decimal, as in 12.05.
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+type writer func(w io.Writer, report Report) error
+
+var writers = map[string]writer{
+	"text": WriteText,
+	"json": WriteJSON,
+	"csv":  WriteCSV,
+}
+
+func Outputs() []string {
+	return slices.Sorted(maps.Keys(writers))
+}
+
+type UnknownOutputError struct {
+	Output string
+}
+
+func (e *UnknownOutputError) Error() string {
+	return fmt.Sprintf("unknown output %q, expected one of %s", e.Output, strings.Join(Outputs(), ", "))
+}
+
+func Write(w io.Writer, output string, report Report) error {
+	write, ok := writers[output]
+	if !ok {
+		return &UnknownOutputError{Output: output}
+	}
+
+	return write(w, report)
+}
+
+// WriteText renders the totals, the per set subtotals, the top cards and the
+// change if there is one, as tables.
+func WriteText(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "copies\t%d\n", report.Copies)
+	fmt.Fprintf(tw, "value\t%s USD\t%s EUR\n", FormatCents(report.Value.USD), FormatCents(report.Value.EUR))
+	fmt.Fprintf(tw, "unpriced copies\t%d USD\t%d EUR\n", report.UnpricedUSD, report.UnpricedEUR)
+	if change := report.Change; change != nil {
+		fmt.Fprintf(
+			tw,
+			"change since %s\t%s USD\t%s EUR\n",
+			change.Since,
+			FormatCents(change.Delta.USD),
+			FormatCents(change.Delta.EUR),
+		)
+		fmt.Fprintf(tw, "copies without history\t%d\n", change.WithoutHistory)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(tw, "SET\tCOPIES\tUSD\tEUR\tNAME")
+	for _, set := range report.Sets {
+		fmt.Fprintf(
+			tw,
+			"%s\t%d\t%s\t%s\t%s\n",
+			set.SetCode,
+			set.Copies,
+			FormatCents(set.Value.USD),
+			FormatCents(set.Value.EUR),
+			set.SetName,
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(tw, "QTY\tSET\tNUMBER\tFINISH\tUSD\tEUR\tTOTAL USD\tTOTAL EUR\tNAME")
+	for _, card := range report.Top {
+		fmt.Fprintf(
+			tw,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			card.Quantity,
+			card.SetCode,
+			card.CollectorNumber,
+			card.Finish,
+			FormatCents(card.Unit.USD),
+			FormatCents(card.Unit.EUR),
+			FormatCents(card.Total.USD),
+			FormatCents(card.Total.EUR),
+			card.Name,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func WriteJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+var csvHeader = []string{
+	"scryfall_id",
+	"name",
+	"set_code",
+	"collector_number",
+	"language",
+	"finish",
+	"quantity",
+	"unit_usd",
+	"unit_eur",
+	"total_usd",
+	"total_eur",
+}
+
+// WriteCSV renders a row per lot, with every lot rather than just the top.
+func WriteCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, card := range report.Cards {
+		err := writer.Write([]string{
+			card.ScryfallID.String(),
+			card.Name,
+			card.SetCode,
+			card.CollectorNumber,
+			card.LanguageCode,
+			card.Finish,
+			strconv.Itoa(card.Quantity),
+			FormatCents(card.Unit.USD),
+			FormatCents(card.Unit.EUR),
+			FormatCents(card.Total.USD),
+			FormatCents(card.Total.EUR),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,238 @@
+package valuation
+
+import (
+	"cmp"
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+// Prices are the prices of one copy of a printing in cents, invalid where
+// Scryfall has none.
+type Prices struct {
+	USD       pgtype.Int8
+	USDFoil   pgtype.Int8
+	USDEtched pgtype.Int8
+	EUR       pgtype.Int8
+	EURFoil   pgtype.Int8
+}
+
+// scryfallPrices is the prices object of a card's Scryfall JSON, with
+// decimal strings for amounts.
+type scryfallPrices struct {
+	Prices struct {
+		USD       *string `json:"usd"`
+		USDFoil   *string `json:"usd_foil"`
+		USDEtched *string `json:"usd_etched"`
+		EUR       *string `json:"eur"`
+		EURFoil   *string `json:"eur_foil"`
+	} `json:"prices"`
+}
+
+func cents(amount *string) pgtype.Int8 {
+	if amount == nil {
+		return pgtype.Int8{}
+	}
+
+	value, err := strconv.ParseFloat(*amount, 64)
+	if err != nil {
+		return pgtype.Int8{}
+	}
+
+	return pgtype.Int8{Int64: int64(math.Round(value * 100)), Valid: true}
+}
+
+// ParsePrices reads the prices of a card's Scryfall JSON.
+func ParsePrices(raw string) (Prices, error) {
+	var parsed scryfallPrices
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return Prices{}, err
+	}
+
+	return Prices{
+		USD:       cents(parsed.Prices.USD),
+		USDFoil:   cents(parsed.Prices.USDFoil),
+		USDEtched: cents(parsed.Prices.USDEtched),
+		EUR:       cents(parsed.Prices.EUR),
+		EURFoil:   cents(parsed.Prices.EURFoil),
+	}, nil
+}
+
+// For returns the USD and EUR prices of a copy in finish. Scryfall has no
+// EUR price for etched foils, which Cardmarket sells as foils.
+func (p Prices) For(finish string) (pgtype.Int8, pgtype.Int8) {
+	switch finish {
+	case deck.FinishFoil:
+		return p.USDFoil, p.EURFoil
+	case deck.FinishEtched:
+		return p.USDEtched, p.EURFoil
+	default:
+		return p.USD, p.EUR
+	}
+}
+
+// Item is a lot of copies of a printing being valued.
+type Item struct {
+	ScryfallID      uuid.UUID
+	Name            string
+	SetCode         string
+	SetName         string
+	CollectorNumber string
+	LanguageCode    string
+	Finish          string
+	Quantity        int
+	Prices          Prices
+	// Previous is nil if no prices were recorded for the printing by the
+	// date compared against
+	Previous *Prices
+}
+
+// Amount is a value in cents of each currency.
+type Amount struct {
+	USD int64 `json:"usd_cents"`
+	EUR int64 `json:"eur_cents"`
+}
+
+func (a Amount) add(usd pgtype.Int8, eur pgtype.Int8, quantity int) Amount {
+	if usd.Valid {
+		a.USD += usd.Int64 * int64(quantity)
+	}
+	if eur.Valid {
+		a.EUR += eur.Int64 * int64(quantity)
+	}
+
+	return a
+}
+
+type CardValue struct {
+	ScryfallID      uuid.UUID `json:"scryfall_id"`
+	Name            string    `json:"name"`
+	SetCode         string    `json:"set"`
+	CollectorNumber string    `json:"collector_number"`
+	LanguageCode    string    `json:"lang"`
+	Finish          string    `json:"finish"`
+	Quantity        int       `json:"quantity"`
+	Unit            Amount    `json:"unit"`
+	Total           Amount    `json:"total"`
+}
+
+type SetValue struct {
+	SetCode string `json:"set"`
+	SetName string `json:"set_name"`
+	Copies  int    `json:"copies"`
+	Value   Amount `json:"value"`
+}
+
+// Change compares the value of the copies priced both now and by Since, in
+// each currency separately.
+type Change struct {
+	Since    string `json:"since"`
+	Previous Amount `json:"previous"`
+	Current  Amount `json:"current"`
+	Delta    Amount `json:"delta"`
+	// WithoutHistory counts the copies with no prices recorded by Since
+	WithoutHistory int `json:"without_history"`
+}
+
+type Report struct {
+	Copies int    `json:"copies"`
+	Value  Amount `json:"value"`
+	// UnpricedUSD and UnpricedEUR count the copies with no price in the
+	// currency, left out of the value
+	UnpricedUSD int        `json:"unpriced_usd"`
+	UnpricedEUR int        `json:"unpriced_eur"`
+	Sets        []SetValue `json:"sets"`
+	// Cards holds every lot, those with the most valuable copies in USD first
+	Cards  []CardValue `json:"-"`
+	Top    []CardValue `json:"top"`
+	Change *Change     `json:"change,omitempty"`
+}
+
+// Value values items at their current prices, keeping the top lots with the
+// most valuable copies. If since is valid, the report also has the change
+// since then for the items with Previous prices.
+func Value(items []Item, top int, since pgtype.Date) Report {
+	report := Report{Sets: []SetValue{}, Cards: []CardValue{}}
+	if since.Valid {
+		report.Change = &Change{Since: since.Time.Format("2006-01-02")}
+	}
+
+	sets := map[string]*SetValue{}
+	for _, item := range items {
+		usd, eur := item.Prices.For(item.Finish)
+
+		report.Copies += item.Quantity
+		report.Value = report.Value.add(usd, eur, item.Quantity)
+		if !usd.Valid {
+			report.UnpricedUSD += item.Quantity
+		}
+		if !eur.Valid {
+			report.UnpricedEUR += item.Quantity
+		}
+
+		set, ok := sets[item.SetCode]
+		if !ok {
+			set = &SetValue{SetCode: item.SetCode, SetName: item.SetName}
+			sets[item.SetCode] = set
+		}
+		set.Copies += item.Quantity
+		set.Value = set.Value.add(usd, eur, item.Quantity)
+
+		report.Cards = append(report.Cards, CardValue{
+			ScryfallID:      item.ScryfallID,
+			Name:            item.Name,
+			SetCode:         item.SetCode,
+			CollectorNumber: item.CollectorNumber,
+			LanguageCode:    item.LanguageCode,
+			Finish:          item.Finish,
+			Quantity:        item.Quantity,
+			Unit:            Amount{}.add(usd, eur, 1),
+			Total:           Amount{}.add(usd, eur, item.Quantity),
+		})
+
+		if report.Change == nil {
+			continue
+		}
+		if item.Previous == nil {
+			report.Change.WithoutHistory += item.Quantity
+			continue
+		}
+
+		previousUSD, previousEUR := item.Previous.For(item.Finish)
+		if usd.Valid && previousUSD.Valid {
+			report.Change.Current.USD += usd.Int64 * int64(item.Quantity)
+			report.Change.Previous.USD += previousUSD.Int64 * int64(item.Quantity)
+		}
+		if eur.Valid && previousEUR.Valid {
+			report.Change.Current.EUR += eur.Int64 * int64(item.Quantity)
+			report.Change.Previous.EUR += previousEUR.Int64 * int64(item.Quantity)
+		}
+	}
+
+	if report.Change != nil {
+		report.Change.Delta = Amount{
+			USD: report.Change.Current.USD - report.Change.Previous.USD,
+			EUR: report.Change.Current.EUR - report.Change.Previous.EUR,
+		}
+	}
+
+	for _, set := range sets {
+		report.Sets = append(report.Sets, *set)
+	}
+	slices.SortFunc(report.Sets, func(a, b SetValue) int {
+		return cmp.Or(cmp.Compare(b.Value.USD, a.Value.USD), cmp.Compare(a.SetCode, b.SetCode))
+	})
+
+	slices.SortStableFunc(report.Cards, func(a, b CardValue) int {
+		return cmp.Or(cmp.Compare(b.Unit.USD, a.Unit.USD), cmp.Compare(b.Unit.EUR, a.Unit.EUR))
+	})
+	report.Top = report.Cards[:min(top, len(report.Cards))]
+
+	return report
+}
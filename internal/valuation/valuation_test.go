@@ -0,0 +1,199 @@
+package valuation
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/deck"
+)
+
+func amount(cents int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: cents, Valid: true}
+}
+
+var (
+	boltPrices = Prices{USD: amount(200), USDFoil: amount(1000), EUR: amount(150), EURFoil: amount(800)}
+	firePrices = Prices{USD: amount(50), USDEtched: amount(300), EURFoil: amount(250)}
+)
+
+var testItems = []Item{
+	{
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		Name:            "Lightning Bolt",
+		SetCode:         "m10",
+		SetName:         "Magic 2010",
+		CollectorNumber: "146",
+		LanguageCode:    "en",
+		Finish:          deck.FinishNonfoil,
+		Quantity:        3,
+		Prices:          boltPrices,
+		Previous:        &Prices{USD: amount(150), EUR: amount(100)},
+	},
+	{
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		Name:            "Lightning Bolt",
+		SetCode:         "m10",
+		SetName:         "Magic 2010",
+		CollectorNumber: "146",
+		LanguageCode:    "es",
+		Finish:          deck.FinishFoil,
+		Quantity:        1,
+		Prices:          boltPrices,
+	},
+	{
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000003"),
+		Name:            "Fire // Ice",
+		SetCode:         "mh2",
+		SetName:         "Modern Horizons 2",
+		CollectorNumber: "290",
+		LanguageCode:    "en",
+		Finish:          deck.FinishEtched,
+		Quantity:        2,
+		Prices:          firePrices,
+		Previous:        &Prices{USDEtched: amount(400)},
+	},
+	{
+		ScryfallID:      uuid.MustParse("00000000-0000-4000-8000-000000000004"),
+		Name:            "Shock",
+		SetCode:         "m19",
+		SetName:         "Core Set 2019",
+		CollectorNumber: "156",
+		LanguageCode:    "en",
+		Finish:          deck.FinishNonfoil,
+		Quantity:        4,
+		Previous:        &Prices{},
+	},
+}
+
+func Test_ParsePrices(t *testing.T) {
+	prices, err := ParsePrices(`{"prices": {"usd": "2.00", "usd_foil": "10.00", "usd_etched": null, "eur": "1.50", "eur_foil": "8"}}`)
+	if err != nil {
+		t.Fatalf("parsing failed with error %v", err)
+	}
+
+	if prices != boltPrices {
+		t.Fatalf("expected %+v but got %+v", boltPrices, prices)
+	}
+}
+
+func Test_PricesFor(t *testing.T) {
+	tests := []struct {
+		finish      string
+		expectedUSD pgtype.Int8
+		expectedEUR pgtype.Int8
+	}{
+		{finish: deck.FinishNonfoil, expectedUSD: amount(50)},
+		{finish: deck.FinishFoil, expectedEUR: amount(250)},
+		{finish: deck.FinishEtched, expectedUSD: amount(300), expectedEUR: amount(250)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.finish, func(t *testing.T) {
+			usd, eur := firePrices.For(test.finish)
+			if usd != test.expectedUSD || eur != test.expectedEUR {
+				t.Fatalf("expected %v and %v but got %v and %v", test.expectedUSD, test.expectedEUR, usd, eur)
+			}
+		})
+	}
+}
+
+func Test_Value(t *testing.T) {
+	since := pgtype.Date{Time: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	report := Value(testItems, 2, since)
+
+	if report.Copies != 10 {
+		t.Fatalf("expected 10 copies but got %d", report.Copies)
+	}
+	if expected := (Amount{USD: 2200, EUR: 1750}); report.Value != expected {
+		t.Fatalf("expected value %+v but got %+v", expected, report.Value)
+	}
+	if report.UnpricedUSD != 4 || report.UnpricedEUR != 4 {
+		t.Fatalf("expected the 4 Shocks unpriced but got %d and %d", report.UnpricedUSD, report.UnpricedEUR)
+	}
+
+	expectedSets := []SetValue{
+		{SetCode: "m10", SetName: "Magic 2010", Copies: 4, Value: Amount{USD: 1600, EUR: 1250}},
+		{SetCode: "mh2", SetName: "Modern Horizons 2", Copies: 2, Value: Amount{USD: 600, EUR: 500}},
+		{SetCode: "m19", SetName: "Core Set 2019", Copies: 4},
+	}
+	if !reflect.DeepEqual(report.Sets, expectedSets) {
+		t.Fatalf("expected sets %+v but got %+v", expectedSets, report.Sets)
+	}
+
+	if len(report.Cards) != 4 || len(report.Top) != 2 {
+		t.Fatalf("expected 4 cards and a top 2, got %+v and %+v", report.Cards, report.Top)
+	}
+	if report.Top[0].Finish != deck.FinishFoil || report.Top[1].Name != "Fire // Ice" {
+		t.Fatalf("expected the foil bolt and the etched Fire // Ice on top, got %+v", report.Top)
+	}
+
+	// The foil bolt has no history, and the Shocks and the etched Fire // Ice
+	// have no EUR prices to compare
+	expectedChange := &Change{
+		Since:          "2025-09-01",
+		Previous:       Amount{USD: 1250, EUR: 300},
+		Current:        Amount{USD: 1200, EUR: 450},
+		Delta:          Amount{USD: -50, EUR: 150},
+		WithoutHistory: 1,
+	}
+	if !reflect.DeepEqual(report.Change, expectedChange) {
+		t.Fatalf("expected change %+v but got %+v", expectedChange, report.Change)
+	}
+
+	if report := Value(testItems, 10, pgtype.Date{}); report.Change != nil || len(report.Top) != 4 {
+		t.Fatalf("expected no change and every card on top, got %+v", report)
+	}
+}
+
+func Test_Write(t *testing.T) {
+	report := Value(testItems[2:3], 1, pgtype.Date{})
+
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{
+			output: "text",
+			expected: `copies           2
+value            6.00 USD  5.00 EUR
+unpriced copies  0 USD     0 EUR
+
+SET  COPIES  USD   EUR   NAME
+mh2  2       6.00  5.00  Modern Horizons 2
+
+QTY  SET  NUMBER  FINISH  USD   EUR   TOTAL USD  TOTAL EUR  NAME
+2    mh2  290     etched  3.00  2.50  6.00       5.00       Fire // Ice
+`,
+		},
+		{
+			output: "csv",
+			expected: `scryfall_id,name,set_code,collector_number,language,finish,quantity,unit_usd,unit_eur,total_usd,total_eur
+00000000-0000-4000-8000-000000000003,Fire // Ice,mh2,290,en,etched,2,3.00,2.50,6.00,5.00
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Write(&out, test.output, report); err != nil {
+				t.Fatalf("writing failed with error %v", err)
+			}
+
+			if out.String() != test.expected {
+				t.Fatalf("expected\n%s\nbut got\n%s", test.expected, out.String())
+			}
+		})
+	}
+
+	var outputErr *UnknownOutputError
+	if err := Write(&bytes.Buffer{}, "xml", report); !errors.As(err, &outputErr) {
+		t.Fatalf("expected UnknownOutputError but got %v", err)
+	}
+}
@@ -2,31 +2,124 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/jackc/pgx/v5"
 	"github.com/joho/godotenv"
 
 	"FedeAbella/mtgdb/internal/db"
-	"FedeAbella/mtgdb/internal/sqlc"
+	"FedeAbella/mtgdb/internal/migrations"
+	"FedeAbella/mtgdb/internal/source"
 )
 
-func main() {
+// Exit codes let wrapper scripts tell apart why a run failed.
+const (
+	exitOK = iota
+	exitError
+	exitMissingFile
+	exitMalformedJSON
+	exitTruncatedArray
+	exitSchemaMismatch
+	exitCancelled
+	exitSyncInProgress
+	exitOutdatedSchema
+	exitUsage
+	exitInvalidDeck
+	exitUnresolvedCards
+	exitUnknownField
+)
 
-	_ = godotenv.Load()
-	conn, err := pgx.Connect(context.Background(), os.Getenv("GO_DB_URL"))
-	if err != nil {
-		log.Fatal(err)
+const usage = `usage:
+  mtgdb [sync] [flags]                 sync the scryfall bulk file into the db
+  mtgdb migrate up|down|status|reset   manage the db schema
+  mtgdb search [flags] "card name"     search printings by english or spanish name
+  mtgdb serve [flags]                  serve the read-only rest api
+  mtgdb collection COMMAND [flags]     manage collections of owned printings
+  mtgdb deck COMMAND [flags]           manage and validate decklists
+  mtgdb trade COMMAND [flags]          manage want lists and match trades
+  mtgdb export FORMAT [flags] FILE     export the card db to a file`
+
+func exitCode(err error) int {
+	var missingErr *source.MissingFileError
+	var malformedErr *source.MalformedJSONError
+	var truncatedErr *source.TruncatedArrayError
+	var schemaErr *source.SchemaError
+	var outdatedErr *migrations.OutdatedSchemaError
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return exitCancelled
+	case errors.Is(err, db.ErrSyncInProgress):
+		return exitSyncInProgress
+	case errors.As(err, &outdatedErr):
+		return exitOutdatedSchema
+	case errors.As(err, &missingErr):
+		return exitMissingFile
+	case errors.As(err, &malformedErr):
+		return exitMalformedJSON
+	case errors.As(err, &truncatedErr):
+		return exitTruncatedArray
+	case errors.Is(err, source.ErrUnknownField):
+		return exitUnknownField
+	case errors.As(err, &schemaErr):
+		return exitSchemaMismatch
 	}
-	defer conn.Close(context.Background())
 
-	db := db.DbConf{
-		Conn:    conn,
-		Queries: sqlc.New(conn),
+	return exitError
+}
+
+// Cancelling the context on SIGINT/SIGTERM or on timeout rolls back any open
+// transaction instead of leaving the run half done.
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func main() {
+	_ = godotenv.Load()
+
+	// Running without a command syncs, as cron jobs predating commands expect
+	command, args := "sync", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	if err = db.UpsertSetsAndCards(); err != nil {
-		log.Fatal(err)
+	switch command {
+	case "sync":
+		os.Exit(runSync(args))
+	case "migrate":
+		os.Exit(runMigrate(args))
+	case "search":
+		os.Exit(runSearch(args))
+	case "serve":
+		os.Exit(runServe(args))
+	case "collection":
+		os.Exit(runCollection(args))
+	case "deck":
+		os.Exit(runDeck(args))
+	case "trade":
+		os.Exit(runTrade(args))
+	case "export":
+		os.Exit(runExport(args))
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		log.Printf("unknown command %q", command)
+		os.Exit(exitUsage)
 	}
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/migrations"
+)
+
+const migrateUsage = "usage: mtgdb migrate up|down|status|reset"
+
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return exitUsage
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	sqlDb, driver, err := db.OpenSQL(os.Getenv("GO_DB_URL"))
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer sqlDb.Close()
+
+	migrator, err := migrations.New(sqlDb, driver)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	switch flags.Arg(0) {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "reset":
+		err = migrator.Reset(ctx)
+	case "status":
+		statuses, statusErr := migrator.Status(ctx)
+		for _, status := range statuses {
+			appliedAt := "-"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-8s %-20s %s\n", status.State, appliedAt, status.Source.Path)
+		}
+		err = statusErr
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return exitUsage
+	}
+
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
@@ -1,7 +1,3 @@
 #!/bin/bash
 
-source .env
-(
-    cd sql/schema || exit 1
-    goose postgres $DB_URL reset && goose postgres $DB_URL up
-)
+go run . migrate reset && go run . migrate up
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"FedeAbella/mtgdb/internal/db"
+)
+
+const searchUsage = `usage: mtgdb search [flags] "card name"`
+
+func runSearch(args []string) int {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := flags.Int("limit", 20, "maximum number of printings returned")
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	_ = flags.Parse(args)
+
+	query := strings.Join(flags.Args(), " ")
+	if strings.TrimSpace(query) == "" || *limit <= 0 {
+		fmt.Fprintln(os.Stderr, searchUsage)
+		return exitUsage
+	}
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{})
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	rows, err := store.SearchCards(ctx, query, db.Page{Limit: int32(*limit)})
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tSET\tNUMBER\tLANG\tNAME\tSPANISH NAME")
+	for _, row := range rows {
+		fmt.Fprintf(
+			w,
+			"%.2f\t%s\t%s\t%s\t%s\t%s\n",
+			row.Rank,
+			row.SetCode,
+			row.CollectorNumber,
+			row.LanguageCode,
+			row.Name,
+			row.SpanishName.String,
+		)
+	}
+	w.Flush()
+
+	return exitOK
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"FedeAbella/mtgdb/internal/api"
+	"FedeAbella/mtgdb/internal/db"
+)
+
+// In-flight requests get this long to finish once the server is told to stop.
+const shutdownTimeout = 10 * time.Second
+
+func runServe(args []string) int {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address the api listens on")
+	poolSize := flags.Int("pool-size", 0, "maximum postgres connections, pgxpool default if 0")
+	statementTimeout := flags.Duration("statement-timeout", 0, "postgres statement timeout, no limit if 0")
+	refresh := flags.Duration("autocomplete-refresh", time.Minute, "how often to check for a sync to reload autocomplete names, never if 0")
+	_ = flags.Parse(args)
+
+	ctx, cancel := commandContext(0)
+	defer cancel()
+
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{
+		MaxConns:         int32(*poolSize),
+		StatementTimeout: *statementTimeout,
+	})
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	handler := api.NewServer(store)
+	if err = handler.RefreshNames(ctx); err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	if *refresh > 0 {
+		go handler.WatchSyncs(ctx, *refresh)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("serving api on %s", *addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serveErr:
+		log.Println(err)
+		return exitError
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	if err = server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Println(err)
+		return exitError
+	}
+
+	return exitOK
+}
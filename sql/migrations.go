@@ -0,0 +1,11 @@
+package sql
+
+import "embed"
+
+const (
+	MigrationsDir       = "schema"
+	SqliteMigrationsDir = "sqlite/schema"
+)
+
+//go:embed schema/*.sql sqlite/schema/*.sql
+var Migrations embed.FS
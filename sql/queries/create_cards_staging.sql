@@ -0,0 +1,2 @@
+-- name: CreateCardsStaging :exec
+CREATE TEMP TABLE cards_staging (LIKE cards INCLUDING DEFAULTS) ON COMMIT DROP;
@@ -0,0 +1,3 @@
+-- name: CreateCollection :one
+INSERT INTO collections (name, created_at) VALUES ($1, $2)
+RETURNING *;
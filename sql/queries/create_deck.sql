@@ -0,0 +1,3 @@
+-- name: CreateDeck :one
+INSERT INTO decks (name, format, created_at, updated_at) VALUES ($1, $2, $3, $4)
+RETURNING *;
@@ -0,0 +1,2 @@
+-- name: CreateSetsStaging :exec
+CREATE TEMP TABLE sets_staging (LIKE sets INCLUDING DEFAULTS) ON COMMIT DROP;
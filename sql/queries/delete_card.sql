@@ -0,0 +1,3 @@
+-- name: DeleteCard :exec
+DELETE FROM cards
+WHERE scryfall_id = $1;
@@ -0,0 +1,3 @@
+-- name: DeleteCollectionItem :exec
+DELETE FROM collection_items
+WHERE id = $1;
@@ -0,0 +1,3 @@
+-- name: DeleteDeckCard :exec
+DELETE FROM deck_cards
+WHERE id = $1;
@@ -0,0 +1,2 @@
+-- name: DeleteQuarantinedCards :exec
+DELETE FROM quarantined_cards;
@@ -0,0 +1,3 @@
+-- name: DeleteSet :exec
+DELETE FROM sets
+WHERE scryfall_id = $1;
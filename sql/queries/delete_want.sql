@@ -0,0 +1,3 @@
+-- name: DeleteWant :exec
+DELETE FROM wants
+WHERE id = $1;
@@ -1,6 +1,21 @@
 -- name: GetAllCards :many
 SELECT
-    *
+    scryfall_id,
+    set_id,
+    name,
+    collector_number,
+    color_identity,
+    colors,
+    language_code,
+    spanish_name,
+    rarity,
+    type_line,
+    scryfall_api_uri,
+    scryfall_web_uri,
+    scryfall_oracle_id,
+    created_at,
+    updated_at,
+    raw_hash
 FROM
     cards c
 ORDER BY name ASC;
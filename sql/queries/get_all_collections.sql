@@ -0,0 +1,6 @@
+-- name: GetAllCollections :many
+SELECT
+    *
+FROM
+    collections
+ORDER BY name ASC;
@@ -0,0 +1,6 @@
+-- name: GetAllDecks :many
+SELECT
+    *
+FROM
+    decks
+ORDER BY name ASC;
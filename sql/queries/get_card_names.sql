@@ -0,0 +1,5 @@
+-- name: GetCardNames :many
+SELECT DISTINCT
+    name, spanish_name, scryfall_oracle_id
+FROM
+    cards;
@@ -0,0 +1,6 @@
+-- name: GetCardsVersion :one
+SELECT
+    count(*) AS card_count,
+    COALESCE(max(updated_at)::TEXT, '')::TEXT AS last_updated_at
+FROM
+    cards;
@@ -0,0 +1,6 @@
+-- name: GetCollectionByName :one
+SELECT
+    *
+FROM
+    collections
+WHERE name = $1;
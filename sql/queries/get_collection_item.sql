@@ -0,0 +1,6 @@
+-- name: GetCollectionItem :one
+SELECT
+    *
+FROM
+    collection_items
+WHERE id = $1;
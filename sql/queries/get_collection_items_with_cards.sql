@@ -0,0 +1,12 @@
+-- name: GetCollectionItemsWithCards :many
+SELECT
+    sqlc.embed(ci),
+    sqlc.embed(c),
+    s.code set_code,
+    s.name set_name
+FROM
+    collection_items ci
+INNER JOIN cards c ON ci.scryfall_id = c.scryfall_id
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE ci.collection_id = $1
+ORDER BY s.code ASC, c.collector_number ASC, ci.id ASC;
@@ -0,0 +1,11 @@
+-- name: GetCollectionPricesOn :many
+SELECT DISTINCT ON (p.scryfall_id)
+    p.*
+FROM
+    card_prices p
+WHERE
+    p.scryfall_id IN (SELECT ci.scryfall_id FROM collection_items ci WHERE ci.collection_id = $1)
+    AND p.price_date <= $2
+ORDER BY
+    p.scryfall_id,
+    p.price_date DESC;
@@ -0,0 +1,6 @@
+-- name: GetDeckByName :one
+SELECT
+    *
+FROM
+    decks
+WHERE name = $1;
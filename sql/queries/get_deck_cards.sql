@@ -0,0 +1,7 @@
+-- name: GetDeckCards :many
+SELECT
+    *
+FROM
+    deck_cards
+WHERE deck_id = $1
+ORDER BY id ASC;
@@ -0,0 +1,5 @@
+-- name: GetLastSyncRun :one
+SELECT *
+FROM sync_runs
+ORDER BY id DESC
+LIMIT 1;
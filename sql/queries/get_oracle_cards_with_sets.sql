@@ -0,0 +1,11 @@
+-- name: GetOracleCardsWithSets :many
+SELECT
+    sqlc.embed(c),
+    s.code set_code,
+    s.name set_name
+FROM
+    cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE c.scryfall_oracle_id = $1
+ORDER BY s.code, c.collector_number, c.language_code ASC
+LIMIT $2 OFFSET $3;
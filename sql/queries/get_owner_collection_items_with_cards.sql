@@ -0,0 +1,13 @@
+-- name: GetOwnerCollectionItemsWithCards :many
+SELECT
+    sqlc.embed(ci),
+    sqlc.embed(c),
+    s.code set_code,
+    s.name set_name
+FROM
+    collection_items ci
+INNER JOIN collections col ON ci.collection_id = col.id
+INNER JOIN cards c ON ci.scryfall_id = c.scryfall_id
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE col.owner = $1
+ORDER BY s.code ASC, c.collector_number ASC, ci.id ASC;
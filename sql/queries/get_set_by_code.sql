@@ -0,0 +1,6 @@
+-- name: GetSetByCode :one
+SELECT
+    *
+FROM
+    sets
+WHERE code = $1;
@@ -0,0 +1,14 @@
+-- name: GetSetCardsWithSets :many
+SELECT
+    sqlc.embed(c),
+    s.code set_code,
+    s.name set_name
+FROM
+    cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE s.code = $1
+ORDER BY
+    COALESCE(substring(c.collector_number FROM '^[0-9]+')::INTEGER, 0),
+    c.collector_number,
+    c.language_code ASC
+LIMIT $2 OFFSET $3;
@@ -0,0 +1,7 @@
+-- name: GetWants :many
+SELECT
+    *
+FROM
+    wants
+WHERE owner = $1
+ORDER BY id ASC;
@@ -1,5 +1,5 @@
 -- name: InsertCards :copyfrom
-INSERT INTO cards (
+INSERT INTO cards_staging (
 	scryfall_id,
 	set_id,
 	name,
@@ -14,7 +14,9 @@ INSERT INTO cards (
 	scryfall_web_uri,
 	scryfall_oracle_id,
 	created_at,
-	updated_at
+	updated_at,
+	raw,
+	raw_hash
 ) VALUES (
     $1,
 	$2,
@@ -30,5 +32,7 @@ INSERT INTO cards (
 	$12,
 	$13,
 	$14,
-	$15
+	$15,
+	$16,
+	$17
 );
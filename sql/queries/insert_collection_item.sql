@@ -0,0 +1,15 @@
+-- name: InsertCollectionItem :one
+INSERT INTO collection_items (
+    collection_id,
+    scryfall_id,
+    quantity,
+    finish,
+    condition,
+    language_code,
+    acquired_price_cents,
+    acquired_currency,
+    acquired_on,
+    created_at,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
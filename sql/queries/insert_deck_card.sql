@@ -0,0 +1,12 @@
+-- name: InsertDeckCard :one
+INSERT INTO deck_cards (
+    deck_id,
+    zone,
+    quantity,
+    scryfall_oracle_id,
+    scryfall_id,
+    created_at,
+    updated_at,
+    finish
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
@@ -0,0 +1,2 @@
+-- name: InsertQuarantinedCards :copyfrom
+INSERT INTO quarantined_cards (scryfall_id, reason, raw, created_at) VALUES ($1, $2, $3, $4);
@@ -1,2 +1,2 @@
 -- name: InsertSets :copyfrom
-INSERT INTO sets (scryfall_id, code, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5);
+INSERT INTO sets_staging (scryfall_id, code, name, created_at, updated_at) VALUES ($1, $2, $3, $4, $5);
@@ -0,0 +1,12 @@
+-- name: InsertSyncRun :one
+INSERT INTO sync_runs (
+    started_at,
+    finished_at,
+    sets_inserted,
+    sets_updated,
+    cards_inserted,
+    cards_updated,
+    cards_quarantined,
+    prices_recorded
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
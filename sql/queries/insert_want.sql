@@ -0,0 +1,11 @@
+-- name: InsertWant :one
+INSERT INTO wants (
+    owner,
+    quantity,
+    scryfall_oracle_id,
+    scryfall_id,
+    finish,
+    created_at,
+    updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
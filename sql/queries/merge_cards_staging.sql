@@ -0,0 +1,55 @@
+-- name: MergeCardsStaging :execrows
+INSERT INTO cards (
+    scryfall_id,
+    set_id,
+    name,
+    collector_number,
+    color_identity,
+    colors,
+    language_code,
+    spanish_name,
+    rarity,
+    type_line,
+    scryfall_api_uri,
+    scryfall_web_uri,
+    scryfall_oracle_id,
+    created_at,
+    updated_at,
+    raw,
+    raw_hash
+)
+SELECT
+    scryfall_id,
+    set_id,
+    name,
+    collector_number,
+    color_identity,
+    colors,
+    language_code,
+    spanish_name,
+    rarity,
+    type_line,
+    scryfall_api_uri,
+    scryfall_web_uri,
+    scryfall_oracle_id,
+    created_at,
+    updated_at,
+    raw,
+    raw_hash
+FROM cards_staging
+ON CONFLICT (scryfall_id) DO UPDATE
+SET set_id = EXCLUDED.set_id,
+    name = EXCLUDED.name,
+    collector_number = EXCLUDED.collector_number,
+    color_identity = EXCLUDED.color_identity,
+    colors = EXCLUDED.colors,
+    language_code = EXCLUDED.language_code,
+    spanish_name = EXCLUDED.spanish_name,
+    rarity = EXCLUDED.rarity,
+    type_line = EXCLUDED.type_line,
+    scryfall_api_uri = EXCLUDED.scryfall_api_uri,
+    scryfall_web_uri = EXCLUDED.scryfall_web_uri,
+    scryfall_oracle_id = EXCLUDED.scryfall_oracle_id,
+    updated_at = EXCLUDED.updated_at,
+    raw = EXCLUDED.raw,
+    raw_hash = EXCLUDED.raw_hash;
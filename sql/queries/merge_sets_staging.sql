@@ -0,0 +1,8 @@
+-- name: MergeSetsStaging :execrows
+INSERT INTO sets (scryfall_id, code, name, created_at, updated_at)
+SELECT scryfall_id, code, name, created_at, updated_at
+FROM sets_staging
+ON CONFLICT (scryfall_id) DO UPDATE
+SET code = EXCLUDED.code,
+    name = EXCLUDED.name,
+    updated_at = EXCLUDED.updated_at;
@@ -0,0 +1,28 @@
+-- name: RecordCollectionPrices :execrows
+INSERT INTO card_prices (
+    scryfall_id,
+    price_date,
+    usd_cents,
+    usd_foil_cents,
+    usd_etched_cents,
+    eur_cents,
+    eur_foil_cents
+)
+SELECT
+    c.scryfall_id,
+    sqlc.arg(price_date)::DATE,
+    round((c.raw->'prices'->>'usd')::NUMERIC * 100)::BIGINT,
+    round((c.raw->'prices'->>'usd_foil')::NUMERIC * 100)::BIGINT,
+    round((c.raw->'prices'->>'usd_etched')::NUMERIC * 100)::BIGINT,
+    round((c.raw->'prices'->>'eur')::NUMERIC * 100)::BIGINT,
+    round((c.raw->'prices'->>'eur_foil')::NUMERIC * 100)::BIGINT
+FROM
+    cards c
+WHERE
+    c.scryfall_id IN (SELECT scryfall_id FROM collection_items)
+ON CONFLICT (scryfall_id, price_date) DO UPDATE SET
+    usd_cents = EXCLUDED.usd_cents,
+    usd_foil_cents = EXCLUDED.usd_foil_cents,
+    usd_etched_cents = EXCLUDED.usd_etched_cents,
+    eur_cents = EXCLUDED.eur_cents,
+    eur_foil_cents = EXCLUDED.eur_foil_cents;
@@ -0,0 +1,26 @@
+-- name: SearchCards :many
+SELECT
+    c.*,
+    s.code set_code,
+    s.name set_name,
+    GREATEST(
+        word_similarity(search_normalize(sqlc.arg(query)), search_normalize(c.name)),
+        COALESCE(word_similarity(search_normalize(sqlc.arg(query)), search_normalize(c.spanish_name)), 0)
+    )::REAL rank
+FROM
+    cards c
+INNER JOIN sets s ON c.set_id = s.scryfall_id
+WHERE
+    search_normalize(sqlc.arg(query)) <% search_normalize(c.name)
+    OR search_normalize(sqlc.arg(query)) <% search_normalize(c.spanish_name)
+ORDER BY
+    rank DESC,
+    GREATEST(
+        similarity(search_normalize(sqlc.arg(query)), search_normalize(c.name)),
+        COALESCE(similarity(search_normalize(sqlc.arg(query)), search_normalize(c.spanish_name)), 0)
+    ) DESC,
+    c.name ASC,
+    s.code ASC,
+    c.collector_number ASC,
+    c.language_code ASC
+LIMIT sqlc.arg(max_results) OFFSET sqlc.arg(result_offset);
@@ -0,0 +1,8 @@
+-- name: AcquireSyncLock :exec
+SELECT pg_advisory_lock(4728115037281910);
+
+-- name: TryAcquireSyncLock :one
+SELECT pg_try_advisory_lock(4728115037281910);
+
+-- name: ReleaseSyncLock :one
+SELECT pg_advisory_unlock(4728115037281910);
@@ -12,5 +12,7 @@ SET set_id = $2,
     scryfall_api_uri = $11,
     scryfall_web_uri = $12,
     scryfall_oracle_id = $13,
-    updated_at = $14
+    updated_at = $14,
+    raw = $15,
+    raw_hash = $16
 WHERE scryfall_id = $1;
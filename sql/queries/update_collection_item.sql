@@ -0,0 +1,6 @@
+-- name: UpdateCollectionItem :exec
+UPDATE collection_items
+SET collection_id = $2,
+    quantity = $3,
+    updated_at = $4
+WHERE id = $1;
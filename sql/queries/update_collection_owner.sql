@@ -0,0 +1,4 @@
+-- name: UpdateCollectionOwner :exec
+UPDATE collections
+SET owner = $2
+WHERE id = $1;
@@ -0,0 +1,5 @@
+-- name: UpdateDeckCard :exec
+UPDATE deck_cards
+SET quantity = $2,
+    updated_at = $3
+WHERE id = $1;
@@ -0,0 +1,5 @@
+-- name: UpdateWant :exec
+UPDATE wants
+SET quantity = $2,
+    updated_at = $3
+WHERE id = $1;
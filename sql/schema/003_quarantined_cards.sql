@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE quarantined_cards (
+    id BIGSERIAL PRIMARY KEY,
+    scryfall_id UUID,
+    reason TEXT NOT NULL,
+    raw JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE quarantined_cards;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE cards ADD COLUMN raw JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE cards ADD COLUMN raw_hash TEXT NOT NULL DEFAULT '';
+CREATE INDEX cards_raw_idx ON cards USING GIN (raw);
+
+-- +goose Down
+DROP INDEX cards_raw_idx;
+ALTER TABLE cards DROP COLUMN raw_hash;
+ALTER TABLE cards DROP COLUMN raw;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent is only STABLE, as its dictionary can change, so index expressions
+-- go through this IMMUTABLE wrapper instead
+-- +goose StatementBegin
+CREATE FUNCTION search_normalize(value TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS $$
+    SELECT lower(public.unaccent('public.unaccent'::regdictionary, value))
+$$;
+-- +goose StatementEnd
+
+CREATE INDEX cards_name_search_idx ON cards USING GIN (search_normalize(name) gin_trgm_ops);
+CREATE INDEX cards_spanish_name_search_idx ON cards USING GIN (search_normalize(spanish_name) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX cards_spanish_name_search_idx;
+DROP INDEX cards_name_search_idx;
+DROP FUNCTION search_normalize;
@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE decks (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    format TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Every entry names its card by oracle id, and optionally the printing
+-- chosen, which like collection items can't be deleted from under it.
+CREATE TABLE deck_cards (
+    id BIGSERIAL PRIMARY KEY,
+    deck_id BIGINT NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    zone TEXT NOT NULL CHECK (zone IN ('main', 'sideboard', 'commander', 'companion', 'maybeboard')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    scryfall_oracle_id UUID NOT NULL,
+    scryfall_id UUID REFERENCES cards(scryfall_id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX deck_cards_deck_id_idx ON deck_cards (deck_id);
+CREATE INDEX cards_scryfall_oracle_id_idx ON cards (scryfall_oracle_id);
+
+-- +goose Down
+DROP INDEX cards_scryfall_oracle_id_idx;
+DROP TABLE deck_cards;
+DROP TABLE decks;
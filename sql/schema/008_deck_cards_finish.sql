@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE deck_cards ADD COLUMN finish TEXT NOT NULL DEFAULT 'nonfoil' CHECK (finish IN ('nonfoil', 'foil', 'etched'));
+
+-- +goose Down
+ALTER TABLE deck_cards DROP COLUMN finish;
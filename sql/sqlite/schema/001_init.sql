@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE TABLE sets (
+    scryfall_id TEXT PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE cards (
+    scryfall_id TEXT PRIMARY KEY,
+    set_id TEXT NOT NULL REFERENCES sets(scryfall_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    collector_number TEXT NOT NULL,
+    color_identity TEXT,
+    colors TEXT,
+    language_code TEXT NOT NULL,
+    spanish_name TEXT,
+    rarity TEXT,
+    type_line TEXT NOT NULL,
+    scryfall_api_uri TEXT NOT NULL UNIQUE,
+    scryfall_web_uri TEXT NOT NULL UNIQUE,
+    scryfall_oracle_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    raw TEXT NOT NULL DEFAULT '{}',
+    raw_hash TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX cards_set_id_idx ON cards (set_id);
+
+CREATE TABLE quarantined_cards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    scryfall_id TEXT,
+    reason TEXT NOT NULL,
+    raw TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE quarantined_cards;
+DROP INDEX cards_set_id_idx;
+DROP TABLE cards;
+DROP TABLE sets;
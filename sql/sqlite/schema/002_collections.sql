@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Items don't cascade from cards, so no printing owned can be deleted. The
+-- sync only updates cards in place, which leaves items untouched.
+CREATE TABLE collection_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    scryfall_id TEXT NOT NULL REFERENCES cards(scryfall_id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    finish TEXT NOT NULL CHECK (finish IN ('nonfoil', 'foil', 'etched')),
+    condition TEXT NOT NULL CHECK (condition IN ('M', 'NM', 'LP', 'MP', 'HP', 'DMG')),
+    language_code TEXT NOT NULL,
+    acquired_price_cents INTEGER,
+    acquired_currency TEXT CHECK (acquired_currency IN ('USD', 'EUR')),
+    acquired_on DATE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX collection_items_collection_id_idx ON collection_items (collection_id);
+CREATE INDEX collection_items_scryfall_id_idx ON collection_items (scryfall_id);
+
+-- +goose Down
+DROP INDEX collection_items_scryfall_id_idx;
+DROP INDEX collection_items_collection_id_idx;
+DROP TABLE collection_items;
+DROP TABLE collections;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE decks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    format TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Every entry names its card by oracle id, and optionally the printing
+-- chosen, which like collection items can't be deleted from under it.
+CREATE TABLE deck_cards (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    deck_id INTEGER NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    zone TEXT NOT NULL CHECK (zone IN ('main', 'sideboard', 'commander', 'companion', 'maybeboard')),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    scryfall_oracle_id TEXT NOT NULL,
+    scryfall_id TEXT REFERENCES cards(scryfall_id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX deck_cards_deck_id_idx ON deck_cards (deck_id);
+CREATE INDEX cards_scryfall_oracle_id_idx ON cards (scryfall_oracle_id);
+
+-- +goose Down
+DROP INDEX cards_scryfall_oracle_id_idx;
+DROP INDEX deck_cards_deck_id_idx;
+DROP TABLE deck_cards;
+DROP TABLE decks;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE card_prices (
+    scryfall_id TEXT NOT NULL REFERENCES cards(scryfall_id) ON DELETE CASCADE,
+    price_date DATE NOT NULL,
+    usd_cents BIGINT,
+    usd_foil_cents BIGINT,
+    usd_etched_cents BIGINT,
+    eur_cents BIGINT,
+    eur_foil_cents BIGINT,
+    PRIMARY KEY (scryfall_id, price_date)
+);
+
+-- +goose Down
+DROP TABLE card_prices;
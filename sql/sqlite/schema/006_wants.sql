@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN owner TEXT;
+
+CREATE INDEX collections_owner_idx ON collections (owner);
+
+-- Like deck cards, wants name their card by oracle id and optionally the
+-- printing, and a finish only if the owner wants that one.
+CREATE TABLE wants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    scryfall_oracle_id TEXT NOT NULL,
+    scryfall_id TEXT REFERENCES cards(scryfall_id) ON DELETE RESTRICT,
+    finish TEXT CHECK (finish IN ('nonfoil', 'foil', 'etched')),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX wants_owner_idx ON wants (owner);
+
+-- +goose Down
+DROP INDEX wants_owner_idx;
+DROP TABLE wants;
+DROP INDEX collections_owner_idx;
+ALTER TABLE collections DROP COLUMN owner;
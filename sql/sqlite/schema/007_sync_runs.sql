@@ -0,0 +1,17 @@
+-- +goose Up
+-- Every finished sync with what it wrote, so exports can tell which one their
+-- cards come from.
+CREATE TABLE sync_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    sets_inserted INTEGER NOT NULL,
+    sets_updated INTEGER NOT NULL,
+    cards_inserted INTEGER NOT NULL,
+    cards_updated INTEGER NOT NULL,
+    cards_quarantined INTEGER NOT NULL,
+    prices_recorded INTEGER NOT NULL
+);
+
+-- +goose Down
+DROP TABLE sync_runs;
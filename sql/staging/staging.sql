@@ -0,0 +1,6 @@
+-- The staging tables the Postgres store copies rows into before merging
+-- them, declared here for sqlc only. They are created per transaction by the
+-- CreateSetsStaging and CreateCardsStaging queries, and never migrated.
+CREATE TEMP TABLE sets_staging (LIKE sets INCLUDING DEFAULTS) ON COMMIT DROP;
+
+CREATE TEMP TABLE cards_staging (LIKE cards INCLUDING DEFAULTS) ON COMMIT DROP;
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"runtime"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/migrations"
+	"FedeAbella/mtgdb/internal/source"
+)
+
+func runSync(args []string) int {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	workers := flags.Int("workers", runtime.NumCPU(), "number of workers decoding and diffing the scryfall file")
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	writers := flags.Int("writers", 4, "number of card batches written to the db concurrently")
+	poolSize := flags.Int("pool-size", 0, "maximum postgres connections, pgxpool default if 0")
+	statementTimeout := flags.Duration("statement-timeout", 0, "postgres statement timeout, no limit if 0")
+	wait := flags.Bool("wait", false, "wait for a sync already in progress instead of exiting")
+	strict := flags.Bool("strict", false, "fail on card fields outside the known scryfall schema")
+	_ = flags.Parse(args)
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{
+		MaxConns:         int32(*poolSize),
+		StatementTimeout: *statementTimeout,
+	})
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	dbConf := db.DbConf{
+		Store:        store,
+		Workers:      *workers,
+		Writers:      *writers,
+		WaitForLock:  *wait,
+		StrictSchema: *strict,
+	}
+
+	summary, err := dbConf.UpsertSetsAndCards(ctx, source.SCRYFALL_ALL_CARDS_PATH)
+	if errors.Is(err, db.ErrSyncInProgress) {
+		log.Println("sync already in progress, exiting")
+		return exitCode(err)
+	}
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	log.Printf("sync finished: %s", summary)
+	return exitOK
+}
+
+func checkSchema(ctx context.Context, dbURL string) error {
+	sqlDb, driver, err := db.OpenSQL(dbURL)
+	if err != nil {
+		return err
+	}
+	defer sqlDb.Close()
+
+	migrator, err := migrations.New(sqlDb, driver)
+	if err != nil {
+		return err
+	}
+
+	return migrator.CheckCurrent(ctx)
+}
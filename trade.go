@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"FedeAbella/mtgdb/internal/db"
+	"FedeAbella/mtgdb/internal/trade"
+	"FedeAbella/mtgdb/internal/valuation"
+)
+
+const tradeUsage = `usage:
+  mtgdb trade want [-quantity N] [-oracle] [-finish FINISH] USER ID
+  mtgdb trade wants USER
+  mtgdb trade unwant [-quantity N] USER WANT_ID
+  mtgdb trade match [-balance] [-currency CURRENCY] USER OTHER
+
+users trade the copies in the collections they own, see mtgdb collection owner`
+
+var errStoreWithoutWants = errors.New("the db backend doesn't support want lists")
+
+func openWants(ctx context.Context) (*db.Wants, func(), error) {
+	if err := checkSchema(ctx, os.Getenv("GO_DB_URL")); err != nil {
+		return nil, nil, err
+	}
+
+	store, closeStore, err := db.Open(ctx, os.Getenv("GO_DB_URL"), db.PoolConf{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wantStore, ok := store.(db.WantStore)
+	if !ok {
+		closeStore()
+		return nil, nil, errStoreWithoutWants
+	}
+
+	return &db.Wants{Store: wantStore}, closeStore, nil
+}
+
+func runTrade(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, tradeUsage)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("trade "+args[0], flag.ExitOnError)
+	timeout := flags.Duration("timeout", 0, "maximum duration of the whole run, no limit if 0")
+	quantity := flags.Int("quantity", 0, "number of copies, all of them if 0 on unwant, 1 on want")
+	oracle := flags.Bool("oracle", false, "want the card by oracle id instead of a specific printing")
+	finish := flags.String("finish", "", "finish wanted, any if empty: "+strings.Join(db.Finishes, ", "))
+	balance := flags.Bool("balance", false, "trim the trade so neither side gives more value than it gets")
+	currency := flags.String("currency", "USD", "currency to value the trade in: "+strings.Join(db.Currencies, ", "))
+	_ = flags.Parse(args[1:])
+
+	ctx, cancel := commandContext(*timeout)
+	defer cancel()
+
+	wants, closeStore, err := openWants(ctx)
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+	defer closeStore()
+
+	switch {
+	case args[0] == "want" && flags.NArg() == 2:
+		err = addWant(ctx, wants, flags.Arg(0), flags.Arg(1), int32(max(*quantity, 1)), *oracle, *finish)
+	case args[0] == "wants" && flags.NArg() == 1:
+		err = listWants(ctx, wants, flags.Arg(0))
+	case args[0] == "unwant" && flags.NArg() == 2:
+		var wantID int64
+		if wantID, err = parseItemID(flags.Arg(1)); err == nil {
+			err = wants.Remove(ctx, flags.Arg(0), wantID, int32(*quantity))
+		}
+	case args[0] == "match" && flags.NArg() == 2:
+		err = matchTrade(ctx, wants, flags.Arg(0), flags.Arg(1), strings.ToUpper(*currency), *balance)
+	default:
+		fmt.Fprintln(os.Stderr, tradeUsage)
+		return exitUsage
+	}
+
+	if err != nil {
+		log.Println(err)
+		return exitCode(err)
+	}
+
+	return exitOK
+}
+
+func addWant(
+	ctx context.Context,
+	wants *db.Wants,
+	owner string,
+	id string,
+	quantity int32,
+	oracle bool,
+	finish string,
+) error {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid id %q", id)
+	}
+
+	want := db.NewWant{
+		Owner:    owner,
+		Quantity: quantity,
+		Finish:   pgtype.Text{String: strings.ToLower(finish), Valid: finish != ""},
+	}
+	if oracle {
+		want.ScryfallOracleID = pgtype.UUID{Bytes: parsed, Valid: true}
+	} else {
+		want.ScryfallID = pgtype.UUID{Bytes: parsed, Valid: true}
+	}
+
+	added, err := wants.Add(ctx, want)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("added want %d\n", added.ID)
+	return nil
+}
+
+func listWants(ctx context.Context, wants *db.Wants, owner string) error {
+	entries, err := wants.List(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tQTY\tSET\tNUMBER\tFINISH\tNAME")
+	for _, entry := range entries {
+		// Wants for any printing or finish print none
+		setCode, collectorNumber, finish := "-", "-", "-"
+		if entry.Want.ScryfallID.Valid {
+			setCode, collectorNumber = entry.Card.SetCode, entry.Card.Card.CollectorNumber
+		}
+		if entry.Want.Finish.Valid {
+			finish = entry.Want.Finish.String
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%s\n",
+			entry.Want.ID,
+			entry.Want.Quantity,
+			setCode,
+			collectorNumber,
+			finish,
+			entry.Card.Card.Name,
+		)
+	}
+
+	return w.Flush()
+}
+
+func matchTrade(ctx context.Context, wants *db.Wants, owner string, other string, currency string, balance bool) error {
+	gives, gets, err := wants.Match(ctx, owner, other, currency, balance)
+	if err != nil {
+		return err
+	}
+
+	for i, side := range []trade.Side{gives, gets} {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := printTradeSide(side, currency); err != nil {
+			return err
+		}
+	}
+
+	// Unpriced copies are left out of the difference
+	ahead, difference := other, gives.Value-gets.Value
+	if difference < 0 {
+		ahead, difference = owner, -difference
+	}
+	if difference == 0 {
+		fmt.Println("\nthe trade is even")
+	} else {
+		fmt.Printf("\n%s gets %s %s more\n", ahead, valuation.FormatCents(difference), currency)
+	}
+
+	return nil
+}
+
+func printTradeSide(side trade.Side, currency string) error {
+	fmt.Printf(
+		"%s gives %s %d copies worth %s %s, %d unpriced\n",
+		side.From,
+		side.To,
+		side.Copies,
+		valuation.FormatCents(side.Value),
+		currency,
+		side.Unpriced,
+	)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ITEM\tQTY\tSET\tNUMBER\tLANG\tFINISH\tCOND\tPRICE\tNAME")
+	for _, offer := range side.Offers {
+		price := "-"
+		if offer.Item.PriceCents.Valid {
+			price = valuation.FormatCents(offer.Item.PriceCents.Int64)
+		}
+
+		fmt.Fprintf(
+			w,
+			"%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			offer.Item.ID,
+			offer.Quantity,
+			offer.Item.SetCode,
+			offer.Item.CollectorNumber,
+			offer.Item.LanguageCode,
+			offer.Item.Finish,
+			offer.Item.Condition,
+			price,
+			offer.Item.Name,
+		)
+	}
+
+	return w.Flush()
+}